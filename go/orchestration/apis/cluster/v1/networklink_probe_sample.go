@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// All fields in these CRDs are required, except if marked as `// +optional`
+// +kubebuilder:validation:Required
+
+// NetworkLinkProbeSample is a single measurement of the QoS of a NetworkLink, taken by a network probe.
+type NetworkLinkProbeSample struct {
+	// The time when the sample was taken.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// The measured bandwidth in kilobits per second.
+	//
+	// +kubebuilder:validation:Minimum=0
+	BandwidthKbps int64 `json:"bandwidthKbps"`
+
+	// The measured end-to-end network delay of a packet.
+	//
+	// +kubebuilder:validation:Minimum=0
+	PacketDelayMsec int32 `json:"packetDelayMsec"`
+
+	// The measured packet loss in basis points (bp).
+	// 1 bp = 0.01%
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10000
+	PacketLossBp int32 `json:"packetLossBp"`
+}
+
+// NetworkLinkMeasuredQoS describes the QoS of a NetworkLink, as aggregated from the samples of network probes.
+//
+// The values are the rolling means and variances over the recent samples.
+type NetworkLinkMeasuredQoS struct {
+	// The mean bandwidth and its variance.
+	Throughput NetworkThroughput `json:"throughput"`
+
+	// The mean packet delay and its variance.
+	Latency NetworkLatency `json:"latency"`
+
+	// The mean packet loss.
+	PacketLoss NetworkPacketLoss `json:"packetLoss"`
+
+	// The variance of PacketLoss.PacketLossBp.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	PacketLossVariance int32 `json:"packetLossVariance"`
+
+	// The number of samples that these values have been aggregated from.
+	//
+	// +kubebuilder:validation:Minimum=0
+	SampleCount int32 `json:"sampleCount"`
+}
+
+// HasFreshMeasuredQoS returns true if the status contains a MeasuredQoS, whose most recent sample
+// is not older than maxAge at the time now.
+func (me *NetworkLinkStatus) HasFreshMeasuredQoS(now time.Time, maxAge time.Duration) bool {
+	if me.MeasuredQoS == nil || me.LastProbeTime == nil {
+		return false
+	}
+	return now.Sub(me.LastProbeTime.Time) <= maxAge
+}
+
+// EffectiveQoS returns the QoS that should be assumed for the direction from NodeA to NodeB of the network link at the time now.
+//
+// If the status contains a MeasuredQoS that is not older than maxAge, the measured throughput, latency, and packet loss
+// take precedence over the ones in the spec. Otherwise, the QoS from the spec is returned.
+// The QualityClass is always taken from the spec, because it is advertised and not measured.
+func (me *NetworkLink) EffectiveQoS(now time.Time, maxAge time.Duration) *NetworkLinkQoS {
+	if !me.Status.HasFreshMeasuredQoS(now, maxAge) {
+		return &me.Spec.QoS
+	}
+
+	measured := me.Status.MeasuredQoS
+	return &NetworkLinkQoS{
+		QualityClass: me.Spec.QoS.QualityClass,
+		Throughput:   measured.Throughput,
+		Latency:      measured.Latency,
+		PacketLoss:   measured.PacketLoss,
+	}
+}
+
+// EffectiveQoSBtoA returns the QoS that should be assumed for the direction from NodeB to NodeA of the network link at the time now.
+//
+// If the spec contains a QoSBtoA, it is returned, because the probe samples describe the direction from NodeA to NodeB.
+// Otherwise, the network link is symmetric and the EffectiveQoS is returned.
+func (me *NetworkLink) EffectiveQoSBtoA(now time.Time, maxAge time.Duration) *NetworkLinkQoS {
+	if me.Spec.QoSBtoA != nil {
+		return me.Spec.QoSBtoA
+	}
+	return me.EffectiveQoS(now, maxAge)
+}
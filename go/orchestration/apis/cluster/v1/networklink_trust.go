@@ -0,0 +1,48 @@
+package v1
+
+// All fields in these CRDs are required, except if marked as `// +optional`
+// +kubebuilder:validation:Required
+
+// NetworkLinkExposure describes who has access to the network infrastructure of a NetworkLink.
+//
+// +kubebuilder:validation:Enum=private;public
+type NetworkLinkExposure string
+
+const (
+	// The network infrastructure is only accessible to the operator of the cluster, e.g., a LAN or a leased line.
+	PrivateNetworkLink NetworkLinkExposure = "private"
+
+	// The network infrastructure is shared with third parties, e.g., the public internet.
+	PublicNetworkLink NetworkLinkExposure = "public"
+)
+
+// NetworkLinkTrust describes the trust attributes of a NetworkLink.
+type NetworkLinkTrust struct {
+	// True if all traffic sent over this network link is encrypted, e.g., using a VPN tunnel.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	Encrypted bool `json:"encrypted"`
+
+	// Describes who has access to the network infrastructure of this network link.
+	//
+	// +kubebuilder:default=public
+	// +optional
+	Exposure NetworkLinkExposure `json:"exposure,omitempty"`
+
+	// True if the integrity of the network equipment along this network link has been attested.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	Attested bool `json:"attested"`
+
+	// The administrative domain (e.g., the organization or network operator) that controls this network link.
+	//
+	// +optional
+	AdministrativeDomain string `json:"administrativeDomain,omitempty"`
+}
+
+// IsPrivate returns true if the network link's Exposure is private.
+func (me *NetworkLinkTrust) IsPrivate() bool {
+	return me.Exposure == PrivateNetworkLink
+}
@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // All fields in these CRDs are required, except if marked as `// +optional`
@@ -41,11 +42,50 @@ type NetworkLinkSpec struct {
 	NodeB string `json:"nodeB"`
 
 	// The quality of service information about this network link.
+	// If QoSBtoA is set, this describes only the direction from NodeA to NodeB.
 	QoS NetworkLinkQoS `json:"qos"`
+
+	// The quality of service information about the direction from NodeB to NodeA,
+	// for network links with asymmetric QoS, e.g., cellular or satellite links, whose uplink and downlink bandwidths differ.
+	// If omitted, QoS applies to both directions.
+	//
+	// +optional
+	QoSBtoA *NetworkLinkQoS `json:"qosBtoA,omitempty"`
+
+	// The trust attributes of this network link.
+	// If omitted, the network link is considered to be unencrypted, public, and unattested.
+	//
+	// +optional
+	Trust *NetworkLinkTrust `json:"trust,omitempty"`
 }
 
-// NetworkLinkStatus defines the observed state of NetworkLink
+// NetworkLinkStatus defines the observed state of NetworkLink.
+//
+// It is populated by the NetworkLinkProbeReport controller from the samples reported by network probes.
 type NetworkLinkStatus struct {
+	// The QoS of this network link, aggregated from the RecentSamples.
+	//
+	// +optional
+	MeasuredQoS *NetworkLinkMeasuredQoS `json:"measuredQoS,omitempty"`
+
+	// The timestamp of the most recent sample.
+	//
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+
+	// The most recent samples reported by network probes, ordered from the oldest to the newest one.
+	// The number of samples is limited by the window size of the NetworkLinkProbeReport controller.
+	//
+	// +optional
+	RecentSamples []NetworkLinkProbeSample `json:"recentSamples,omitempty"`
+
+	// The UIDs of the most recently merged NetworkLinkProbeReports, ordered from the oldest to the newest one.
+	// These allow the NetworkLinkProbeReport controller to skip reports that have already been merged,
+	// e.g., if deleting a report failed after the status had been updated.
+	// The number of UIDs is limited by the window size of the NetworkLinkProbeReport controller.
+	//
+	// +optional
+	MergedProbeReports []types.UID `json:"mergedProbeReports,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Rainbow Project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// All fields in these CRDs are required, except if marked as `// +optional`
+// +kubebuilder:validation:Required
+
+// IMPORTANT: Run `make` and `make manifests` to regenerate code and YAML files after modifying this file.
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// NetworkLinkProbeReportSpec contains the samples that a network probe has measured for a NetworkLink.
+//
+// The samples describe the direction from NodeA to NodeB of the NetworkLink.
+// If the NetworkLink has no QoSBtoA, they apply to both directions.
+type NetworkLinkProbeReportSpec struct {
+	// The name of the NetworkLink that has been probed.
+	// The NetworkLink must be in the same namespace as the NetworkLinkProbeReport.
+	NetworkLink string `json:"networkLink"`
+
+	// The samples measured by the probe.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Samples []NetworkLinkProbeSample `json:"samples"`
+}
+
+// NetworkLinkProbeReportStatus defines the observed state of NetworkLinkProbeReport
+type NetworkLinkProbeReportStatus struct {
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// NetworkLinkProbeReport is used by network probes to report QoS measurements of a NetworkLink.
+//
+// The NetworkLinkProbeReport controller aggregates the samples into the status of the NetworkLink
+// and deletes the report afterwards.
+type NetworkLinkProbeReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkLinkProbeReportSpec   `json:"spec,omitempty"`
+	Status NetworkLinkProbeReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NetworkLinkProbeReportList contains a list of NetworkLinkProbeReport
+type NetworkLinkProbeReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkLinkProbeReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkLinkProbeReport{}, &NetworkLinkProbeReportList{})
+}
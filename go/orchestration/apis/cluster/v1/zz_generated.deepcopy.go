@@ -22,6 +22,7 @@ package v1
 
 import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -44,8 +45,8 @@ func (in *NetworkLink) DeepCopyInto(out *NetworkLink) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLink.
@@ -98,6 +99,136 @@ func (in *NetworkLinkList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkMeasuredQoS) DeepCopyInto(out *NetworkLinkMeasuredQoS) {
+	*out = *in
+	out.Throughput = in.Throughput
+	out.Latency = in.Latency
+	out.PacketLoss = in.PacketLoss
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkMeasuredQoS.
+func (in *NetworkLinkMeasuredQoS) DeepCopy() *NetworkLinkMeasuredQoS {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkMeasuredQoS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkProbeReport) DeepCopyInto(out *NetworkLinkProbeReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkProbeReport.
+func (in *NetworkLinkProbeReport) DeepCopy() *NetworkLinkProbeReport {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkProbeReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkLinkProbeReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkProbeReportList) DeepCopyInto(out *NetworkLinkProbeReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkLinkProbeReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkProbeReportList.
+func (in *NetworkLinkProbeReportList) DeepCopy() *NetworkLinkProbeReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkProbeReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkLinkProbeReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkProbeReportSpec) DeepCopyInto(out *NetworkLinkProbeReportSpec) {
+	*out = *in
+	if in.Samples != nil {
+		in, out := &in.Samples, &out.Samples
+		*out = make([]NetworkLinkProbeSample, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkProbeReportSpec.
+func (in *NetworkLinkProbeReportSpec) DeepCopy() *NetworkLinkProbeReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkProbeReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkProbeReportStatus) DeepCopyInto(out *NetworkLinkProbeReportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkProbeReportStatus.
+func (in *NetworkLinkProbeReportStatus) DeepCopy() *NetworkLinkProbeReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkProbeReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkProbeSample) DeepCopyInto(out *NetworkLinkProbeSample) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkProbeSample.
+func (in *NetworkLinkProbeSample) DeepCopy() *NetworkLinkProbeSample {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkProbeSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkLinkQoS) DeepCopyInto(out *NetworkLinkQoS) {
 	*out = *in
@@ -120,6 +251,16 @@ func (in *NetworkLinkQoS) DeepCopy() *NetworkLinkQoS {
 func (in *NetworkLinkSpec) DeepCopyInto(out *NetworkLinkSpec) {
 	*out = *in
 	out.QoS = in.QoS
+	if in.QoSBtoA != nil {
+		in, out := &in.QoSBtoA, &out.QoSBtoA
+		*out = new(NetworkLinkQoS)
+		**out = **in
+	}
+	if in.Trust != nil {
+		in, out := &in.Trust, &out.Trust
+		*out = new(NetworkLinkTrust)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkSpec.
@@ -135,6 +276,27 @@ func (in *NetworkLinkSpec) DeepCopy() *NetworkLinkSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkLinkStatus) DeepCopyInto(out *NetworkLinkStatus) {
 	*out = *in
+	if in.MeasuredQoS != nil {
+		in, out := &in.MeasuredQoS, &out.MeasuredQoS
+		*out = new(NetworkLinkMeasuredQoS)
+		**out = **in
+	}
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RecentSamples != nil {
+		in, out := &in.RecentSamples, &out.RecentSamples
+		*out = make([]NetworkLinkProbeSample, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MergedProbeReports != nil {
+		in, out := &in.MergedProbeReports, &out.MergedProbeReports
+		*out = make([]types.UID, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkStatus.
@@ -147,6 +309,21 @@ func (in *NetworkLinkStatus) DeepCopy() *NetworkLinkStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkLinkTrust) DeepCopyInto(out *NetworkLinkTrust) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkLinkTrust.
+func (in *NetworkLinkTrust) DeepCopy() *NetworkLinkTrust {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkLinkTrust)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkPacketLoss) DeepCopyInto(out *NetworkPacketLoss) {
 	*out = *in
@@ -0,0 +1,25 @@
+package v1
+
+// LatencyBudget declares an end-to-end latency budget over a chain of ServiceGraphNodes,
+// e.g., UserNode -> gateway -> inference -> db within 40 ms.
+//
+// The budget applies to the sum of the packet delays of the network paths between the K8s nodes,
+// on which the consecutive ServiceGraphNodes of the chain are placed.
+type LatencyBudget struct {
+
+	// The name of this latency budget.
+	Name string `json:"name"`
+
+	// The names of the ServiceGraphNodes that make up the chain, in the order in which a request traverses them.
+	//
+	// Each pair of consecutive ServiceGraphNodes should be connected by a ServiceLink, whose QosRequirements
+	// will be used for the network path between them.
+	//
+	// +kubebuilder:validation:MinItems=2
+	Chain []string `json:"chain"`
+
+	// The maximum cumulative packet delay over all hops of the chain.
+	//
+	// +kubebuilder:validation:Minimum=0
+	MaxPacketDelayMsec int32 `json:"maxPacketDelayMsec"`
+}
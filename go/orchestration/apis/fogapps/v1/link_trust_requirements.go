@@ -1,6 +1,30 @@
 package v1
 
 // LinkTrustRequirements specifies trust requirements that a NetworkLink must fulfill.
+//
+// These requirements apply to every NetworkLink along the network path between the nodes that host the
+// source and the target of a ServiceLink.
+// NetworkLinks without trust information are considered to be unencrypted, public, unattested,
+// and not to belong to any administrative domain.
 type LinkTrustRequirements struct {
-	// ToDo
+	// If true, all traffic sent over the NetworkLinks must be encrypted.
+	//
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// If true, only NetworkLinks with a private exposure may be used.
+	//
+	// +optional
+	Private bool `json:"private,omitempty"`
+
+	// If true, the integrity of the network equipment along the NetworkLinks must have been attested.
+	//
+	// +optional
+	Attested bool `json:"attested,omitempty"`
+
+	// The administrative domains that the NetworkLinks may belong to.
+	// If omitted, NetworkLinks from all administrative domains may be used.
+	//
+	// +optional
+	AdministrativeDomains []string `json:"administrativeDomains,omitempty"`
 }
@@ -33,6 +33,18 @@ type ReplicasConfig struct {
 	// +optional
 	InitialCount *int32 `json:"initialCount,omitempty"`
 
+	// The minimum number of replicas that must be scheduled together, i.e., as a gang, before any of them is bound to a node.
+	// The pods of all ServiceGraphNodes that are added or changed in the same generation of a ServiceGraph form a gang.
+	// Defaults to the same number as Min.
+	// If a ServiceGraphNode is changed, its pods are replaced by a rolling update, so at most as many replicas are used
+	// as the rolling update creates at once.
+	// For a Stateful ServiceGraphNode at most 1 replica is used, because a StatefulSet only creates
+	// its next pod once the previous one is running and ready.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GangMinMembers *int32 `json:"gangMinMembers,omitempty"`
+
 	// Specifies the type of replica set that should be used.
 	// For a ServiceGraphNode this cannot be changed after the node has been submitted to the orchestrator
 	//
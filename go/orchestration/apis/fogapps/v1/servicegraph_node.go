@@ -43,6 +43,11 @@ type ServiceGraphNode struct {
 	// +optional
 	PodLabels map[string]string `json:"labels,omitempty"`
 
+	// The annotations that should be applied to the pods, created from this ServiceGraphNode.
+	//
+	// +optional
+	PodAnnotations map[string]string `json:"annotations,omitempty"`
+
 	// Containers that are used to initialize the service upon startup.
 	//
 	// +optional
@@ -115,6 +120,13 @@ type ServiceGraphNode struct {
 	//
 	// +optional
 	GeoLocation *GeoLocation `json:"geoLocation,omitempty"`
+
+	// Configures the K8s nodes, through which the users represented by a UserNode attach to the region.
+	//
+	// This is only evaluated if NodeType is "UserNode". If omitted, the ServiceLinks from and to the UserNode are not QoS-checked.
+	//
+	// +optional
+	AttachmentPoints *UserNodeAttachmentPoints `json:"attachmentPoints,omitempty"`
 }
 
 // ServiceGraphNodeStatus describes the observed state of the resources created from a ServiceGraphNode.
@@ -144,4 +156,23 @@ type ServiceGraphNodeStatus struct {
 	//
 	// +optional
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// The metadata.Generation of the ServiceGraph, in which this ServiceGraphNode was added.
+	// This is 0 for ServiceGraphNodes that were added before the orchestrator started tracking this generation.
+	//
+	// +optional
+	CreationGeneration int64 `json:"creationGeneration,omitempty"`
+
+	// The metadata.Generation of the ServiceGraph, in which the pod template created from this ServiceGraphNode was last changed.
+	// The pods created from the pod template carry this value in their "rainbow-h2020.eu/service-graph-generation" annotation.
+	// This is 0 if the pod template has not changed since the orchestrator started tracking this generation,
+	// in which case the pods do not carry the annotation.
+	//
+	// +optional
+	PodTemplateGeneration int64 `json:"podTemplateGeneration,omitempty"`
+
+	// The hash of the pod template created from this ServiceGraphNode, which is used to detect changes of the pod template.
+	//
+	// +optional
+	PodTemplateHash string `json:"podTemplateHash,omitempty"`
 }
@@ -44,6 +44,11 @@ type ServiceGraphSpec struct {
 	// +optional
 	Links []ServiceLink `json:"links,omitempty"`
 
+	// End-to-end latency budgets for chains of ServiceGraphNodes.
+	//
+	// +optional
+	LatencyBudgets []LatencyBudget `json:"latencyBudgets,omitempty"`
+
 	// The SLOs defined for the entire application described by this ServiceGraph.
 	//
 	// +optional
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UserNodeAttachmentPoints describes the K8s nodes, through which the users represented by a UserNode
+// attach to the region, e.g., the nodes of a 5G base station or of a geographic area.
+//
+// The attachment points are the union of the nodes listed in NodeNames and the nodes selected by NodeSelector.
+// When evaluating a ServiceLink from (or to) a UserNode, the attachment points are treated like the K8s nodes,
+// on which the pods of a ServiceNode have been placed, i.e., a path from any of them must meet the ServiceLink's QoS requirements.
+type UserNodeAttachmentPoints struct {
+
+	// The names of the K8s nodes that are attachment points.
+	//
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+
+	// Selects the K8s nodes that are attachment points by their labels.
+	//
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
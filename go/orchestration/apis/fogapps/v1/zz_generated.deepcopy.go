@@ -194,6 +194,26 @@ func (in *GpuInfo) DeepCopy() *GpuInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyBudget) DeepCopyInto(out *LatencyBudget) {
+	*out = *in
+	if in.Chain != nil {
+		in, out := &in.Chain, &out.Chain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyBudget.
+func (in *LatencyBudget) DeepCopy() *LatencyBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinkQosRequirements) DeepCopyInto(out *LinkQosRequirements) {
 	*out = *in
@@ -237,6 +257,11 @@ func (in *LinkQosRequirements) DeepCopy() *LinkQosRequirements {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinkTrustRequirements) DeepCopyInto(out *LinkTrustRequirements) {
 	*out = *in
+	if in.AdministrativeDomains != nil {
+		in, out := &in.AdministrativeDomains, &out.AdministrativeDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkTrustRequirements.
@@ -449,6 +474,11 @@ func (in *ReplicasConfig) DeepCopyInto(out *ReplicasConfig) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.GangMinMembers != nil {
+		in, out := &in.GangMinMembers, &out.GangMinMembers
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasConfig.
@@ -556,6 +586,13 @@ func (in *ServiceGraphNode) DeepCopyInto(out *ServiceGraphNode) {
 			(*out)[key] = val
 		}
 	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]corev1.Container, len(*in))
@@ -622,6 +659,11 @@ func (in *ServiceGraphNode) DeepCopyInto(out *ServiceGraphNode) {
 		*out = new(GeoLocation)
 		**out = **in
 	}
+	if in.AttachmentPoints != nil {
+		in, out := &in.AttachmentPoints, &out.AttachmentPoints
+		*out = new(UserNodeAttachmentPoints)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGraphNode.
@@ -676,6 +718,13 @@ func (in *ServiceGraphSpec) DeepCopyInto(out *ServiceGraphSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LatencyBudgets != nil {
+		in, out := &in.LatencyBudgets, &out.LatencyBudgets
+		*out = make([]LatencyBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.SLOs != nil {
 		in, out := &in.SLOs, &out.SLOs
 		*out = make([]ServiceLevelObjective, len(*in))
@@ -784,7 +833,7 @@ func (in *ServiceLink) DeepCopyInto(out *ServiceLink) {
 	if in.TrustRequirements != nil {
 		in, out := &in.TrustRequirements, &out.TrustRequirements
 		*out = new(LinkTrustRequirements)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.SLOs != nil {
 		in, out := &in.SLOs, &out.SLOs
@@ -831,3 +880,28 @@ func (in *SloUserConfig) DeepCopy() *SloUserConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserNodeAttachmentPoints) DeepCopyInto(out *UserNodeAttachmentPoints) {
+	*out = *in
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserNodeAttachmentPoints.
+func (in *UserNodeAttachmentPoints) DeepCopy() *UserNodeAttachmentPoints {
+	if in == nil {
+		return nil
+	}
+	out := new(UserNodeAttachmentPoints)
+	in.DeepCopyInto(out)
+	return out
+}
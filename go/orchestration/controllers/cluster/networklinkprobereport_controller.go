@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Rainbow Project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/linkprobe"
+)
+
+const (
+	// The time after which a NetworkLinkProbeReport for a NetworkLink that does not exist (yet) is processed again.
+	missingNetworkLinkRequeueDelay = time.Minute
+)
+
+// NetworkLinkProbeReportReconciler aggregates the samples of NetworkLinkProbeReports into the status of their NetworkLinks.
+type NetworkLinkProbeReportReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// The number of recent samples that are used for computing the rolling means and variances.
+	// If this is not set, linkprobe.DefaultWindowSize is used.
+	WindowSize int
+}
+
+// Permissions on NetworkLinkProbeReports:
+//+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinkprobereports,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinkprobereports/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinkprobereports/finalizers,verbs=update
+
+// Permissions on NetworkLinks:
+//+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinks/status,verbs=get;update;patch
+
+// Reconcile is triggered whenever a NetworkLinkProbeReport is added or changed.
+//
+// Reconcile adds the samples of the report to the recent samples in the status of the probed NetworkLink,
+// recomputes the NetworkLink's measured QoS, and deletes the report afterwards.
+// The UIDs of merged reports are recorded in the NetworkLink's status, such that a report is never merged twice.
+func (me *NetworkLinkProbeReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := me.Log.WithValues("Reconcile NetworkLinkProbeReport", req.NamespacedName)
+
+	var report clusterCRDs.NetworkLinkProbeReport
+	if err := me.Get(ctx, req.NamespacedName, &report); err != nil {
+		err = client.IgnoreNotFound(err)
+		if err != nil {
+			log.Error(err, "Unable to fetch NetworkLinkProbeReport")
+		}
+		return ctrl.Result{}, err
+	}
+
+	var networkLink clusterCRDs.NetworkLink
+	networkLinkName := types.NamespacedName{Namespace: report.Namespace, Name: report.Spec.NetworkLink}
+	if err := me.Get(ctx, networkLinkName, &networkLink); err != nil {
+		if err = client.IgnoreNotFound(err); err != nil {
+			log.Error(err, "Unable to fetch NetworkLink", "networkLink", networkLinkName)
+			return ctrl.Result{}, err
+		}
+		log.Info("The probed NetworkLink does not exist.", "networkLink", networkLinkName)
+		return ctrl.Result{RequeueAfter: missingNetworkLinkRequeueDelay}, nil
+	}
+
+	// If deleting the report failed after a successful merge, the report's UID is still recorded in the status,
+	// so we only need to retry the deletion.
+	if linkprobe.MergeReport(&networkLink.Status, &report, me.WindowSize) {
+		if err := me.Status().Update(ctx, &networkLink); err != nil {
+			// On a conflict, the report is processed again with the updated NetworkLink.
+			log.Error(err, "Error updating NetworkLink status subresource", "networkLink", networkLinkName)
+			return ctrl.Result{}, err
+		}
+		log.Info("Successfully updated the measured QoS of the NetworkLink.", "networkLink", networkLinkName)
+	} else {
+		log.Info("The NetworkLinkProbeReport has already been merged.", "networkLink", networkLinkName)
+	}
+
+	if err := me.Delete(ctx, &report); err != nil {
+		if err = client.IgnoreNotFound(err); err != nil {
+			log.Error(err, "Unable to delete NetworkLinkProbeReport")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (me *NetworkLinkProbeReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&clusterCRDs.NetworkLinkProbeReport{}).
+		Complete(me)
+}
@@ -47,6 +47,9 @@ type ServiceGraphReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// The RegionManager that provides the RegionGraph of the cluster.
+	RegionManager regionmanager.RegionManager
 }
 
 // serviceGraphChildObjects collects all child objects that are created from a ServiceGraph.
@@ -67,6 +70,9 @@ type serviceGraphChildObjects struct {
 //+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=cluster.k8s.rainbow-h2020.eu,resources=networklinks/finalizers,verbs=update
 
+// Permissions on Nodes (required by the RegionManager):
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
 // Permissions on Deployments and StatefulSets:
 //+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments/status;statefulsets/status,verbs=get
@@ -167,8 +173,9 @@ func (me *ServiceGraphReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	// Initialize the region manager.
-	var _ = regionmanager.GetRegionManager()
+	if me.RegionManager == nil {
+		return fmt.Errorf("the ServiceGraphReconciler requires a RegionManager")
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fogappsCRDs.ServiceGraph{}).
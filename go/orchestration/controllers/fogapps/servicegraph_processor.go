@@ -497,6 +497,8 @@ func (me *serviceGraphProcessor) updateNodeStatusWithDeployment(node *fogappsCRD
 		nodeStatus.ConfiguredReplicas = *replicas
 	}
 	nodeStatus.ReadyReplicas = deployment.Status.ReadyReplicas
+	nodeStatus.CreationGeneration = svcGraphUtil.GetCreationGeneration(node, me.svcGraph)
+	nodeStatus.PodTemplateGeneration, nodeStatus.PodTemplateHash = svcGraphUtil.GetPodTemplateGeneration(node, me.svcGraph)
 }
 
 func (me *serviceGraphProcessor) updateNodeStatusWithStatefulSet(node *fogappsCRDs.ServiceGraphNode, statefulSet *apps.StatefulSet) {
@@ -513,6 +515,8 @@ func (me *serviceGraphProcessor) updateNodeStatusWithStatefulSet(node *fogappsCR
 		nodeStatus.ConfiguredReplicas = *replicas
 	}
 	nodeStatus.ReadyReplicas = statefulSet.Status.ReadyReplicas
+	nodeStatus.CreationGeneration = svcGraphUtil.GetCreationGeneration(node, me.svcGraph)
+	nodeStatus.PodTemplateGeneration, nodeStatus.PodTemplateHash = svcGraphUtil.GetPodTemplateGeneration(node, me.svcGraph)
 }
 
 func (me *serviceGraphProcessor) updateStatusConditions() {
@@ -84,9 +84,8 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
-	// Initialize the ConfigManager and the RegionManager.
-	configmanager.InitConfigManager(ctrl.GetConfigOrDie(), scheme.Scheme)
-	regionmanager.InitRegionManager()
+	regionMgr, err := regionmanager.NewRegionManager(configmanager.NewConfigManager(cfg, scheme.Scheme))
+	Expect(err).NotTo(HaveOccurred())
 
 	// Start the controllers.
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
@@ -95,9 +94,10 @@ var _ = BeforeSuite(func() {
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&fogappscontrollers.ServiceGraphReconciler{
-		Client: k8sManager.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("fogapps").WithName("ServiceGraph"),
-		Scheme: k8sManager.GetScheme(),
+		Client:        k8sManager.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("fogapps").WithName("ServiceGraph"),
+		Scheme:        k8sManager.GetScheme(),
+		RegionManager: regionMgr,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -1,6 +1,8 @@
 package servicegraphutil
 
 import (
+	"strconv"
+
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,12 +14,15 @@ const (
 	kubernetesCpuArchLabel = "kubernetes.io/arch"
 )
 
-// CreatePodSpec creates a PodSpec from the specified node.
+// CreatePodTemplate creates a PodTemplateSpec from the specified node.
+// This is the same template that is used for the Deployment or StatefulSet of the node.
 func CreatePodTemplate(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) (*core.PodTemplateSpec, error) {
 	podTemplate := core.PodTemplateSpec{
 		ObjectMeta: meta.ObjectMeta{},
 		Spec:       core.PodSpec{},
 	}
+	updatePodTemplate(&podTemplate, node, graph)
+	setPodTemplateGeneration(&podTemplate, node, graph)
 
 	return &podTemplate, nil
 }
@@ -36,6 +41,7 @@ func CreateDeployment(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.Ser
 func UpdateDeployment(deployment *apps.Deployment, node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) (*apps.Deployment, error) {
 	updateNodeObjectMeta(&deployment.ObjectMeta, node, graph)
 	updatePodTemplate(&deployment.Spec.Template, node, graph)
+	setPodTemplateGeneration(&deployment.Spec.Template, node, graph)
 	deployment.Spec.Selector = createLabelSelector(node, graph)
 
 	initialReplicas := GetInitialReplicas(node)
@@ -61,6 +67,7 @@ func CreateStatefulSet(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.Se
 func UpdateStatefulSet(statefulSet *apps.StatefulSet, node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) (*apps.StatefulSet, error) {
 	updateNodeObjectMeta(&statefulSet.ObjectMeta, node, graph)
 	updatePodTemplate(&statefulSet.Spec.Template, node, graph)
+	setPodTemplateGeneration(&statefulSet.Spec.Template, node, graph)
 	statefulSet.Spec.Selector = createLabelSelector(node, graph)
 
 	initialReplicas := GetInitialReplicas(node)
@@ -79,7 +86,8 @@ func updatePodTemplate(podTemplate *core.PodTemplateSpec, node *fogappsCRDs.Serv
 	podTemplate.Spec.InitContainers = node.InitContainers
 	podTemplate.Spec.Containers = node.Containers
 	podTemplate.Spec.Volumes = node.Volumes
-	podTemplate.Spec.Affinity = node.Affinity
+	// The affinity may be extended by addNodeHardwareRequirements(), so we must not modify the one of the ServiceGraphNode.
+	podTemplate.Spec.Affinity = node.Affinity.DeepCopy()
 
 	if node.ImagePullSecrets != nil && len(node.ImagePullSecrets) > 0 {
 		podTemplate.Spec.ImagePullSecrets = node.ImagePullSecrets
@@ -109,6 +117,52 @@ func updatePodTemplate(podTemplate *core.PodTemplateSpec, node *fogappsCRDs.Serv
 	}
 }
 
+// GetCreationGeneration returns the generation of the ServiceGraph, in which the specified node was added.
+//
+// If the node already exists in the Status of the ServiceGraph, the generation stored there is returned, which is 0
+// if the node was added before the orchestrator started tracking this generation.
+func GetCreationGeneration(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) int64 {
+	if nodeState := getNodeStatus(node, graph); nodeState != nil {
+		return nodeState.CreationGeneration
+	}
+	return graph.Generation
+}
+
+// GetPodTemplateGeneration returns the generation of the ServiceGraph, in which the pod template of the specified node
+// was last changed, and the hash of the node's current pod template.
+//
+// The pod template is considered to be changed in the current generation of the ServiceGraph if its hash differs from
+// the one stored in the Status of the ServiceGraph, i.e., if the node has been added or any of its fields
+// that affect the pod template have been modified.
+//
+// If the Status of the ServiceGraph contains the node, but no pod template hash, the pod template has been created
+// before the orchestrator started tracking its generation. Since we cannot tell if it has changed, we keep treating it
+// as unchanged and return 0, such that existing Deployments and StatefulSets are not rolled out just because of
+// the generation annotation.
+func GetPodTemplateGeneration(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) (int64, string) {
+	podTemplate := core.PodTemplateSpec{}
+	updatePodTemplate(&podTemplate, node, graph)
+	hash, err := kubeutil.ComputeSpecHash(&podTemplate)
+	if err != nil {
+		return graph.Generation, ""
+	}
+
+	if nodeState := getNodeStatus(node, graph); nodeState != nil {
+		if nodeState.PodTemplateHash == "" || nodeState.PodTemplateHash == hash {
+			return nodeState.PodTemplateGeneration, hash
+		}
+	}
+	return graph.Generation, hash
+}
+
+// Sets the AnnotationServiceGraphGeneration annotation on the pod template, unless the pod template has not changed
+// since the orchestrator started tracking its generation.
+func setPodTemplateGeneration(podTemplate *core.PodTemplateSpec, node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) {
+	if generation, _ := GetPodTemplateGeneration(node, graph); generation > 0 {
+		kubeutil.SetAnnotation(&podTemplate.ObjectMeta, kubeutil.AnnotationServiceGraphGeneration, strconv.FormatInt(generation, 10))
+	}
+}
+
 func createLabelSelector(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) *meta.LabelSelector {
 	return &meta.LabelSelector{
 		MatchLabels: getPodLabels(node, graph),
@@ -214,3 +268,11 @@ func getInitialReplicasFromStatus(node *fogappsCRDs.ServiceGraphNode, graph *fog
 	}
 	return -1
 }
+
+// Returns the status of the specified node from the Status of the ServiceGraph or nil, if the node does not exist in the Status.
+func getNodeStatus(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) *fogappsCRDs.ServiceGraphNodeStatus {
+	if graph.Status.NodeStates == nil {
+		return nil
+	}
+	return graph.Status.NodeStates[node.Name]
+}
@@ -39,7 +39,9 @@ import (
 	clusterv1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
 	fogappsv1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
 	slov1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/slo/v1"
+	clustercontrollers "k8s.rainbow-h2020.eu/rainbow/orchestration/controllers/cluster"
 	fogappscontrollers "k8s.rainbow-h2020.eu/rainbow/orchestration/controllers/fogapps"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/linkprobe"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
 	//+kubebuilder:scaffold:imports
@@ -63,11 +65,14 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var networkLinkProbeWindow int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.IntVar(&networkLinkProbeWindow, "network-link-probe-window", linkprobe.DefaultWindowSize,
+		"The number of recent network probe samples used for computing the measured QoS of a NetworkLink.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -89,18 +94,32 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize the ConfigManager and the RegionManager.
-	configmanager.InitConfigManager(ctrl.GetConfigOrDie(), scheme)
-	regionmanager.InitRegionManager()
+	configMgr := configmanager.NewConfigManager(ctrl.GetConfigOrDie(), scheme)
+	regionMgr, err := regionmanager.NewRegionManager(configMgr)
+	if err != nil {
+		setupLog.Error(err, "unable to start RegionManager")
+		os.Exit(1)
+	}
+	defer regionMgr.Stop()
 
 	if err = (&fogappscontrollers.ServiceGraphReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("fogapps").WithName("ServiceGraph"),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("fogapps").WithName("ServiceGraph"),
+		Scheme:        mgr.GetScheme(),
+		RegionManager: regionMgr,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceGraph")
 		os.Exit(1)
 	}
+	if err = (&clustercontrollers.NetworkLinkProbeReportReconciler{
+		Client:     mgr.GetClient(),
+		Log:        ctrl.Log.WithName("controllers").WithName("cluster").WithName("NetworkLinkProbeReport"),
+		Scheme:     mgr.GetScheme(),
+		WindowSize: networkLinkProbeWindow,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NetworkLinkProbeReport")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
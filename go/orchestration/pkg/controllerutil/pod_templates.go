@@ -0,0 +1,18 @@
+package controllerutil
+
+import (
+	core "k8s.io/api/core/v1"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/internal/servicegraphutil"
+)
+
+// CreatePodTemplate creates the template for the pods of the specified ServiceGraphNode.
+// This is the same template that the ServiceGraph controller uses for the Deployment or StatefulSet of the node.
+func CreatePodTemplate(node *fogappsCRDs.ServiceGraphNode, graph *fogappsCRDs.ServiceGraph) (*core.PodTemplateSpec, error) {
+	return servicegraphutil.CreatePodTemplate(node, graph)
+}
+
+// GetInitialReplicas returns the number of replicas that the ServiceGraph controller initially creates for the specified ServiceGraphNode.
+func GetInitialReplicas(node *fogappsCRDs.ServiceGraphNode) int32 {
+	return servicegraphutil.GetInitialReplicas(node)
+}
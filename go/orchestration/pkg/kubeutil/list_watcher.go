@@ -2,11 +2,15 @@ package kubeutil
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,22 +20,67 @@ var (
 	_ ListWatcher = _listWatcherImpl
 )
 
+const (
+	// The time to wait before retrying to restart a failed watch.
+	listWatcherRetryInterval = 5 * time.Second
+)
+
+// ListWatchDeltaType describes the type of change that is reported by a ListWatchDelta.
+type ListWatchDeltaType string
+
+const (
+	// The Object has been added.
+	ListWatchAdded ListWatchDeltaType = "Added"
+
+	// The Object has been modified. The ListWatchDelta contains the new version of the object.
+	ListWatchModified ListWatchDeltaType = "Modified"
+
+	// The Object has been deleted. The ListWatchDelta contains the last known version of the object.
+	ListWatchDeleted ListWatchDeltaType = "Deleted"
+
+	// The watch had to be restarted and the List contains the current state of all objects.
+	// This replaces all objects that have been reported before. Objects that are not contained in the List anymore
+	// have been deleted while the watch was not running.
+	ListWatchResync ListWatchDeltaType = "Resync"
+)
+
+// ListWatchDelta describes a change to the list of objects watched by a ListWatcher.
+type ListWatchDelta struct {
+	// The type of change.
+	Type ListWatchDeltaType
+
+	// The added, modified, or deleted object.
+	// This is nil if Type is ListWatchResync.
+	Object client.Object
+
+	// The entire list of objects.
+	// This is only set if Type is ListWatchResync.
+	List client.ObjectList
+}
+
 // ListWatcher allows watching a list of Kubernetes objects.
-// It emits the entire list upon initialization and whenever an element is added, changed, or deleted.
+// It fetches the entire list upon initialization and afterwards emits a ListWatchDelta whenever an element is added, changed, or deleted.
 type ListWatcher interface {
 
-	// Gets the channel that emits the list of objects after init and on every change.
-	WatchChan() chan client.ObjectList
+	// Gets the list of objects that was fetched when the ListWatcher was started.
+	InitialList() client.ObjectList
+
+	// Gets the channel that emits a ListWatchDelta for every change to the list after the InitialList.
+	// The channel is closed when the ListWatcher is stopped.
+	DeltaChan() <-chan ListWatchDelta
 
 	// Stops the watch.
+	// Calling Stop() more than once has no effect.
 	Stop()
 }
 
 type listWatcherImpl struct {
-	client     client.WithWatch
-	listType   client.ObjectList
-	watch      watch.Interface
-	outputChan chan client.ObjectList
+	client      client.WithWatch
+	listType    client.ObjectList
+	initialList client.ObjectList
+	outputChan  chan ListWatchDelta
+	stopChan    chan struct{}
+	stopOnce    sync.Once
 }
 
 // Creates a new ListWatcher for the specified listType and starts the watch.
@@ -40,70 +89,136 @@ func StartListWatcher(listType client.ObjectList, cfg *rest.Config, scheme *runt
 	if err != nil {
 		return nil, err
 	}
+	return StartListWatcherWithClient(listType, cl)
+}
 
+// Creates a new ListWatcher for the specified listType that uses the specified client and starts the watch.
+func StartListWatcherWithClient(listType client.ObjectList, cl client.WithWatch) (ListWatcher, error) {
 	watcher := &listWatcherImpl{
 		client:     cl,
 		listType:   listType.DeepCopyObject().(client.ObjectList),
-		outputChan: make(chan client.ObjectList, 1),
+		outputChan: make(chan ListWatchDelta, 16),
+		stopChan:   make(chan struct{}),
 	}
-	if err := watcher.initWatch(); err != nil {
+
+	initialList, watch, err := watcher.listAndWatch()
+	if err != nil {
 		return nil, err
 	}
+	watcher.initialList = initialList
+	go watcher.runWatch(watch)
 	return watcher, nil
 }
 
-func (me *listWatcherImpl) WatchChan() chan client.ObjectList {
+func (me *listWatcherImpl) InitialList() client.ObjectList {
+	return me.initialList
+}
+
+func (me *listWatcherImpl) DeltaChan() <-chan ListWatchDelta {
 	return me.outputChan
 }
 
 func (me *listWatcherImpl) Stop() {
-	me.watch.Stop()
-	close(me.outputChan)
+	me.stopOnce.Do(func() { close(me.stopChan) })
 }
 
-func (me *listWatcherImpl) initWatch() error {
-	// Fetch the initial list.
-	initialList, err := me.getCurrentList()
-	if err != nil {
-		return err
+// Fetches the current list and starts a watch from its resource version.
+func (me *listWatcherImpl) listAndWatch() (client.ObjectList, watch.Interface, error) {
+	list := me.listType.DeepCopyObject().(client.ObjectList)
+	if err := me.client.List(context.TODO(), list); err != nil {
+		return nil, nil, err
 	}
 
-	// Initializes the watch object, starting from the current resource version.
 	listType := me.listType.DeepCopyObject().(client.ObjectList)
 	watch, err := me.client.Watch(context.TODO(), listType, &client.ListOptions{
-		Raw: &metav1.ListOptions{ResourceVersion: initialList.GetResourceVersion()},
+		Raw: &metav1.ListOptions{ResourceVersion: list.GetResourceVersion()},
 	})
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	me.watch = watch
-
-	// Write the initial list to the output channel and run the watch loop.
-	me.publishList(initialList)
-	go me.runWatch()
-	return nil
+	return list, watch, nil
 }
 
-func (me *listWatcherImpl) runWatch() {
-	resultsChan := me.watch.ResultChan()
-	for event := range resultsChan {
-		// ToDo: Implement some form of caching to avoid refetching the entire list every time.
-		var _ = event
-		list, err := me.getCurrentList()
-		if err == nil {
-			me.publishList(list)
+// Converts the watch events into ListWatchDeltas until the ListWatcher is stopped.
+//
+// If the watch ends (e.g., because the API server closed the connection) or reports an error (e.g., because
+// the resource version has expired), the list is fetched again, emitted as a ListWatchResync delta, and the watch is restarted.
+func (me *listWatcherImpl) runWatch(currWatch watch.Interface) {
+	defer close(me.outputChan)
+
+	for {
+		if !me.forwardEvents(currWatch) {
+			return
+		}
+
+		for {
+			list, newWatch, err := me.listAndWatch()
+			if err == nil {
+				currWatch = newWatch
+				if !me.publishDelta(ListWatchDelta{Type: ListWatchResync, List: list}) {
+					currWatch.Stop()
+					return
+				}
+				break
+			}
+			klog.Errorf("ListWatcher: could not restart the watch: %v", err)
+			select {
+			case <-me.stopChan:
+				return
+			case <-time.After(listWatcherRetryInterval):
+			}
 		}
 	}
 }
 
-func (me *listWatcherImpl) getCurrentList() (client.ObjectList, error) {
-	list := me.listType.DeepCopyObject().(client.ObjectList)
-	if err := me.client.List(context.TODO(), list); err != nil {
-		return nil, err
+// Forwards the events of the watch to the output channel.
+// Returns false if the ListWatcher has been stopped or true if the watch needs to be restarted.
+func (me *listWatcherImpl) forwardEvents(currWatch watch.Interface) bool {
+	defer currWatch.Stop()
+	resultsChan := currWatch.ResultChan()
+
+	for {
+		select {
+		case <-me.stopChan:
+			return false
+		case event, ok := <-resultsChan:
+			if !ok {
+				return true
+			}
+
+			var deltaType ListWatchDeltaType
+			switch event.Type {
+			case watch.Added:
+				deltaType = ListWatchAdded
+			case watch.Modified:
+				deltaType = ListWatchModified
+			case watch.Deleted:
+				deltaType = ListWatchDeleted
+			case watch.Error:
+				klog.Warningf("ListWatcher: the watch reported an error, restarting: %v", apierrors.FromObject(event.Object))
+				return true
+			default:
+				continue
+			}
+
+			obj, isClientObj := event.Object.(client.Object)
+			if !isClientObj {
+				continue
+			}
+			if !me.publishDelta(ListWatchDelta{Type: deltaType, Object: obj}) {
+				return false
+			}
+		}
 	}
-	return list, nil
 }
 
-func (me *listWatcherImpl) publishList(list client.ObjectList) {
-	me.outputChan <- list
+// Writes the delta to the output channel.
+// Returns false if the ListWatcher was stopped before the delta could be written.
+func (me *listWatcherImpl) publishDelta(delta ListWatchDelta) bool {
+	select {
+	case me.outputChan <- delta:
+		return true
+	case <-me.stopChan:
+		return false
+	}
 }
@@ -0,0 +1,78 @@
+package kubeutil_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ListWatcher", func() {
+
+	var cl client.WithWatch
+	var watcher kubeutil.ListWatcher
+
+	newConfigMap := func(name string, value string) *core.ConfigMap {
+		return &core.ConfigMap{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "test"},
+			Data:       map[string]string{"value": value},
+		}
+	}
+
+	nextDelta := func() kubeutil.ListWatchDelta {
+		var delta kubeutil.ListWatchDelta
+		Eventually(watcher.DeltaChan(), 5*time.Second).Should(Receive(&delta))
+		return delta
+	}
+
+	BeforeEach(func() {
+		cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(newConfigMap("initial", "a")).Build()
+
+		var err error
+		watcher, err = kubeutil.StartListWatcherWithClient(&core.ConfigMapList{}, cl)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		watcher.Stop()
+	})
+
+	It("provides the initial list", func() {
+		initialList := watcher.InitialList().(*core.ConfigMapList)
+		Expect(initialList.Items).To(HaveLen(1))
+		Expect(initialList.Items[0].Name).To(Equal("initial"))
+	})
+
+	It("emits typed deltas for added, modified, and deleted objects", func() {
+		configMap := newConfigMap("new", "a")
+		Expect(cl.Create(context.TODO(), configMap)).To(Succeed())
+		delta := nextDelta()
+		Expect(delta.Type).To(Equal(kubeutil.ListWatchAdded))
+		Expect(delta.Object.GetName()).To(Equal("new"))
+
+		configMap.Data["value"] = "b"
+		Expect(cl.Update(context.TODO(), configMap)).To(Succeed())
+		delta = nextDelta()
+		Expect(delta.Type).To(Equal(kubeutil.ListWatchModified))
+		Expect(delta.Object.(*core.ConfigMap).Data["value"]).To(Equal("b"))
+
+		Expect(cl.Delete(context.TODO(), configMap)).To(Succeed())
+		delta = nextDelta()
+		Expect(delta.Type).To(Equal(kubeutil.ListWatchDeleted))
+		Expect(delta.Object.GetName()).To(Equal("new"))
+	})
+
+	It("closes the delta channel when stopped", func() {
+		watcher.Stop()
+		Eventually(watcher.DeltaChan(), 5*time.Second).Should(BeClosed())
+	})
+
+})
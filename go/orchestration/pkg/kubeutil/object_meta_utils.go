@@ -1,6 +1,8 @@
 package kubeutil
 
 import (
+	"strconv"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,12 +20,30 @@ const (
 	// Name of the node label that contains the node's hourly cost (as a floating point number in decimal notation, e.g., "1.49").
 	LabelNodeCost = "rainbow-h2020.eu/node-cost-per-hour"
 
+	// Name of the node label that contains the node's hourly spot price (as a floating point number in decimal notation, e.g., "0.42").
+	// If this label is present, the node is a spot (i.e., preemptible) instance and this price is used instead of LabelNodeCost.
+	LabelNodeSpotCost = "rainbow-h2020.eu/node-spot-cost-per-hour"
+
 	// Name of the annotation that stores the resourceVersion of the service graph, from which an object was last updated.
 	AnnotationLastUpdatedByServiceGraphVersion = "rainbow-h2020.eu/last-updated-by-service-graph-version"
 
 	// Name of the annotation that stores the hash of the spec that was used for creating an object.
 	// See SetSpecHash()
 	AnnotationSpecHash = "rainbow-h2020.eu/spec-hash"
+
+	// Name of the pod annotation that stores the metadata.Generation of the service graph, in which the pod template
+	// of the pod's service graph node was last changed (as an integer in decimal notation).
+	// All pods that have been created from service graph nodes, which were added or changed in the same generation, carry the same value.
+	AnnotationServiceGraphGeneration = "rainbow-h2020.eu/service-graph-generation"
+
+	// Name of the pod annotation that stores the bandwidth, which the scheduler has reserved for the pod's ServiceLinks
+	// on the network links of their paths, as a JSON array of reservations.
+	// This allows restoring the exact reservations after a restart of the scheduler.
+	AnnotationBandwidthReservations = "rainbow-h2020.eu/bandwidth-reservations"
+
+	// Name of the pod annotation that specifies the type of workload that the pod represents, e.g., "video-transcoding".
+	// It may also be set in the annotations of a service graph node, which are applied to the pods created from it.
+	AnnotationWorkloadType = "rainbow-h2020.eu/workload-type"
 )
 
 // GetLabel returns the label with the specified key.
@@ -52,6 +72,20 @@ func GetAnnotation(obj metav1.Object, key string) (string, bool) {
 	return "", false
 }
 
+// GetServiceGraphGeneration returns the value of the AnnotationServiceGraphGeneration annotation of the object.
+// If the annotation is not set or is not a valid integer, false is returned.
+func GetServiceGraphGeneration(obj metav1.Object) (int64, bool) {
+	value, ok := GetAnnotation(obj, AnnotationServiceGraphGeneration)
+	if !ok {
+		return 0, false
+	}
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return generation, true
+}
+
 // SetAnnotation sets the annotation indicated by the key to the specified value.
 func SetAnnotation(obj metav1.Object, key, value string) {
 	annotations := obj.GetAnnotations()
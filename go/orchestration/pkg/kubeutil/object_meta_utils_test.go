@@ -93,6 +93,28 @@ var _ = Describe("object_meta_utils", func() {
 
 	})
 
+	Describe("GetServiceGraphGeneration", func() {
+
+		It("returns the parsed generation", func() {
+			resourceObj.ObjectMeta.Annotations[kubeutil.AnnotationServiceGraphGeneration] = "42"
+			generation, found := kubeutil.GetServiceGraphGeneration(resourceObj)
+			Expect(found).To(Equal(true))
+			Expect(generation).To(Equal(int64(42)))
+		})
+
+		It("reports false on a missing annotation", func() {
+			_, found := kubeutil.GetServiceGraphGeneration(resourceObj)
+			Expect(found).To(Equal(false))
+		})
+
+		It("reports false on an invalid generation", func() {
+			resourceObj.ObjectMeta.Annotations[kubeutil.AnnotationServiceGraphGeneration] = "latest"
+			_, found := kubeutil.GetServiceGraphGeneration(resourceObj)
+			Expect(found).To(Equal(false))
+		})
+
+	})
+
 	Describe("SetAnnotation", func() {
 
 		Context("Existing Annotations field", func() {
@@ -16,13 +16,20 @@ import (
 // If the hash results in collisions for common cases, an alternative would be
 // https://github.com/banzaicloud/k8s-objectmatcher
 func SetSpecHash(obj meta.Object, objSpec interface{}) {
-	hash, err := hashstructure.Hash(objSpec, hashstructure.FormatV2, nil)
-	if err == nil {
-		hashStr := fmt.Sprintf("%v", hash)
+	if hashStr, err := ComputeSpecHash(objSpec); err == nil {
 		SetAnnotation(obj, AnnotationSpecHash, hashStr)
 	}
 }
 
+// Computes the hash of the objSpec, which is used by SetSpecHash().
+func ComputeSpecHash(objSpec interface{}) (string, error) {
+	hash, err := hashstructure.Hash(objSpec, hashstructure.FormatV2, nil)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", hash), nil
+}
+
 // Returns true if the spec hash annotation values of the two objects are equal
 // or false, if they are not equal or both are not set.
 func CheckSpecHashesAreEqual(a, b meta.Object) bool {
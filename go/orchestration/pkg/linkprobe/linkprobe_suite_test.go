@@ -0,0 +1,13 @@
+package linkprobe_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLinkProbe(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LinkProbe Suite")
+}
@@ -0,0 +1,131 @@
+package linkprobe
+
+import (
+	"math"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+)
+
+const (
+	// DefaultWindowSize is the default number of recent samples that are used for computing the rolling mean and variance.
+	DefaultWindowSize = 10
+)
+
+// AddSamples adds the new samples to the RecentSamples of the status, retaining only the windowSize most recent ones,
+// and recomputes the MeasuredQoS and the LastProbeTime of the status.
+//
+// Samples that are older than the oldest sample in a full window are discarded.
+func AddSamples(status *cluster.NetworkLinkStatus, samples []cluster.NetworkLinkProbeSample, windowSize int) {
+	if windowSize <= 0 {
+		windowSize = DefaultWindowSize
+	}
+
+	allSamples := make([]cluster.NetworkLinkProbeSample, 0, len(status.RecentSamples)+len(samples))
+	allSamples = append(allSamples, status.RecentSamples...)
+	allSamples = append(allSamples, samples...)
+	sort.SliceStable(allSamples, func(i, j int) bool {
+		return allSamples[i].Timestamp.Before(&allSamples[j].Timestamp)
+	})
+	if len(allSamples) > windowSize {
+		allSamples = allSamples[len(allSamples)-windowSize:]
+	}
+
+	status.RecentSamples = allSamples
+	status.MeasuredQoS = AggregateSamples(allSamples)
+	if len(allSamples) > 0 {
+		lastProbeTime := allSamples[len(allSamples)-1].Timestamp
+		status.LastProbeTime = &lastProbeTime
+	} else {
+		status.LastProbeTime = nil
+	}
+}
+
+// MergeReport adds the samples of the report to the status using AddSamples() and records the report's UID
+// in the MergedProbeReports of the status, retaining only the windowSize most recent UIDs.
+//
+// If the report has already been merged into the status, the status is not changed and false is returned.
+func MergeReport(status *cluster.NetworkLinkStatus, report *cluster.NetworkLinkProbeReport, windowSize int) bool {
+	if IsReportMerged(status, report.UID) {
+		return false
+	}
+	if windowSize <= 0 {
+		windowSize = DefaultWindowSize
+	}
+
+	AddSamples(status, report.Spec.Samples, windowSize)
+
+	// Every report contributes at least one sample, so at most windowSize reports can be part of the RecentSamples.
+	mergedReports := append(status.MergedProbeReports, report.UID)
+	if len(mergedReports) > windowSize {
+		mergedReports = mergedReports[len(mergedReports)-windowSize:]
+	}
+	status.MergedProbeReports = mergedReports
+	return true
+}
+
+// IsReportMerged returns true if the NetworkLinkProbeReport with the specified UID is recorded in the MergedProbeReports of the status.
+func IsReportMerged(status *cluster.NetworkLinkStatus, reportUID types.UID) bool {
+	for _, uid := range status.MergedProbeReports {
+		if uid == reportUID {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateSamples computes the means and the variances of the bandwidth, the packet delay, and the packet loss of the samples.
+// If there are no samples, nil is returned.
+func AggregateSamples(samples []cluster.NetworkLinkProbeSample) *cluster.NetworkLinkMeasuredQoS {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var bandwidth, delay, packetLoss meanAndVariance
+	for i := range samples {
+		sample := &samples[i]
+		bandwidth.add(float64(sample.BandwidthKbps))
+		delay.add(float64(sample.PacketDelayMsec))
+		packetLoss.add(float64(sample.PacketLossBp))
+	}
+
+	return &cluster.NetworkLinkMeasuredQoS{
+		Throughput: cluster.NetworkThroughput{
+			BandwidthKbps:     int64(math.Round(bandwidth.mean)),
+			BandwidthVariance: int64(math.Round(bandwidth.variance())),
+		},
+		Latency: cluster.NetworkLatency{
+			PacketDelayMsec:     int32(math.Round(delay.mean)),
+			PacketDelayVariance: int32(math.Round(delay.variance())),
+		},
+		PacketLoss: cluster.NetworkPacketLoss{
+			PacketLossBp: int32(math.Round(packetLoss.mean)),
+		},
+		PacketLossVariance: int32(math.Round(packetLoss.variance())),
+		SampleCount:        int32(len(samples)),
+	}
+}
+
+// Computes the mean and the population variance of a series of values using Welford's algorithm.
+type meanAndVariance struct {
+	count int
+	mean  float64
+
+	// The sum of the squared differences from the mean.
+	m2 float64
+}
+
+func (me *meanAndVariance) add(value float64) {
+	me.count++
+	delta := value - me.mean
+	me.mean += delta / float64(me.count)
+	me.m2 += delta * (value - me.mean)
+}
+
+func (me *meanAndVariance) variance() float64 {
+	if me.count == 0 {
+		return 0
+	}
+	return me.m2 / float64(me.count)
+}
@@ -0,0 +1,107 @@
+package linkprobe_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/linkprobe"
+)
+
+var _ = Describe("Sample aggregation", func() {
+
+	baseTime := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	newSample := func(second int, bandwidthKbps int64, delayMsec int32, lossBp int32) cluster.NetworkLinkProbeSample {
+		return cluster.NetworkLinkProbeSample{
+			Timestamp:       meta.NewTime(baseTime.Add(time.Duration(second) * time.Second)),
+			BandwidthKbps:   bandwidthKbps,
+			PacketDelayMsec: delayMsec,
+			PacketLossBp:    lossBp,
+		}
+	}
+
+	It("computes the means and variances of the samples", func() {
+		measured := linkprobe.AggregateSamples([]cluster.NetworkLinkProbeSample{
+			newSample(0, 1000, 2, 0),
+			newSample(1, 2000, 4, 10),
+			newSample(2, 3000, 6, 20),
+		})
+
+		Expect(measured.SampleCount).To(Equal(int32(3)))
+		Expect(measured.Throughput.BandwidthKbps).To(Equal(int64(2000)))
+		Expect(measured.Throughput.BandwidthVariance).To(Equal(int64(666667)))
+		Expect(measured.Latency.PacketDelayMsec).To(Equal(int32(4)))
+		Expect(measured.Latency.PacketDelayVariance).To(Equal(int32(3)))
+		Expect(measured.PacketLoss.PacketLossBp).To(Equal(int32(10)))
+		Expect(measured.PacketLossVariance).To(Equal(int32(67)))
+	})
+
+	It("returns nil if there are no samples", func() {
+		Expect(linkprobe.AggregateSamples(nil)).To(BeNil())
+	})
+
+	It("retains only the most recent samples within the window", func() {
+		status := &cluster.NetworkLinkStatus{}
+		linkprobe.AddSamples(status, []cluster.NetworkLinkProbeSample{
+			newSample(2, 3000, 6, 0),
+			newSample(0, 1000, 2, 0),
+		}, 3)
+		linkprobe.AddSamples(status, []cluster.NetworkLinkProbeSample{
+			newSample(3, 4000, 8, 0),
+			newSample(1, 2000, 4, 0),
+		}, 3)
+
+		Expect(status.RecentSamples).To(HaveLen(3))
+		Expect(status.RecentSamples[0].BandwidthKbps).To(Equal(int64(2000)))
+		Expect(status.RecentSamples[2].BandwidthKbps).To(Equal(int64(4000)))
+		Expect(status.MeasuredQoS.Throughput.BandwidthKbps).To(Equal(int64(3000)))
+		Expect(status.LastProbeTime.Time).To(Equal(baseTime.Add(3 * time.Second)))
+	})
+
+	It("merges each report only once", func() {
+		newReport := func(uid string, samples ...cluster.NetworkLinkProbeSample) *cluster.NetworkLinkProbeReport {
+			return &cluster.NetworkLinkProbeReport{
+				ObjectMeta: meta.ObjectMeta{UID: types.UID(uid)},
+				Spec:       cluster.NetworkLinkProbeReportSpec{Samples: samples},
+			}
+		}
+
+		status := &cluster.NetworkLinkStatus{}
+		Expect(linkprobe.MergeReport(status, newReport("report-0", newSample(0, 1000, 2, 0)), 2)).To(BeTrue())
+		Expect(linkprobe.MergeReport(status, newReport("report-1", newSample(1, 2000, 4, 0)), 2)).To(BeTrue())
+		Expect(linkprobe.MergeReport(status, newReport("report-1", newSample(1, 2000, 4, 0)), 2)).To(BeFalse())
+		Expect(status.RecentSamples).To(HaveLen(2))
+		Expect(status.MeasuredQoS.Throughput.BandwidthKbps).To(Equal(int64(1500)))
+		Expect(status.MergedProbeReports).To(Equal([]types.UID{"report-0", "report-1"}))
+
+		Expect(linkprobe.MergeReport(status, newReport("report-2", newSample(2, 3000, 6, 0)), 2)).To(BeTrue())
+		Expect(status.MergedProbeReports).To(Equal([]types.UID{"report-1", "report-2"}))
+		Expect(linkprobe.IsReportMerged(status, "report-0")).To(BeFalse())
+		Expect(linkprobe.IsReportMerged(status, "report-2")).To(BeTrue())
+	})
+
+	It("prefers fresh measured values over the spec", func() {
+		link := &cluster.NetworkLink{
+			Spec: cluster.NetworkLinkSpec{
+				QoS: cluster.NetworkLinkQoS{
+					QualityClass: cluster.QC1Gbps,
+					Throughput:   cluster.NetworkThroughput{BandwidthKbps: 1000000},
+				},
+			},
+		}
+		linkprobe.AddSamples(&link.Status, []cluster.NetworkLinkProbeSample{newSample(0, 500000, 3, 5)}, 10)
+
+		fresh := link.EffectiveQoS(baseTime.Add(time.Minute), 5*time.Minute)
+		Expect(fresh.QualityClass).To(Equal(cluster.QC1Gbps))
+		Expect(fresh.Throughput.BandwidthKbps).To(Equal(int64(500000)))
+		Expect(fresh.Latency.PacketDelayMsec).To(Equal(int32(3)))
+
+		stale := link.EffectiveQoS(baseTime.Add(10*time.Minute), 5*time.Minute)
+		Expect(stale.Throughput.BandwidthKbps).To(Equal(int64(1000000)))
+	})
+})
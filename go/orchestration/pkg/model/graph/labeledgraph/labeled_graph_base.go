@@ -78,6 +78,13 @@ func (me *labeledGraphBase) AddNode(node LabeledNode) {
 	me.nodeIdsByLabel[label] = node.ID()
 }
 
+func (me *labeledGraphBase) RemoveNode(id int64) {
+	if node := me.Node(id); node != nil {
+		delete(me.nodeIdsByLabel, node.(LabeledNode).Label())
+	}
+	me.weightedGraph.RemoveNode(id)
+}
+
 func (me *labeledGraphBase) NewWeightedEdge(from, to LabeledNode, weight ComplexEdgeWeight) WeightedEdge {
 	return me.createNewEdge(from, to, weight)
 }
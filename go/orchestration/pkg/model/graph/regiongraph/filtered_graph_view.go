@@ -0,0 +1,90 @@
+package regiongraph
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+)
+
+var (
+	_filteredGraphView *filteredGraphView
+
+	_ graph.Weighted = _filteredGraphView
+)
+
+// NetworkLinkFilterFn decides if the specified network link may be used (i.e., traversed) or not.
+type NetworkLinkFilterFn func(link Edge) bool
+
+// A read-only view on a RegionGraph, which contains all nodes of the RegionGraph, but
+// only those edges that are accepted by a NetworkLinkFilterFn.
+type filteredGraphView struct {
+	region     RegionGraph
+	linkFilter NetworkLinkFilterFn
+}
+
+// NewFilteredGraphView creates a read-only view on the specified RegionGraph that contains all of its nodes,
+// but only the network links (i.e., edges) that are accepted by the linkFilter.
+//
+// The view can be passed to the gonum path search algorithms to restrict them to the network links that
+// fulfill certain criteria. It does not copy the RegionGraph, so the RegionGraph must not be modified while the view is in use.
+func NewFilteredGraphView(region RegionGraph, linkFilter NetworkLinkFilterFn) graph.Weighted {
+	return &filteredGraphView{
+		region:     region,
+		linkFilter: linkFilter,
+	}
+}
+
+func (me *filteredGraphView) Node(id int64) graph.Node {
+	return me.region.Graph().Node(id)
+}
+
+func (me *filteredGraphView) Nodes() graph.Nodes {
+	return me.region.Graph().Nodes()
+}
+
+func (me *filteredGraphView) From(id int64) graph.Nodes {
+	neighbors := make([]graph.Node, 0)
+	neighborsIterator := me.region.Graph().From(id)
+	for neighborsIterator.Next() {
+		neighbor := neighborsIterator.Node()
+		if me.WeightedEdge(id, neighbor.ID()) != nil {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	if len(neighbors) == 0 {
+		return graph.Empty
+	}
+	return iterator.NewOrderedNodes(neighbors)
+}
+
+func (me *filteredGraphView) HasEdgeBetween(xid, yid int64) bool {
+	// In a directed RegionGraph, there may only be an edge in one of the two directions.
+	return me.WeightedEdge(xid, yid) != nil || me.WeightedEdge(yid, xid) != nil
+}
+
+func (me *filteredGraphView) Edge(uid, vid int64) graph.Edge {
+	if edge := me.WeightedEdge(uid, vid); edge != nil {
+		return edge
+	}
+	return nil
+}
+
+func (me *filteredGraphView) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	edge := me.region.Graph().WeightedEdge(uid, vid)
+	if edge == nil || !me.linkFilter(edge.(Edge)) {
+		return nil
+	}
+	return edge
+}
+
+func (me *filteredGraphView) Weight(xid, yid int64) (float64, bool) {
+	if xid == yid {
+		return 0, true
+	}
+	if edge := me.WeightedEdge(xid, yid); edge != nil {
+		return edge.Weight(), true
+	}
+	return math.Inf(1), false
+}
@@ -11,12 +11,14 @@ var (
 )
 
 type networkLinkQosWeightImpl struct {
-	qos *cluster.NetworkLinkQoS
+	qos   *cluster.NetworkLinkQoS
+	trust *cluster.NetworkLinkTrust
 }
 
-func newNetworkLinkQosWeightImpl(qos *cluster.NetworkLinkQoS) *networkLinkQosWeightImpl {
+func newNetworkLinkQosWeightImpl(qos *cluster.NetworkLinkQoS, trust *cluster.NetworkLinkTrust) *networkLinkQosWeightImpl {
 	return &networkLinkQosWeightImpl{
-		qos: qos,
+		qos:   qos,
+		trust: trust,
 	}
 }
 
@@ -24,6 +26,10 @@ func (me *networkLinkQosWeightImpl) NetworkLinkQoS() *cluster.NetworkLinkQoS {
 	return me.qos
 }
 
+func (me *networkLinkQosWeightImpl) NetworkLinkTrust() *cluster.NetworkLinkTrust {
+	return me.trust
+}
+
 func (me *networkLinkQosWeightImpl) SimpleWeight() float64 {
 	return float64(me.qos.Latency.PacketDelayMsec)
 }
@@ -0,0 +1,78 @@
+package regiongraph
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+)
+
+// PathQoS describes the aggregated QoS of a path between two nodes in a RegionGraph.
+type PathQoS struct {
+	// The nodes of the path, starting with the source node and ending with the destination node.
+	Path []graph.Node
+
+	// The lowest bandwidth of any link along the path.
+	LowestBandwidthKbps int64
+
+	// The highest bandwidth variance of any link along the path.
+	HighestBandwidthVariance int64
+
+	// The sum of the packet delays over the entire path.
+	TotalPacketDelayMsec int64
+
+	// The highest packet delay variance of any link along the path.
+	HighestPacketDelayVariance int32
+
+	// The highest packet loss in basis points of any link along the path.
+	HighestPacketLossBp int32
+
+	// The lowest QualityClass (in Kbps) of any network link in the path.
+	LowestNetworkQualityClassKbps int64
+}
+
+// NewPathQoS computes the PathQoS of the specified path through the region.
+func NewPathQoS(path []graph.Node, region RegionGraph) *PathQoS {
+	pathQos := PathQoS{
+		Path:                          path,
+		LowestBandwidthKbps:           math.MaxInt64,
+		LowestNetworkQualityClassKbps: math.MaxInt64,
+	}
+
+	pathLength := len(path)
+	for i := 0; i < pathLength-1; i++ {
+		startNode := path[i]
+		endNode := path[i+1]
+		link := region.Graph().Edge(startNode.ID(), endNode.ID()).(Edge)
+		linkQos := link.NetworkLinkQoS()
+
+		// Quality class
+		if linkQos.QualityClass != "" {
+			linkQualityClassKbps := cluster.NetworkQualitClassToKbps(linkQos.QualityClass)
+			if linkQualityClassKbps < pathQos.LowestNetworkQualityClassKbps {
+				pathQos.LowestNetworkQualityClassKbps = linkQualityClassKbps
+			}
+		}
+
+		// Throughput
+		if linkQos.Throughput.BandwidthKbps < pathQos.LowestBandwidthKbps {
+			pathQos.LowestBandwidthKbps = linkQos.Throughput.BandwidthKbps
+		}
+		if linkQos.Throughput.BandwidthVariance > pathQos.HighestBandwidthVariance {
+			pathQos.HighestBandwidthVariance = linkQos.Throughput.BandwidthVariance
+		}
+
+		// Latency
+		pathQos.TotalPacketDelayMsec += int64(linkQos.Latency.PacketDelayMsec)
+		if linkQos.Latency.PacketDelayVariance > pathQos.HighestPacketDelayVariance {
+			pathQos.HighestPacketDelayVariance = linkQos.Latency.PacketDelayVariance
+		}
+
+		// Packet loss
+		if linkQos.PacketLoss.PacketLossBp > pathQos.HighestPacketLossBp {
+			pathQos.HighestPacketLossBp = linkQos.PacketLoss.PacketLossBp
+		}
+	}
+
+	return &pathQos
+}
@@ -18,6 +18,9 @@ type Edge interface {
 
 	// Gets the cluster.NetworkLinkQoS that describes this edge.
 	NetworkLinkQoS() *cluster.NetworkLinkQoS
+
+	// Gets the cluster.NetworkLinkTrust that describes this edge or nil, if no trust information is available.
+	NetworkLinkTrust() *cluster.NetworkLinkTrust
 }
 
 // NetworkLinkQosWeight wraps a NetworkLinkQoS in a ComplexEdgeWeight object.
@@ -26,14 +29,25 @@ type NetworkLinkQosWeight interface {
 
 	// Gets the NetworkLinkQoS stored by this weight.
 	NetworkLinkQoS() *cluster.NetworkLinkQoS
+
+	// Gets the NetworkLinkTrust stored by this weight.
+	NetworkLinkTrust() *cluster.NetworkLinkTrust
 }
 
-// RegionGraph is a representation of a RAINBOW region as a weighted undirected graph.
+// RegionGraph is a representation of a RAINBOW region as a weighted graph.
 // The weight of an edge is the number of milliseconds it takes to send a request between the
 // two nodes that it connects.
+//
+// In an undirected RegionGraph, every edge describes both directions of a network link.
+// In a directed RegionGraph, every edge describes only the direction from its From() to its To() node,
+// which allows modeling network links with asymmetric QoS. Paths in a directed RegionGraph must follow the direction of the edges.
 type RegionGraph interface {
-	// Graph returns the LabeledUndirectedGraph that models this region.
-	Graph() lg.LabeledUndirectedGraph
+	// Graph returns the LabeledGraph that models this region.
+	// This is a LabeledDirectedGraph, if IsDirected() returns true, and a LabeledUndirectedGraph otherwise.
+	Graph() lg.LabeledGraph
+
+	// IsDirected returns true if the edges of this RegionGraph are directed.
+	IsDirected() bool
 
 	// Creates and returns a new Node with a unique ID and the specified label.
 	//
@@ -46,13 +60,32 @@ type RegionGraph interface {
 
 	// Creates a new edge from the `from` node to the `to` node and
 	// assigns the specified qos as its weight.
+	// In a directed RegionGraph, the qos describes only the direction from the `from` node to the `to` node.
+	// The trust may be nil, if no trust information is available for the network link.
 	//
 	// This edge can be added to the graph using the SetEdge() method.
-	NewEdge(from, to Node, qos *cluster.NetworkLinkQoS) Edge
+	NewEdge(from, to Node, qos *cluster.NetworkLinkQoS, trust *cluster.NetworkLinkTrust) Edge
 
 	// Adds the specified edge to this graph.
 	SetEdge(edge Edge)
 
+	// Removes the edge from the node with the label fromLabel to the node with the label toLabel.
+	// In an undirected RegionGraph, this removes the edge for both directions.
+	// If such an edge does not exist, this is a no-op.
+	RemoveEdge(fromLabel, toLabel string)
+
+	// Removes the node with the specified label and all of its edges.
+	// If such a node does not exist, this is a no-op.
+	RemoveNode(label string)
+
+	// Clone creates a shallow copy of this RegionGraph.
+	//
+	// The copy has its own set of nodes and edges, so adding or removing nodes and edges does not affect this RegionGraph.
+	// However, the Node and Edge objects themselves are shared, so they must be treated as immutable.
+	// To change the QoS of a network link, a new edge must be set in the copy.
+	// This allows applying changes to a copy, while readers continue to use an unchanged snapshot.
+	Clone() RegionGraph
+
 	// NodeByLabel gets the node with the specified label.
 	NodeByLabel(label string) Node
 
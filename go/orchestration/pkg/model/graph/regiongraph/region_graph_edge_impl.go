@@ -28,6 +28,10 @@ func (me *regionGraphEdgeImpl) NetworkLinkQoS() *cluster.NetworkLinkQoS {
 	return me.ComplexWeight().(NetworkLinkQosWeight).NetworkLinkQoS()
 }
 
+func (me *regionGraphEdgeImpl) NetworkLinkTrust() *cluster.NetworkLinkTrust {
+	return me.ComplexWeight().(NetworkLinkQosWeight).NetworkLinkTrust()
+}
+
 func (me *regionGraphEdgeImpl) ReversedEdge() graph.Edge {
 	return NewEdge(me.To().(Node), me.From().(Node), me.ComplexWeight())
 }
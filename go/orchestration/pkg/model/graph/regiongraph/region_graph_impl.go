@@ -11,22 +11,36 @@ var (
 	_ RegionGraph = _regionGraphImpl
 )
 
-// regionGraphImpl is the default implementation of regionGraphImpl
+// regionGraphImpl is the default implementation of RegionGraph
 type regionGraphImpl struct {
-	graph lg.LabeledUndirectedGraph
+	graph    lg.LabeledGraph
+	directed bool
 }
 
-// NewRegionGraph creates a new instance of the default RegionGraph type.
+// NewRegionGraph creates a new instance of the default undirected RegionGraph type.
 func NewRegionGraph() RegionGraph {
 	return &regionGraphImpl{
-		graph: lg.NewLabeledUndirectedGraph(NewNode, NewEdge),
+		graph:    lg.NewLabeledUndirectedGraph(NewNode, NewEdge),
+		directed: false,
 	}
 }
 
-func (me *regionGraphImpl) Graph() lg.LabeledUndirectedGraph {
+// NewDirectedRegionGraph creates a new instance of the default directed RegionGraph type.
+func NewDirectedRegionGraph() RegionGraph {
+	return &regionGraphImpl{
+		graph:    lg.NewLabeledDirectedGraph(NewNode, NewEdge),
+		directed: true,
+	}
+}
+
+func (me *regionGraphImpl) Graph() lg.LabeledGraph {
 	return me.graph
 }
 
+func (me *regionGraphImpl) IsDirected() bool {
+	return me.directed
+}
+
 func (me *regionGraphImpl) NodeByLabel(label string) Node {
 	if node := me.graph.NodeByLabel(label); node != nil {
 		return node.(Node)
@@ -35,7 +49,10 @@ func (me *regionGraphImpl) NodeByLabel(label string) Node {
 }
 
 func (me *regionGraphImpl) Edge(fromLabel, toLabel string) Edge {
-	return me.graph.EdgeByLabels(fromLabel, toLabel).(Edge)
+	if edge := me.graph.EdgeByLabels(fromLabel, toLabel); edge != nil {
+		return edge.(Edge)
+	}
+	return nil
 }
 
 func (me *regionGraphImpl) NewNode(label string) Node {
@@ -46,11 +63,49 @@ func (me *regionGraphImpl) AddNode(node Node) {
 	me.graph.AddNode(node)
 }
 
-func (me *regionGraphImpl) NewEdge(from, to Node, qos *cluster.NetworkLinkQoS) Edge {
-	weight := newNetworkLinkQosWeightImpl(qos)
+func (me *regionGraphImpl) NewEdge(from, to Node, qos *cluster.NetworkLinkQoS, trust *cluster.NetworkLinkTrust) Edge {
+	weight := newNetworkLinkQosWeightImpl(qos, trust)
 	return me.graph.NewWeightedEdge(from, to, weight).(Edge)
 }
 
 func (me *regionGraphImpl) SetEdge(edge Edge) {
 	me.graph.SetWeightedEdge(edge)
 }
+
+func (me *regionGraphImpl) RemoveEdge(fromLabel, toLabel string) {
+	if edge := me.Edge(fromLabel, toLabel); edge != nil {
+		me.graph.RemoveEdge(edge.From().ID(), edge.To().ID())
+	}
+}
+
+func (me *regionGraphImpl) RemoveNode(label string) {
+	if node := me.NodeByLabel(label); node != nil {
+		me.graph.RemoveNode(node.ID())
+	}
+}
+
+func (me *regionGraphImpl) Clone() RegionGraph {
+	var clone *regionGraphImpl
+	if me.directed {
+		clone = NewDirectedRegionGraph().(*regionGraphImpl)
+	} else {
+		clone = NewRegionGraph().(*regionGraphImpl)
+	}
+
+	nodes := me.graph.Nodes()
+	for nodes.Next() {
+		clone.graph.AddNode(nodes.Node().(lg.LabeledNode))
+	}
+
+	nodes.Reset()
+	for nodes.Next() {
+		fromId := nodes.Node().ID()
+		neighbors := me.graph.From(fromId)
+		for neighbors.Next() {
+			edge := me.graph.WeightedEdge(fromId, neighbors.Node().ID())
+			clone.graph.SetWeightedEdge(edge.(lg.WeightedEdge))
+		}
+	}
+
+	return clone
+}
@@ -0,0 +1,133 @@
+package regiongraph_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+var _ = Describe("RegionGraph", func() {
+
+	newQoS := func(bandwidthKbps int64) *cluster.NetworkLinkQoS {
+		return &cluster.NetworkLinkQoS{Throughput: cluster.NetworkThroughput{BandwidthKbps: bandwidthKbps}}
+	}
+
+	getOrCreateNode := func(region regiongraph.RegionGraph, label string) regiongraph.Node {
+		if node := region.NodeByLabel(label); node != nil {
+			return node
+		}
+		node := region.NewNode(label)
+		region.AddNode(node)
+		return node
+	}
+
+	setEdge := func(region regiongraph.RegionGraph, from string, to string, bandwidthKbps int64) {
+		fromNode := getOrCreateNode(region, from)
+		toNode := getOrCreateNode(region, to)
+		region.SetEdge(region.NewEdge(fromNode, toNode, newQoS(bandwidthKbps), nil))
+	}
+
+	// Creates a directed RegionGraph with the edges a->b, b->a, and b->c and the isolated node d.
+	newTestRegion := func() regiongraph.RegionGraph {
+		region := regiongraph.NewDirectedRegionGraph()
+		setEdge(region, "a", "b", 100)
+		setEdge(region, "b", "a", 10)
+		setEdge(region, "b", "c", 50)
+		getOrCreateNode(region, "d")
+		return region
+	}
+
+	It("keeps the directions of the edges of a directed RegionGraph separate", func() {
+		region := regiongraph.NewDirectedRegionGraph()
+		Expect(region.IsDirected()).To(BeTrue())
+		setEdge(region, "a", "b", 100)
+		Expect(region.Edge("b", "a")).To(BeNil())
+
+		setEdge(region, "b", "a", 10)
+		Expect(region.Edge("a", "b").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(100)))
+		Expect(region.Edge("b", "a").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(10)))
+
+		region.RemoveEdge("a", "b")
+		Expect(region.Edge("a", "b")).To(BeNil())
+		Expect(region.Edge("b", "a")).ToNot(BeNil())
+	})
+
+	It("uses the same edge for both directions in an undirected RegionGraph", func() {
+		region := regiongraph.NewRegionGraph()
+		Expect(region.IsDirected()).To(BeFalse())
+		setEdge(region, "a", "b", 100)
+		Expect(region.Edge("b", "a").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(100)))
+
+		region.RemoveEdge("b", "a")
+		Expect(region.Edge("a", "b")).To(BeNil())
+	})
+
+	It("removes single edges and nodes with all of their edges", func() {
+		region := newTestRegion()
+
+		region.RemoveEdge("a", "b")
+		Expect(region.Edge("a", "b")).To(BeNil())
+		Expect(region.Edge("b", "a")).ToNot(BeNil())
+
+		region.RemoveNode("b")
+		Expect(region.NodeByLabel("b")).To(BeNil())
+		Expect(region.Edge("b", "a")).To(BeNil())
+		Expect(region.Edge("b", "c")).To(BeNil())
+		Expect(region.Graph().Nodes().Len()).To(Equal(3))
+
+		// Removing edges and nodes that do not exist is a no-op.
+		region.RemoveEdge("a", "c")
+		region.RemoveNode("x")
+		Expect(region.Graph().Nodes().Len()).To(Equal(3))
+	})
+
+	It("clones all nodes and edges and preserves the directedness", func() {
+		region := newTestRegion()
+		clone := region.Clone()
+
+		Expect(clone.IsDirected()).To(BeTrue())
+		Expect(clone.Graph().Nodes().Len()).To(Equal(4))
+		Expect(clone.NodeByLabel("d")).ToNot(BeNil())
+		Expect(clone.Edge("a", "b").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(100)))
+		Expect(clone.Edge("b", "a").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(10)))
+		Expect(clone.Edge("c", "b")).To(BeNil())
+
+		undirectedClone := regiongraph.NewRegionGraph().Clone()
+		Expect(undirectedClone.IsDirected()).To(BeFalse())
+	})
+
+	It("does not modify the original when the clone is modified", func() {
+		region := newTestRegion()
+		clone := region.Clone()
+
+		setEdge(clone, "a", "b", 1)
+		setEdge(clone, "c", "e", 20)
+		clone.RemoveEdge("b", "a")
+		clone.RemoveNode("d")
+
+		Expect(region.Graph().Nodes().Len()).To(Equal(4))
+		Expect(region.NodeByLabel("d")).ToNot(BeNil())
+		Expect(region.NodeByLabel("e")).To(BeNil())
+		Expect(region.Edge("a", "b").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(100)))
+		Expect(region.Edge("b", "a")).ToNot(BeNil())
+		Expect(region.Edge("c", "e")).To(BeNil())
+
+		Expect(clone.Edge("a", "b").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(1)))
+		Expect(clone.Edge("b", "a")).To(BeNil())
+	})
+
+	It("does not modify the clone when the original is modified", func() {
+		region := newTestRegion()
+		clone := region.Clone()
+
+		region.RemoveNode("b")
+		getOrCreateNode(region, "e")
+
+		Expect(clone.NodeByLabel("b")).ToNot(BeNil())
+		Expect(clone.Edge("b", "c")).ToNot(BeNil())
+		Expect(clone.NodeByLabel("e")).To(BeNil())
+	})
+
+})
@@ -0,0 +1,13 @@
+package regiongraph_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRegionGraph(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RegionGraph Suite")
+}
@@ -1,34 +1,51 @@
 package serviceplacement
 
-// Used to transform a string slice into a new string slice.
-type StringSliceTransformFn func(curr []string) []string
+// PodCounts contains the number of pods of a ServiceGraphNode on a single Kubernetes node.
+type PodCounts struct {
+	// The number of pods that have been assumed on the node by the scheduler,
+	// but whose binding has not been observed yet.
+	Assumed int
+
+	// The number of pods that have been observed as bound to the node.
+	Bound int
+}
+
+// Total returns the number of assumed and bound pods.
+func (me PodCounts) Total() int {
+	return me.Assumed + me.Bound
+}
 
 // ServiceGraphPlacementMap allows caching and looking up the Kubernetes nodes, on which
-// the pods of a ServiceGraphNode have been placed.
+// the pods of a ServiceGraphNode have been placed, along with the number of pods on each node.
 //
 // It provides the following thread safe operations:
 // - lookup of Kubernetes node names for a service graph node
-// - update of Kubernetes node names for a service graph node
+// - lookup of the pod counts for a service graph node
+// - update of the pod counts for a service graph node
 //
 // The node names for each ServiceGraphNode are accessible as an array slice (instead of a Set),
 // because the common use case is iterating through the list of nodes (e.g., the NetworkQosPlugin in the scheduler).
 type ServiceGraphPlacementMap interface {
 
-	// Gets the list of Kubernetes node names, to which at least one pod of the
-	// ServiceGraphNode has been assigned.
+	// Gets the sorted list of Kubernetes node names, to which at least one pod of the
+	// ServiceGraphNode has been assigned (assumed or bound).
 	//
 	// If no pods have been assigned to any nodes, the returned list is empty.
 	// If the service graph node is unknown, nil is returned.
 	GetKubernetesNodes(svcGraphNodeLabel string) []string
 
-	// Sets the list of Kubernetes node names for the ServiceGraphNode with the specified label.
-	//
-	// The updateFn is called after locking the respective node and receives the current list of nodes
-	// (nil if the ServiceGraphNode has not yet been added). It must return a new list that should
-	// be stored for the ServiceGraphNode.
-	//
-	// Each K8s node name should appear only once in the list.
-	SetKubernetesNodes(svcGraphNodeLabel string, updateFn StringSliceTransformFn)
+	// Gets the pod counts of the ServiceGraphNode on the specified Kubernetes node.
+	// If there are no pods of the ServiceGraphNode on the node, zero counts are returned.
+	GetPodCounts(svcGraphNodeLabel string, k8sNodeName string) PodCounts
+
+	// Gets the pod counts of the ServiceGraphNode on all Kubernetes nodes, to which at least one of its pods has been assigned.
+	// The returned map is a copy and may be modified by the caller.
+	// If the service graph node is unknown, nil is returned.
+	GetAllPodCounts(svcGraphNodeLabel string) map[string]PodCounts
+
+	// Replaces the pod counts of the ServiceGraphNode with the specified label.
+	// Nodes with a total count of zero are ignored.
+	SetPodCounts(svcGraphNodeLabel string, podCounts map[string]PodCounts)
 
 	// Returns true if this map was created for the initial placement of the ServiceGraph (i.e., if the placement map was initially empty).
 	IsInitialPlacement() bool
@@ -1,6 +1,7 @@
 package serviceplacement
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -19,11 +20,14 @@ type servicePlacementInfo struct {
 	// The name of the ServiceGraphNode
 	svcGraphNodeLabel string
 
-	// Used for controlling access to k8sNodes
+	// Used for controlling access to k8sNodes and podCounts
 	mutex sync.RWMutex
 
-	// The list of Kubernetes nodes, the pods of this ServiceGraphNode have been placed on.
+	// The sorted list of Kubernetes nodes, the pods of this ServiceGraphNode have been placed on.
 	k8sNodes []string
+
+	// Maps the names of the Kubernetes nodes in k8sNodes to the pod counts.
+	podCounts map[string]PodCounts
 }
 
 func newSericePlacementInfo(svcGraphNodeLabel string) *servicePlacementInfo {
@@ -31,6 +35,7 @@ func newSericePlacementInfo(svcGraphNodeLabel string) *servicePlacementInfo {
 		svcGraphNodeLabel: svcGraphNodeLabel,
 		mutex:             sync.RWMutex{},
 		k8sNodes:          make([]string, 0),
+		podCounts:         make(map[string]PodCounts),
 	}
 }
 
@@ -41,10 +46,38 @@ func (me *servicePlacementInfo) getK8sNodes() []string {
 	return ret
 }
 
-func (me *servicePlacementInfo) updateK8sNodes(updateFn StringSliceTransformFn) {
+func (me *servicePlacementInfo) getPodCounts(k8sNodeName string) PodCounts {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	return me.podCounts[k8sNodeName]
+}
+
+func (me *servicePlacementInfo) getAllPodCounts() map[string]PodCounts {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	ret := make(map[string]PodCounts, len(me.podCounts))
+	for k8sNode, counts := range me.podCounts {
+		ret[k8sNode] = counts
+	}
+	return ret
+}
+
+func (me *servicePlacementInfo) setPodCounts(podCounts map[string]PodCounts) {
+	k8sNodes := make([]string, 0, len(podCounts))
+	newPodCounts := make(map[string]PodCounts, len(podCounts))
+	for k8sNode, counts := range podCounts {
+		if counts.Total() > 0 {
+			k8sNodes = append(k8sNodes, k8sNode)
+			newPodCounts[k8sNode] = counts
+		}
+	}
+	sort.Strings(k8sNodes)
+
 	me.mutex.Lock()
 	defer me.mutex.Unlock()
-	me.k8sNodes = updateFn(me.k8sNodes)
+	// The k8sNodes slice is replaced instead of being modified, because it is returned to the callers of getK8sNodes().
+	me.k8sNodes = k8sNodes
+	me.podCounts = newPodCounts
 }
 
 //////////////////////////////////////////////////////
@@ -83,9 +116,31 @@ func (me *serviceGraphPlacementMapImpl) GetKubernetesNodes(svcGraphNodeLabel str
 	return nil
 }
 
-func (me *serviceGraphPlacementMapImpl) SetKubernetesNodes(svcGraphNodeLabel string, updateFn StringSliceTransformFn) {
+func (me *serviceGraphPlacementMapImpl) GetPodCounts(svcGraphNodeLabel string, k8sNodeName string) PodCounts {
+	me.mutex.RLock()
+	placementInfo, found := me.svcGraphNodes[svcGraphNodeLabel]
+	me.mutex.RUnlock()
+
+	if found {
+		return placementInfo.getPodCounts(k8sNodeName)
+	}
+	return PodCounts{}
+}
+
+func (me *serviceGraphPlacementMapImpl) GetAllPodCounts(svcGraphNodeLabel string) map[string]PodCounts {
+	me.mutex.RLock()
+	placementInfo, found := me.svcGraphNodes[svcGraphNodeLabel]
+	me.mutex.RUnlock()
+
+	if found {
+		return placementInfo.getAllPodCounts()
+	}
+	return nil
+}
+
+func (me *serviceGraphPlacementMapImpl) SetPodCounts(svcGraphNodeLabel string, podCounts map[string]PodCounts) {
 	placementInfo := me.getOrCreatePlacementInfo(svcGraphNodeLabel)
-	placementInfo.updateK8sNodes(updateFn)
+	placementInfo.setPodCounts(podCounts)
 }
 
 func (me *serviceGraphPlacementMapImpl) IsInitialPlacement() bool {
@@ -0,0 +1,101 @@
+package bandwidthledger
+
+import (
+	"encoding/json"
+
+	"gonum.org/v1/gonum/graph"
+	core "k8s.io/api/core/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+// BandwidthReservation describes the bandwidth that a pod consumes on a single NetworkLink.
+type BandwidthReservation struct {
+	// The name of the K8s node that sends the data over the NetworkLink.
+	From string `json:"from"`
+
+	// The name of the K8s node that receives the data over the NetworkLink.
+	To string `json:"to"`
+
+	// The reserved bandwidth.
+	BandwidthKbps int64 `json:"bandwidthKbps"`
+}
+
+// BandwidthLedger records how much of the bandwidth of each NetworkLink is consumed by
+// the ServiceLinks of the pods that have already been placed.
+//
+// The reservations of a pod are identified by the pod's namespace and name.
+// NetworkLinks are identified by the names of the two K8s nodes that they connect, so the ledger
+// remains valid when the RegionGraph is rebuilt.
+// For a directed RegionGraph, the reservations are distinguished by direction, because every direction of a NetworkLink
+// has its own bandwidth, e.g., on asymmetric cellular links. For an undirected RegionGraph, a reservation reduces
+// the residual bandwidth of the NetworkLink regardless of its direction.
+//
+// All methods are thread-safe.
+type BandwidthLedger interface {
+	// ReservedBandwidthKbps returns the total bandwidth that is reserved on the NetworkLink from one node to the other.
+	// If the ledger is not directed, the order of the nodes does not matter.
+	ReservedBandwidthKbps(from, to string) int64
+
+	// ResidualBandwidthKbps returns the bandwidth of the network link in the direction of the edge that is not reserved yet.
+	// The result is negative if the network link is overbooked, e.g., because its bandwidth has decreased.
+	ResidualBandwidthKbps(link regiongraph.Edge) int64
+
+	// Reserve records the bandwidth reservations of the specified pod.
+	// Existing reservations of the pod are replaced.
+	Reserve(pod *core.Pod, reservations []BandwidthReservation)
+
+	// Release removes all bandwidth reservations of the specified pod.
+	// If the pod has no reservations, this is a no-op.
+	Release(pod *core.Pod)
+}
+
+// NewBandwidthReservationsForPath creates a BandwidthReservation with the specified bandwidth for each
+// network link along the path, in the direction from the first to the last node of the path.
+func NewBandwidthReservationsForPath(path []graph.Node, bandwidthKbps int64) []BandwidthReservation {
+	if len(path) < 2 {
+		return nil
+	}
+
+	reservations := make([]BandwidthReservation, len(path)-1)
+	for i := range reservations {
+		reservations[i] = BandwidthReservation{
+			From:          path[i].(regiongraph.Node).Label(),
+			To:            path[i+1].(regiongraph.Node).Label(),
+			BandwidthKbps: bandwidthKbps,
+		}
+	}
+	return reservations
+}
+
+// NewBandwidthLedger creates a new, empty BandwidthLedger.
+// If directed is true, the reservations are distinguished by direction, which must be used for directed RegionGraphs.
+func NewBandwidthLedger(directed bool) BandwidthLedger {
+	return newBandwidthLedgerImpl(directed)
+}
+
+// EncodeReservationsAnnotation encodes the reservations as the value of the kubeutil.AnnotationBandwidthReservations pod annotation.
+func EncodeReservationsAnnotation(reservations []BandwidthReservation) (string, error) {
+	if reservations == nil {
+		reservations = []BandwidthReservation{}
+	}
+	value, err := json.Marshal(reservations)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// GetReservationsFromAnnotation returns the reservations stored in the kubeutil.AnnotationBandwidthReservations annotation of the pod.
+// If the pod does not have this annotation, false is returned. If the annotation cannot be decoded, an error is returned.
+func GetReservationsFromAnnotation(pod *core.Pod) ([]BandwidthReservation, bool, error) {
+	value, ok := kubeutil.GetAnnotation(pod, kubeutil.AnnotationBandwidthReservations)
+	if !ok {
+		return nil, false, nil
+	}
+	var reservations []BandwidthReservation
+	if err := json.Unmarshal([]byte(value), &reservations); err != nil {
+		return nil, true, err
+	}
+	return reservations, true, nil
+}
@@ -0,0 +1,114 @@
+package bandwidthledger
+
+import (
+	"fmt"
+	"sync"
+
+	core "k8s.io/api/core/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+var (
+	_bandwidthLedgerImpl *bandwidthLedgerImpl
+
+	_ BandwidthLedger = _bandwidthLedgerImpl
+)
+
+// Identifies a NetworkLink by the names of the two K8s nodes that it connects.
+// If the ledger is not directed, from is always the lexicographically smaller name.
+type networkLinkKey struct {
+	from string
+	to   string
+}
+
+// Default implementation of the BandwidthLedger.
+type bandwidthLedgerImpl struct {
+	// True if the reservations are distinguished by direction.
+	directed bool
+
+	// The total reserved bandwidth per network link.
+	reservedKbps map[networkLinkKey]int64
+
+	// Maps pod keys (<namespace>.<name>) to the reservations of the pods.
+	podReservations map[string][]BandwidthReservation
+
+	// Synchronizes access to reservedKbps and podReservations.
+	mutex sync.RWMutex
+}
+
+func newBandwidthLedgerImpl(directed bool) *bandwidthLedgerImpl {
+	return &bandwidthLedgerImpl{
+		directed:        directed,
+		reservedKbps:    make(map[networkLinkKey]int64),
+		podReservations: make(map[string][]BandwidthReservation),
+	}
+}
+
+func (me *bandwidthLedgerImpl) ReservedBandwidthKbps(from, to string) int64 {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	return me.reservedKbps[me.newNetworkLinkKey(from, to)]
+}
+
+func (me *bandwidthLedgerImpl) ResidualBandwidthKbps(link regiongraph.Edge) int64 {
+	from := link.From().(regiongraph.Node).Label()
+	to := link.To().(regiongraph.Node).Label()
+	return link.NetworkLinkQoS().Throughput.BandwidthKbps - me.ReservedBandwidthKbps(from, to)
+}
+
+func (me *bandwidthLedgerImpl) Reserve(pod *core.Pod, reservations []BandwidthReservation) {
+	podKey := me.getPodKey(pod)
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.releaseInternal(podKey)
+	if len(reservations) == 0 {
+		return
+	}
+
+	for _, reservation := range reservations {
+		me.reservedKbps[me.newNetworkLinkKey(reservation.From, reservation.To)] += reservation.BandwidthKbps
+	}
+	me.podReservations[podKey] = reservations
+}
+
+func (me *bandwidthLedgerImpl) Release(pod *core.Pod) {
+	podKey := me.getPodKey(pod)
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	me.releaseInternal(podKey)
+}
+
+// Removes the reservations of the pod with the specified key.
+// The caller must hold the write lock.
+func (me *bandwidthLedgerImpl) releaseInternal(podKey string) {
+	reservations, ok := me.podReservations[podKey]
+	if !ok {
+		return
+	}
+
+	for _, reservation := range reservations {
+		linkKey := me.newNetworkLinkKey(reservation.From, reservation.To)
+		if remaining := me.reservedKbps[linkKey] - reservation.BandwidthKbps; remaining > 0 {
+			me.reservedKbps[linkKey] = remaining
+		} else {
+			delete(me.reservedKbps, linkKey)
+		}
+	}
+	delete(me.podReservations, podKey)
+}
+
+func (me *bandwidthLedgerImpl) getPodKey(pod *core.Pod) string {
+	return fmt.Sprintf("%s.%s", kubeutil.GetNamespace(pod), pod.Name)
+}
+
+func (me *bandwidthLedgerImpl) newNetworkLinkKey(from, to string) networkLinkKey {
+	if !me.directed && from > to {
+		from, to = to, from
+	}
+	return networkLinkKey{from: from, to: to}
+}
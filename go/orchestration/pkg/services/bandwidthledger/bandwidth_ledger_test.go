@@ -0,0 +1,161 @@
+package bandwidthledger_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"gonum.org/v1/gonum/graph"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/bandwidthledger"
+)
+
+var _ = Describe("BandwidthLedger", func() {
+
+	var ledger bandwidthledger.BandwidthLedger
+	var podA, podB *core.Pod
+
+	newPod := func(name string) *core.Pod {
+		return &core.Pod{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "test"}}
+	}
+
+	BeforeEach(func() {
+		ledger = bandwidthledger.NewBandwidthLedger(false)
+		podA = newPod("pod-a")
+		podB = newPod("pod-b")
+	})
+
+	It("sums the reservations of all pods, regardless of the link direction, if the ledger is not directed", func() {
+		ledger.Reserve(podA, []bandwidthledger.BandwidthReservation{
+			{From: "node-1", To: "node-2", BandwidthKbps: 50000},
+		})
+		ledger.Reserve(podB, []bandwidthledger.BandwidthReservation{
+			{From: "node-2", To: "node-1", BandwidthKbps: 30000},
+			{From: "node-2", To: "node-3", BandwidthKbps: 30000},
+		})
+
+		Expect(ledger.ReservedBandwidthKbps("node-1", "node-2")).To(Equal(int64(80000)))
+		Expect(ledger.ReservedBandwidthKbps("node-3", "node-2")).To(Equal(int64(30000)))
+		Expect(ledger.ReservedBandwidthKbps("node-1", "node-3")).To(Equal(int64(0)))
+	})
+
+	It("replaces existing reservations of a pod", func() {
+		ledger.Reserve(podA, []bandwidthledger.BandwidthReservation{
+			{From: "node-1", To: "node-2", BandwidthKbps: 50000},
+		})
+		ledger.Reserve(podA, []bandwidthledger.BandwidthReservation{
+			{From: "node-1", To: "node-2", BandwidthKbps: 20000},
+		})
+
+		Expect(ledger.ReservedBandwidthKbps("node-1", "node-2")).To(Equal(int64(20000)))
+	})
+
+	It("releases the reservations of a pod", func() {
+		ledger.Reserve(podA, []bandwidthledger.BandwidthReservation{
+			{From: "node-1", To: "node-2", BandwidthKbps: 50000},
+		})
+		ledger.Reserve(podB, []bandwidthledger.BandwidthReservation{
+			{From: "node-1", To: "node-2", BandwidthKbps: 30000},
+		})
+
+		ledger.Release(podA)
+		Expect(ledger.ReservedBandwidthKbps("node-1", "node-2")).To(Equal(int64(30000)))
+
+		ledger.Release(podA)
+		ledger.Release(podB)
+		Expect(ledger.ReservedBandwidthKbps("node-1", "node-2")).To(Equal(int64(0)))
+	})
+
+	It("computes the residual bandwidth of a network link", func() {
+		region := regiongraph.NewRegionGraph()
+		node1 := region.NewNode("node-1")
+		region.AddNode(node1)
+		node2 := region.NewNode("node-2")
+		region.AddNode(node2)
+		qos := &cluster.NetworkLinkQoS{
+			Throughput: cluster.NetworkThroughput{BandwidthKbps: 100000},
+		}
+		region.SetEdge(region.NewEdge(node1, node2, qos, nil))
+
+		ledger.Reserve(podA, bandwidthledger.NewBandwidthReservationsForPath([]graph.Node{node2, node1}, 60000))
+		Expect(ledger.ResidualBandwidthKbps(region.Edge("node-1", "node-2"))).To(Equal(int64(40000)))
+
+		ledger.Reserve(podB, bandwidthledger.NewBandwidthReservationsForPath([]graph.Node{node1, node2}, 60000))
+		Expect(ledger.ResidualBandwidthKbps(region.Edge("node-1", "node-2"))).To(Equal(int64(-20000)))
+	})
+
+	It("restores the reservations stored in a pod annotation", func() {
+		reservations := []bandwidthledger.BandwidthReservation{
+			{From: "node-1", To: "node-2", BandwidthKbps: 50000},
+			{From: "node-2", To: "node-3", BandwidthKbps: 50000},
+		}
+		value, err := bandwidthledger.EncodeReservationsAnnotation(reservations)
+		Expect(err).ToNot(HaveOccurred())
+		podA.Annotations = map[string]string{kubeutil.AnnotationBandwidthReservations: value}
+
+		restored, found, err := bandwidthledger.GetReservationsFromAnnotation(podA)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(restored).To(Equal(reservations))
+
+		_, found, err = bandwidthledger.GetReservationsFromAnnotation(podB)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		podB.Annotations = map[string]string{kubeutil.AnnotationBandwidthReservations: "invalid"}
+		_, found, err = bandwidthledger.GetReservationsFromAnnotation(podB)
+		Expect(err).To(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	Context("directed", func() {
+
+		// Creates a directed RegionGraph with an asymmetric network link between node-1 and node-2.
+		newAsymmetricRegion := func() regiongraph.RegionGraph {
+			region := regiongraph.NewDirectedRegionGraph()
+			node1 := region.NewNode("node-1")
+			region.AddNode(node1)
+			node2 := region.NewNode("node-2")
+			region.AddNode(node2)
+			downlink := &cluster.NetworkLinkQoS{Throughput: cluster.NetworkThroughput{BandwidthKbps: 100000}}
+			uplink := &cluster.NetworkLinkQoS{Throughput: cluster.NetworkThroughput{BandwidthKbps: 2000}}
+			region.SetEdge(region.NewEdge(node1, node2, downlink, nil))
+			region.SetEdge(region.NewEdge(node2, node1, uplink, nil))
+			return region
+		}
+
+		BeforeEach(func() {
+			ledger = bandwidthledger.NewBandwidthLedger(true)
+		})
+
+		It("distinguishes the reservations by direction", func() {
+			ledger.Reserve(podA, []bandwidthledger.BandwidthReservation{
+				{From: "node-1", To: "node-2", BandwidthKbps: 50000},
+			})
+			ledger.Reserve(podB, []bandwidthledger.BandwidthReservation{
+				{From: "node-2", To: "node-1", BandwidthKbps: 1000},
+			})
+
+			Expect(ledger.ReservedBandwidthKbps("node-1", "node-2")).To(Equal(int64(50000)))
+			Expect(ledger.ReservedBandwidthKbps("node-2", "node-1")).To(Equal(int64(1000)))
+
+			ledger.Release(podA)
+			Expect(ledger.ReservedBandwidthKbps("node-1", "node-2")).To(Equal(int64(0)))
+			Expect(ledger.ReservedBandwidthKbps("node-2", "node-1")).To(Equal(int64(1000)))
+		})
+
+		It("does not reduce the residual bandwidth of the opposite direction of an asymmetric network link", func() {
+			region := newAsymmetricRegion()
+			path := []graph.Node{region.NodeByLabel("node-1"), region.NodeByLabel("node-2")}
+			ledger.Reserve(podA, bandwidthledger.NewBandwidthReservationsForPath(path, 50000))
+
+			Expect(ledger.ResidualBandwidthKbps(region.Edge("node-1", "node-2"))).To(Equal(int64(50000)))
+			Expect(ledger.ResidualBandwidthKbps(region.Edge("node-2", "node-1"))).To(Equal(int64(2000)))
+		})
+
+	})
+
+})
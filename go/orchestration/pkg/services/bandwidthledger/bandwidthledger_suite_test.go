@@ -0,0 +1,13 @@
+package bandwidthledger_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBandwidthLedger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BandwidthLedger Suite")
+}
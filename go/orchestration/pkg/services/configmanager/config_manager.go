@@ -5,10 +5,6 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-var (
-	configMgrInstance ConfigManager
-)
-
 // ConfigManager provides easy access to the controller's configuration.
 type ConfigManager interface {
 
@@ -19,16 +15,7 @@ type ConfigManager interface {
 	Scheme() *runtime.Scheme
 }
 
-// Gets the ConfigManager's singleton instance or panics, if it hasn't been initialized.
-func GetConfigManager() ConfigManager {
-	if configMgrInstance == nil {
-		panic("ConfigManager has not been initialized. Did you call configmanager.InitConfigManager()?")
-	}
-	return configMgrInstance
-}
-
-// Initializes the ConfigManager with the specified configuration and scheme.
-func InitConfigManager(restConfig *rest.Config, scheme *runtime.Scheme) ConfigManager {
-	configMgrInstance = newConfigManagerImpl(restConfig, scheme)
-	return configMgrInstance
+// NewConfigManager creates a new ConfigManager with the specified configuration and scheme.
+func NewConfigManager(restConfig *rest.Config, scheme *runtime.Scheme) ConfigManager {
+	return newConfigManagerImpl(restConfig, scheme)
 }
@@ -0,0 +1,67 @@
+package regionmanager
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+var (
+	_fakeRegionManager *FakeRegionManager
+
+	_ RegionManager = _fakeRegionManager
+)
+
+// FakeRegionManager is an in-memory RegionManager for unit tests.
+//
+// It does not watch any cluster, instead the RegionGraph is set explicitly using SetRegionGraph().
+type FakeRegionManager struct {
+	pathCache     RegionPathCache
+	orphanedLinks []types.NamespacedName
+	mutex         sync.RWMutex
+}
+
+// NewFakeRegionManager creates a new FakeRegionManager with the specified RegionGraph.
+// If region is nil, an empty directed RegionGraph is used.
+func NewFakeRegionManager(region regiongraph.RegionGraph) *FakeRegionManager {
+	if region == nil {
+		region = regiongraph.NewDirectedRegionGraph()
+	}
+	return &FakeRegionManager{
+		pathCache:     NewRegionPathCache(region, 1),
+		orphanedLinks: []types.NamespacedName{},
+	}
+}
+
+// SetRegionGraph replaces the current RegionGraph and increments the generation of the RegionPathCache.
+func (me *FakeRegionManager) SetRegionGraph(region regiongraph.RegionGraph) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	me.pathCache = NewRegionPathCache(region, me.pathCache.Generation()+1)
+}
+
+// SetOrphanedNetworkLinks sets the NetworkLinks returned by OrphanedNetworkLinks().
+func (me *FakeRegionManager) SetOrphanedNetworkLinks(links []types.NamespacedName) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	me.orphanedLinks = links
+}
+
+func (me *FakeRegionManager) RegionGraph() regiongraph.RegionGraph {
+	return me.PathCache().RegionGraph()
+}
+
+func (me *FakeRegionManager) PathCache() RegionPathCache {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	return me.pathCache
+}
+
+func (me *FakeRegionManager) OrphanedNetworkLinks() []types.NamespacedName {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	return me.orphanedLinks
+}
+
+func (me *FakeRegionManager) Stop() {}
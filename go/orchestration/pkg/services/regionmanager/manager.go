@@ -1,29 +1,49 @@
 package regionmanager
 
 import (
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
-)
-
-var (
-	instance RegionManager
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // RegionManager provides methods for obtaining information about a fog region
 type RegionManager interface {
 	// RegionGraph gets a graph that represents that current state of the region.
+	//
+	// The RegionGraph contains all nodes of the cluster, including nodes that are not connected by any NetworkLink,
+	// as well as all nodes referenced by NetworkLinks, even if they are not nodes of the cluster.
 	RegionGraph() regiongraph.RegionGraph
+
+	// PathCache gets the RegionPathCache for the current generation of the RegionGraph.
+	//
+	// Use PathCache().RegionGraph() to obtain the RegionGraph, if the graph needs to be consistent with the cached paths.
+	PathCache() RegionPathCache
+
+	// OrphanedNetworkLinks returns the names of the NetworkLinks that reference at least one node that does not exist in the cluster.
+	//
+	// Orphaned NetworkLinks are still part of the RegionGraph, because they may connect network devices that are not Kubernetes nodes.
+	OrphanedNetworkLinks() []types.NamespacedName
+
+	// Stop stops watching the NetworkLinks and nodes of the cluster.
+	// Afterwards, the RegionGraph is not updated anymore.
+	// Calling Stop() more than once has no effect.
+	Stop()
 }
 
-// GetRegionManager returns the singleton instance of the RegionManager.
-func GetRegionManager() RegionManager {
-	if instance != nil {
-		return instance
+// NewRegionManager creates a new RegionManager, which watches the NetworkLinks and nodes of the cluster configured by the ConfigManager.
+// The initial RegionGraph is built before this function returns.
+func NewRegionManager(configMgr configmanager.ConfigManager) (RegionManager, error) {
+	cl, err := client.NewWithWatch(configMgr.RestConfig(), client.Options{Scheme: configMgr.Scheme()})
+	if err != nil {
+		return nil, err
 	}
-	panic("RegionManager singleton has not been initialized. Did you call InitRegionManager()?")
+	return NewRegionManagerWithClient(cl)
 }
 
-// Initializes the singleton instance of the RegionManager.
-func InitRegionManager() RegionManager {
-	instance = newRegionManagerImpl()
-	return instance
+// NewRegionManagerWithClient creates a new RegionManager, which uses the specified client for watching the NetworkLinks and nodes.
+// This allows running the RegionManager against an in-memory client, e.g., in a simulation.
+// The initial RegionGraph is built before this function returns.
+func NewRegionManagerWithClient(cl client.WithWatch) (RegionManager, error) {
+	return newRegionManagerImpl(cl)
 }
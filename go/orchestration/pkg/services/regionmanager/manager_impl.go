@@ -1,13 +1,17 @@
 package regionmanager
 
 import (
+	"sort"
 	"sync/atomic"
+	"time"
 
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
@@ -16,58 +20,352 @@ var (
 	_ RegionManager = _regionManagerImpl
 )
 
+const (
+	// The maximum age of the measured QoS in the status of a NetworkLink for it to take precedence over the QoS in the spec.
+	networkLinkStatusMaxAge = 5 * time.Minute
+)
+
 type regionManagerImpl struct {
-	regionGraph atomic.Value
-	watcher     kubeutil.ListWatcher
+	// Stores the RegionPathCache for the current generation of the RegionGraph, which also holds the RegionGraph itself.
+	pathCache atomic.Value
+
+	// Stores the []types.NamespacedName of the NetworkLinks that reference nodes, which do not exist in the cluster.
+	orphanedLinks atomic.Value
+
+	// The generation of the current RegionGraph.
+	// This is only accessed by the goroutine that builds the RegionGraph.
+	generation int64
+
+	// The NetworkLinks, from which the current RegionGraph has been built.
+	// This is only accessed by the goroutine that builds the RegionGraph.
+	networkLinks map[types.NamespacedName]*networkLinkInfo
+
+	// The names of the nodes that exist in the cluster.
+	// This is only accessed by the goroutine that builds the RegionGraph.
+	k8sNodes map[string]bool
+
+	linksWatcher kubeutil.ListWatcher
+	nodesWatcher kubeutil.ListWatcher
+}
+
+// Stores a NetworkLink that is part of the current RegionGraph.
+type networkLinkInfo struct {
+	networkLink *cluster.NetworkLink
+
+	// The time at which the measured QoS, which was used for the NetworkLink's edges, becomes stale
+	// or nil, if the edges were created from the QoS in the spec.
+	staleAt *time.Time
+
+	// True if the NetworkLink references a node that does not exist in the cluster.
+	orphaned bool
 }
 
-func newRegionManagerImpl() *regionManagerImpl {
-	configMgr := configmanager.GetConfigManager()
-	watcher, err := kubeutil.StartListWatcher(&cluster.NetworkLinkList{}, configMgr.RestConfig(), configMgr.Scheme())
+func newRegionManagerImpl(cl client.WithWatch) (*regionManagerImpl, error) {
+	linksWatcher, err := kubeutil.StartListWatcherWithClient(&cluster.NetworkLinkList{}, cl)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	nodesWatcher, err := kubeutil.StartListWatcherWithClient(&core.NodeList{}, cl)
+	if err != nil {
+		linksWatcher.Stop()
+		return nil, err
 	}
 	regionMgr := &regionManagerImpl{
-		watcher: watcher,
+		linksWatcher: linksWatcher,
+		nodesWatcher: nodesWatcher,
 	}
 
 	// Build the initial region graph
-	networkLinks := (<-watcher.WatchChan()).(*cluster.NetworkLinkList)
-	regionGraph := regionMgr.buildRegionGraph(networkLinks)
-	regionMgr.regionGraph.Store(regionGraph)
+	regionMgr.setK8sNodes(nodesWatcher.InitialList().(*core.NodeList))
+	regionGraph := regionMgr.buildRegionGraph(linksWatcher.InitialList().(*cluster.NetworkLinkList))
+	regionMgr.updateOrphanedLinks()
+	regionMgr.storeRegionGraph(regionGraph)
 
-	go regionMgr.watchNetworkLinks()
-	return regionMgr
+	go regionMgr.watchRegion()
+	return regionMgr, nil
 }
 
 func (me *regionManagerImpl) RegionGraph() regiongraph.RegionGraph {
-	return me.regionGraph.Load().(regiongraph.RegionGraph)
+	return me.PathCache().RegionGraph()
+}
+
+func (me *regionManagerImpl) PathCache() RegionPathCache {
+	return me.pathCache.Load().(RegionPathCache)
+}
+
+func (me *regionManagerImpl) OrphanedNetworkLinks() []types.NamespacedName {
+	return me.orphanedLinks.Load().([]types.NamespacedName)
+}
+
+func (me *regionManagerImpl) Stop() {
+	me.linksWatcher.Stop()
+	me.nodesWatcher.Stop()
 }
 
-func (me *regionManagerImpl) watchNetworkLinks() {
-	watchChan := me.watcher.WatchChan()
-	for networkLinks := range watchChan {
-		updatedGraph := me.buildRegionGraph(networkLinks.(*cluster.NetworkLinkList))
-		me.regionGraph.Store(updatedGraph)
+// Updates the RegionGraph whenever the NetworkLinks or the nodes of the cluster change or when the measured QoS of a NetworkLink becomes stale.
+//
+// The RegionGraph is updated using copy-on-write: the deltas are applied to a copy of the current RegionGraph,
+// which then replaces the current one, so that readers always see a consistent snapshot.
+// All deltas that are available at the same time are applied to the same copy.
+// Deltas that do not change anything, e.g., status updates of nodes that are already known, neither copy nor replace the RegionGraph.
+func (me *regionManagerImpl) watchRegion() {
+	linkDeltas := me.linksWatcher.DeltaChan()
+	nodeDeltas := me.nodesWatcher.DeltaChan()
+	for {
+		var staleTimer <-chan time.Time
+		if staleAt := me.getNextStaleTime(); staleAt != nil {
+			staleTimer = time.After(time.Until(*staleAt))
+		}
+
+		region := newCopyOnWriteRegionGraph(me.RegionGraph())
+		changesCount := 0
+		select {
+		case delta, ok := <-linkDeltas:
+			if !ok {
+				return
+			}
+			me.applyNetworkLinkDelta(region, delta)
+			changesCount++
+		case delta, ok := <-nodeDeltas:
+			if !ok {
+				return
+			}
+			if me.applyNodeDelta(region, delta) {
+				changesCount++
+			}
+		case <-staleTimer:
+			me.refreshStaleNetworkLinks(region.Writable(), time.Now())
+			changesCount++
+		}
+
+		changesCount += me.applyPendingDeltas(region, linkDeltas, nodeDeltas)
+		if changesCount == 0 {
+			continue
+		}
+		klog.Infof("Applied %v changes to the RegionGraph.", changesCount)
+		me.updateOrphanedLinks()
+		if region.IsModified() {
+			me.storeRegionGraph(region.Writable())
+		}
 	}
 }
 
+// Applies all deltas that are immediately available on the linkDeltas and nodeDeltas channels to the region
+// and returns the number of deltas that changed the NetworkLinks or the nodes of the cluster.
+func (me *regionManagerImpl) applyPendingDeltas(
+	region *copyOnWriteRegionGraph,
+	linkDeltas <-chan kubeutil.ListWatchDelta,
+	nodeDeltas <-chan kubeutil.ListWatchDelta,
+) int {
+	changesCount := 0
+	for {
+		select {
+		case delta, ok := <-linkDeltas:
+			if !ok {
+				return changesCount
+			}
+			me.applyNetworkLinkDelta(region, delta)
+			changesCount++
+		case delta, ok := <-nodeDeltas:
+			if !ok {
+				return changesCount
+			}
+			if me.applyNodeDelta(region, delta) {
+				changesCount++
+			}
+		default:
+			return changesCount
+		}
+	}
+}
+
+// Applies a NetworkLink delta to the region.
+// On a resync, a new region is built.
+func (me *regionManagerImpl) applyNetworkLinkDelta(region *copyOnWriteRegionGraph, delta kubeutil.ListWatchDelta) {
+	switch delta.Type {
+	case kubeutil.ListWatchAdded, kubeutil.ListWatchModified:
+		me.setNetworkLink(region.Writable(), delta.Object.(*cluster.NetworkLink), time.Now())
+	case kubeutil.ListWatchDeleted:
+		me.removeNetworkLink(region.Writable(), delta.Object.(*cluster.NetworkLink))
+	case kubeutil.ListWatchResync:
+		region.Replace(me.buildRegionGraph(delta.List.(*cluster.NetworkLinkList)))
+	}
+}
+
+// Applies a Node delta to the region and returns true if the nodes of the cluster have changed.
+//
+// Nodes without NetworkLinks are added to the region as isolated nodes.
+// When a node is deleted, it is only removed from the region, if none of the NetworkLinks reference it anymore.
+// Modifications of nodes that are already known do not change anything, because the RegionGraph only depends on the node names.
+func (me *regionManagerImpl) applyNodeDelta(region *copyOnWriteRegionGraph, delta kubeutil.ListWatchDelta) bool {
+	switch delta.Type {
+	case kubeutil.ListWatchAdded, kubeutil.ListWatchModified:
+		nodeName := delta.Object.GetName()
+		if me.k8sNodes[nodeName] {
+			return false
+		}
+		me.k8sNodes[nodeName] = true
+		if region.Current().NodeByLabel(nodeName) == nil {
+			getOrCreateNode(region.Writable(), nodeName)
+		}
+	case kubeutil.ListWatchDeleted:
+		nodeName := delta.Object.GetName()
+		if !me.k8sNodes[nodeName] {
+			return false
+		}
+		delete(me.k8sNodes, nodeName)
+		if isUnconnected(region.Current(), nodeName) {
+			region.Writable().RemoveNode(nodeName)
+		}
+	case kubeutil.ListWatchResync:
+		me.setK8sNodes(delta.List.(*core.NodeList))
+		writable := region.Writable()
+		for nodeName := range me.k8sNodes {
+			getOrCreateNode(writable, nodeName)
+		}
+		for _, nodeName := range getNodeNames(writable) {
+			me.removeNodeIfUnconnected(writable, nodeName)
+		}
+	}
+	return true
+}
+
+// Stores the RegionGraph as the current one and creates a new RegionPathCache for it,
+// which replaces the cache of the previous generation.
+func (me *regionManagerImpl) storeRegionGraph(regionGraph regiongraph.RegionGraph) {
+	me.generation++
+	me.pathCache.Store(NewRegionPathCache(regionGraph, me.generation))
+}
+
+// Replaces the stored names of the nodes in the cluster.
+func (me *regionManagerImpl) setK8sNodes(nodes *core.NodeList) {
+	me.k8sNodes = make(map[string]bool, len(nodes.Items))
+	for i := range nodes.Items {
+		me.k8sNodes[nodes.Items[i].Name] = true
+	}
+}
+
+// Builds a new directed RegionGraph from the NetworkLinks and the nodes of the cluster and replaces the stored NetworkLinks.
 func (me *regionManagerImpl) buildRegionGraph(networkLinks *cluster.NetworkLinkList) regiongraph.RegionGraph {
-	region := regiongraph.NewRegionGraph()
+	region := regiongraph.NewDirectedRegionGraph()
+	me.networkLinks = make(map[types.NamespacedName]*networkLinkInfo, len(networkLinks.Items))
+	now := time.Now()
 
+	for nodeName := range me.k8sNodes {
+		getOrCreateNode(region, nodeName)
+	}
 	for i := range networkLinks.Items {
-		networkLink := &networkLinks.Items[i]
-		fromNode := getOrCreateNode(region, networkLink.Spec.NodeA)
-		toNode := getOrCreateNode(region, networkLink.Spec.NodeB)
-
-		edge := region.NewEdge(fromNode, toNode, &networkLink.Spec.QoS)
-		region.SetEdge(edge)
+		me.setNetworkLink(region, &networkLinks.Items[i], now)
 	}
 
-	klog.Infof("Successfully built a RegionGraph with %v links.", len(networkLinks.Items))
+	klog.Infof("Successfully built a RegionGraph with %v nodes and %v links.", len(me.k8sNodes), len(networkLinks.Items))
 	return region
 }
 
+// Adds the edges for both directions of the NetworkLink to the region or replaces them, if the NetworkLink already exists.
+//
+// If the measured QoS in the status of a NetworkLink is fresh, it is used instead of the QoS from the spec.
+func (me *regionManagerImpl) setNetworkLink(region regiongraph.RegionGraph, networkLink *cluster.NetworkLink, now time.Time) {
+	key := client.ObjectKeyFromObject(networkLink)
+	linkInfo := &networkLinkInfo{networkLink: networkLink}
+	if prevLink, ok := me.networkLinks[key]; ok {
+		prevSpec := &prevLink.networkLink.Spec
+		if prevSpec.NodeA != networkLink.Spec.NodeA || prevSpec.NodeB != networkLink.Spec.NodeB {
+			me.removeNetworkLink(region, prevLink.networkLink)
+		} else {
+			linkInfo.orphaned = prevLink.orphaned
+		}
+	}
+
+	if networkLink.Status.HasFreshMeasuredQoS(now, networkLinkStatusMaxAge) {
+		staleAt := networkLink.Status.LastProbeTime.Add(networkLinkStatusMaxAge)
+		linkInfo.staleAt = &staleAt
+	}
+	me.networkLinks[key] = linkInfo
+
+	addNetworkLinkEdges(region, networkLink, now)
+}
+
+// Removes the edges of the NetworkLink from the region and removes its nodes, if they are neither nodes of the cluster
+// nor connected to any other node anymore.
+func (me *regionManagerImpl) removeNetworkLink(region regiongraph.RegionGraph, networkLink *cluster.NetworkLink) {
+	delete(me.networkLinks, client.ObjectKeyFromObject(networkLink))
+
+	nodeA := networkLink.Spec.NodeA
+	nodeB := networkLink.Spec.NodeB
+	region.RemoveEdge(nodeA, nodeB)
+	region.RemoveEdge(nodeB, nodeA)
+	me.removeNodeIfUnconnected(region, nodeA)
+	me.removeNodeIfUnconnected(region, nodeB)
+}
+
+// Removes the node with the specified name from the region, if it is not a node of the cluster and does not have any network links.
+// Since every NetworkLink creates edges in both directions, a node without outgoing edges also has no incoming edges.
+func (me *regionManagerImpl) removeNodeIfUnconnected(region regiongraph.RegionGraph, nodeName string) {
+	if !me.k8sNodes[nodeName] && isUnconnected(region, nodeName) {
+		region.RemoveNode(nodeName)
+	}
+}
+
+// Recreates the edges of all NetworkLinks, whose measured QoS has become stale, such that they use the QoS from the spec.
+func (me *regionManagerImpl) refreshStaleNetworkLinks(region regiongraph.RegionGraph, now time.Time) {
+	for _, linkInfo := range me.networkLinks {
+		if linkInfo.staleAt != nil && !linkInfo.staleAt.After(now) {
+			me.setNetworkLink(region, linkInfo.networkLink, now)
+		}
+	}
+}
+
+// Returns the earliest time, at which the measured QoS of one of the NetworkLinks becomes stale
+// or nil, if no measured QoS values are used.
+func (me *regionManagerImpl) getNextStaleTime() *time.Time {
+	var nextStaleAt *time.Time
+	for _, linkInfo := range me.networkLinks {
+		if linkInfo.staleAt != nil && (nextStaleAt == nil || linkInfo.staleAt.Before(*nextStaleAt)) {
+			nextStaleAt = linkInfo.staleAt
+		}
+	}
+	return nextStaleAt
+}
+
+// Flags the NetworkLinks that reference nodes, which do not exist in the cluster, and stores them as the orphaned NetworkLinks.
+// A warning is logged for every NetworkLink that has become orphaned since the last update.
+//
+// Orphaned NetworkLinks remain in the RegionGraph, because they may connect network devices (e.g., routers) that are not Kubernetes nodes.
+func (me *regionManagerImpl) updateOrphanedLinks() {
+	orphanedLinks := make([]types.NamespacedName, 0)
+	for key, linkInfo := range me.networkLinks {
+		spec := &linkInfo.networkLink.Spec
+		orphaned := !me.k8sNodes[spec.NodeA] || !me.k8sNodes[spec.NodeB]
+		if orphaned && !linkInfo.orphaned {
+			klog.Warningf("The NetworkLink %s references a node that does not exist in the cluster (nodeA: %s, nodeB: %s).", key, spec.NodeA, spec.NodeB)
+		}
+		linkInfo.orphaned = orphaned
+		if orphaned {
+			orphanedLinks = append(orphanedLinks, key)
+		}
+	}
+
+	sort.Slice(orphanedLinks, func(i, j int) bool {
+		return orphanedLinks[i].String() < orphanedLinks[j].String()
+	})
+	me.orphanedLinks.Store(orphanedLinks)
+}
+
+// Adds the edges for both directions of the NetworkLink to the region, creating its nodes, if necessary.
+func addNetworkLinkEdges(region regiongraph.RegionGraph, networkLink *cluster.NetworkLink, now time.Time) {
+	fromNode := getOrCreateNode(region, networkLink.Spec.NodeA)
+	toNode := getOrCreateNode(region, networkLink.Spec.NodeB)
+	region.SetEdge(region.NewEdge(fromNode, toNode, networkLink.EffectiveQoS(now, networkLinkStatusMaxAge), networkLink.Spec.Trust))
+	region.SetEdge(region.NewEdge(toNode, fromNode, networkLink.EffectiveQoSBtoA(now, networkLinkStatusMaxAge), networkLink.Spec.Trust))
+}
+
+// Returns true if the region contains the node with the specified name and the node does not have any outgoing edges.
+func isUnconnected(region regiongraph.RegionGraph, nodeName string) bool {
+	node := region.NodeByLabel(nodeName)
+	return node != nil && region.Graph().From(node.ID()).Len() == 0
+}
+
 func getOrCreateNode(region regiongraph.RegionGraph, nodeName string) regiongraph.Node {
 	node := region.NodeByLabel(nodeName)
 	if node == nil {
@@ -76,3 +374,51 @@ func getOrCreateNode(region regiongraph.RegionGraph, nodeName string) regiongrap
 	}
 	return node
 }
+
+// Returns the labels of all nodes of the region.
+func getNodeNames(region regiongraph.RegionGraph) []string {
+	nodes := region.Graph().Nodes()
+	nodeNames := make([]string, 0, nodes.Len())
+	for nodes.Next() {
+		nodeNames = append(nodeNames, nodes.Node().(regiongraph.Node).Label())
+	}
+	return nodeNames
+}
+
+// Wraps the current RegionGraph and copies it when it is modified for the first time.
+// This allows applying deltas that may not change anything without copying the RegionGraph.
+type copyOnWriteRegionGraph struct {
+	current regiongraph.RegionGraph
+	copy    regiongraph.RegionGraph
+}
+
+func newCopyOnWriteRegionGraph(current regiongraph.RegionGraph) *copyOnWriteRegionGraph {
+	return &copyOnWriteRegionGraph{current: current}
+}
+
+// Current returns the copy, if the RegionGraph has been modified, and the current RegionGraph otherwise.
+// The returned RegionGraph must not be modified.
+func (me *copyOnWriteRegionGraph) Current() regiongraph.RegionGraph {
+	if me.copy != nil {
+		return me.copy
+	}
+	return me.current
+}
+
+// Writable returns the copy of the RegionGraph, which may be modified, and creates it, if necessary.
+func (me *copyOnWriteRegionGraph) Writable() regiongraph.RegionGraph {
+	if me.copy == nil {
+		me.copy = me.current.Clone()
+	}
+	return me.copy
+}
+
+// Replace replaces the copy with the specified RegionGraph.
+func (me *copyOnWriteRegionGraph) Replace(region regiongraph.RegionGraph) {
+	me.copy = region
+}
+
+// IsModified returns true if the RegionGraph has been copied or replaced.
+func (me *copyOnWriteRegionGraph) IsModified() bool {
+	return me.copy != nil
+}
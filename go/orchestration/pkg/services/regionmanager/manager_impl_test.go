@@ -0,0 +1,187 @@
+package regionmanager
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+var _ = Describe("regionManagerImpl deltas", func() {
+
+	var regionMgr *regionManagerImpl
+	var original regiongraph.RegionGraph
+
+	newNode := func(name string) *core.Node {
+		return &core.Node{ObjectMeta: meta.ObjectMeta{Name: name}}
+	}
+
+	newNetworkLink := func(name string, nodeA string, nodeB string, bandwidthKbps int64) *cluster.NetworkLink {
+		return &cluster.NetworkLink{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: cluster.NetworkLinkSpec{
+				NodeA: nodeA,
+				NodeB: nodeB,
+				QoS: cluster.NetworkLinkQoS{
+					Throughput: cluster.NetworkThroughput{BandwidthKbps: bandwidthKbps},
+				},
+			},
+		}
+	}
+
+	// Applies the delta to a copy of the original RegionGraph and returns the resulting RegionGraph.
+	applyLinkDelta := func(deltaType kubeutil.ListWatchDeltaType, link *cluster.NetworkLink) regiongraph.RegionGraph {
+		region := newCopyOnWriteRegionGraph(original)
+		regionMgr.applyNetworkLinkDelta(region, kubeutil.ListWatchDelta{Type: deltaType, Object: link})
+		Expect(region.IsModified()).To(BeTrue())
+		return region.Current()
+	}
+
+	bandwidth := func(region regiongraph.RegionGraph, from string, to string) int64 {
+		edge := region.Edge(from, to)
+		Expect(edge).ToNot(BeNil(), "%s->%s", from, to)
+		return edge.NetworkLinkQoS().Throughput.BandwidthKbps
+	}
+
+	BeforeEach(func() {
+		regionMgr = &regionManagerImpl{}
+		regionMgr.setK8sNodes(&core.NodeList{Items: []core.Node{*newNode("edge-1"), *newNode("edge-2"), *newNode("cloud")}})
+		original = regionMgr.buildRegionGraph(&cluster.NetworkLinkList{Items: []cluster.NetworkLink{
+			*newNetworkLink("edge-1-to-edge-2", "edge-1", "edge-2", 100000),
+			*newNetworkLink("edge-2-to-router", "edge-2", "router", 10000),
+		}})
+	})
+
+	It("adds the edges of new NetworkLinks without modifying the original RegionGraph", func() {
+		region := applyLinkDelta(kubeutil.ListWatchAdded, newNetworkLink("edge-2-to-cloud", "edge-2", "cloud", 50000))
+
+		Expect(bandwidth(region, "edge-2", "cloud")).To(Equal(int64(50000)))
+		Expect(bandwidth(region, "cloud", "edge-2")).To(Equal(int64(50000)))
+		Expect(original.Edge("edge-2", "cloud")).To(BeNil())
+	})
+
+	It("moves the edges of a NetworkLink, whose endpoints have changed", func() {
+		region := applyLinkDelta(kubeutil.ListWatchModified, newNetworkLink("edge-2-to-router", "edge-2", "cloud", 20000))
+
+		Expect(region.Edge("edge-2", "router")).To(BeNil())
+		Expect(region.Edge("router", "edge-2")).To(BeNil())
+		Expect(region.NodeByLabel("router")).To(BeNil())
+		Expect(bandwidth(region, "edge-2", "cloud")).To(Equal(int64(20000)))
+		Expect(original.NodeByLabel("router")).ToNot(BeNil())
+	})
+
+	It("removes nodes that are not in the cluster when their last NetworkLink is deleted", func() {
+		region := applyLinkDelta(kubeutil.ListWatchDeleted, newNetworkLink("edge-2-to-router", "edge-2", "router", 10000))
+		Expect(region.NodeByLabel("router")).To(BeNil())
+		Expect(region.NodeByLabel("edge-2")).ToNot(BeNil())
+
+		original = region
+		region = applyLinkDelta(kubeutil.ListWatchDeleted, newNetworkLink("edge-1-to-edge-2", "edge-1", "edge-2", 100000))
+		Expect(region.Graph().Nodes().Len()).To(Equal(3))
+		Expect(region.Edge("edge-1", "edge-2")).To(BeNil())
+	})
+
+	It("removes deleted nodes only once they are not referenced by NetworkLinks anymore", func() {
+		region := newCopyOnWriteRegionGraph(original)
+		Expect(regionMgr.applyNodeDelta(region, kubeutil.ListWatchDelta{Type: kubeutil.ListWatchDeleted, Object: newNode("cloud")})).To(BeTrue())
+		Expect(region.Current().NodeByLabel("cloud")).To(BeNil())
+
+		Expect(regionMgr.applyNodeDelta(region, kubeutil.ListWatchDelta{Type: kubeutil.ListWatchDeleted, Object: newNode("edge-1")})).To(BeTrue())
+		Expect(region.Current().NodeByLabel("edge-1")).ToNot(BeNil())
+
+		original = region.Current()
+		region2 := applyLinkDelta(kubeutil.ListWatchDeleted, newNetworkLink("edge-1-to-edge-2", "edge-1", "edge-2", 100000))
+		Expect(region2.NodeByLabel("edge-1")).To(BeNil())
+	})
+
+	It("does not copy the RegionGraph for modifications of known nodes", func() {
+		region := newCopyOnWriteRegionGraph(original)
+		Expect(regionMgr.applyNodeDelta(region, kubeutil.ListWatchDelta{Type: kubeutil.ListWatchModified, Object: newNode("edge-1")})).To(BeFalse())
+		Expect(regionMgr.applyNodeDelta(region, kubeutil.ListWatchDelta{Type: kubeutil.ListWatchDeleted, Object: newNode("unknown")})).To(BeFalse())
+		Expect(region.IsModified()).To(BeFalse())
+
+		Expect(regionMgr.applyNodeDelta(region, kubeutil.ListWatchDelta{Type: kubeutil.ListWatchAdded, Object: newNode("edge-3")})).To(BeTrue())
+		Expect(region.IsModified()).To(BeTrue())
+		Expect(region.Current().NodeByLabel("edge-3")).ToNot(BeNil())
+		Expect(original.NodeByLabel("edge-3")).To(BeNil())
+	})
+
+	It("replaces the measured QoS of a NetworkLink with its spec QoS once it becomes stale", func() {
+		now := time.Now()
+		probedLink := newNetworkLink("edge-1-to-edge-2", "edge-1", "edge-2", 100000)
+		probedLink.Status.MeasuredQoS = &cluster.NetworkLinkMeasuredQoS{Throughput: cluster.NetworkThroughput{BandwidthKbps: 80000}}
+		probedLink.Status.LastProbeTime = &meta.Time{Time: now.Add(-networkLinkStatusMaxAge + time.Minute)}
+
+		region := applyLinkDelta(kubeutil.ListWatchModified, probedLink)
+		Expect(bandwidth(region, "edge-1", "edge-2")).To(Equal(int64(80000)))
+		staleAt := regionMgr.getNextStaleTime()
+		Expect(staleAt).ToNot(BeNil())
+		Expect(*staleAt).To(BeTemporally("~", now.Add(time.Minute), time.Second))
+
+		// Refreshing before the QoS is stale does not change anything.
+		regionMgr.refreshStaleNetworkLinks(region, now)
+		Expect(bandwidth(region, "edge-1", "edge-2")).To(Equal(int64(80000)))
+
+		regionMgr.refreshStaleNetworkLinks(region, staleAt.Add(time.Second))
+		Expect(bandwidth(region, "edge-1", "edge-2")).To(Equal(int64(100000)))
+		Expect(bandwidth(region, "edge-2", "edge-1")).To(Equal(int64(100000)))
+		Expect(regionMgr.getNextStaleTime()).To(BeNil())
+	})
+
+	It("uses the QoSBtoA for the direction from NodeB to NodeA and the measured QoS only for the direction from NodeA to NodeB", func() {
+		asymmetricLink := newNetworkLink("edge-2-to-cloud", "edge-2", "cloud", 2000)
+		asymmetricLink.Spec.QoSBtoA = &cluster.NetworkLinkQoS{Throughput: cluster.NetworkThroughput{BandwidthKbps: 100000}}
+		asymmetricLink.Status.MeasuredQoS = &cluster.NetworkLinkMeasuredQoS{Throughput: cluster.NetworkThroughput{BandwidthKbps: 1500}}
+		asymmetricLink.Status.LastProbeTime = &meta.Time{Time: time.Now()}
+		region := applyLinkDelta(kubeutil.ListWatchAdded, asymmetricLink)
+		Expect(bandwidth(region, "edge-2", "cloud")).To(Equal(int64(1500)))
+		Expect(bandwidth(region, "cloud", "edge-2")).To(Equal(int64(100000)))
+
+		// Without a QoSBtoA, the NetworkLink is symmetric, so the measured QoS applies to both directions.
+		symmetricLink := newNetworkLink("edge-1-to-cloud", "edge-1", "cloud", 2000)
+		symmetricLink.Status = asymmetricLink.Status
+		original = region
+		region = applyLinkDelta(kubeutil.ListWatchAdded, symmetricLink)
+		Expect(bandwidth(region, "edge-1", "cloud")).To(Equal(int64(1500)))
+		Expect(bandwidth(region, "cloud", "edge-1")).To(Equal(int64(1500)))
+	})
+
+	It("rebuilds the RegionGraph on a NetworkLink resync", func() {
+		region := newCopyOnWriteRegionGraph(original)
+		regionMgr.applyNetworkLinkDelta(region, kubeutil.ListWatchDelta{
+			Type: kubeutil.ListWatchResync,
+			List: &cluster.NetworkLinkList{Items: []cluster.NetworkLink{*newNetworkLink("edge-1-to-cloud", "edge-1", "cloud", 30000)}},
+		})
+
+		Expect(region.IsModified()).To(BeTrue())
+		Expect(region.Current().Edge("edge-1", "edge-2")).To(BeNil())
+		Expect(region.Current().NodeByLabel("router")).To(BeNil())
+		Expect(bandwidth(region.Current(), "cloud", "edge-1")).To(Equal(int64(30000)))
+		Expect(regionMgr.networkLinks).To(HaveLen(1))
+		Expect(original.Edge("edge-1", "edge-2")).ToNot(BeNil())
+	})
+
+	It("reconciles the nodes on a node resync", func() {
+		region := newCopyOnWriteRegionGraph(original)
+		Expect(regionMgr.applyNodeDelta(region, kubeutil.ListWatchDelta{
+			Type: kubeutil.ListWatchResync,
+			List: &core.NodeList{Items: []core.Node{*newNode("edge-1"), *newNode("edge-2"), *newNode("edge-3")}},
+		})).To(BeTrue())
+
+		// cloud is unconnected and was deleted, router is not a node of the cluster, but still referenced by a NetworkLink.
+		Expect(region.Current().NodeByLabel("cloud")).To(BeNil())
+		Expect(region.Current().NodeByLabel("edge-3")).ToNot(BeNil())
+		Expect(region.Current().NodeByLabel("router")).ToNot(BeNil())
+		Expect(original.NodeByLabel("cloud")).ToNot(BeNil())
+
+		regionMgr.updateOrphanedLinks()
+		Expect(regionMgr.OrphanedNetworkLinks()).To(HaveLen(1))
+	})
+
+})
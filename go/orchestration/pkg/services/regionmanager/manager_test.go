@@ -0,0 +1,121 @@
+package regionmanager_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("RegionManager", func() {
+
+	const timeout = 5 * time.Second
+
+	var regionMgr regionmanager.RegionManager
+	var cl client.WithWatch
+
+	newNode := func(name string) *core.Node {
+		return &core.Node{ObjectMeta: meta.ObjectMeta{Name: name}}
+	}
+
+	newNetworkLink := func(name string, nodeA string, nodeB string, bandwidthKbps int64) *cluster.NetworkLink {
+		return &cluster.NetworkLink{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: cluster.NetworkLinkSpec{
+				NodeA: nodeA,
+				NodeB: nodeB,
+				QoS: cluster.NetworkLinkQoS{
+					Throughput: cluster.NetworkThroughput{BandwidthKbps: bandwidthKbps},
+				},
+			},
+		}
+	}
+
+	hasNode := func(nodeName string) func() bool {
+		return func() bool {
+			return regionMgr.RegionGraph().NodeByLabel(nodeName) != nil
+		}
+	}
+
+	generation := func() int64 {
+		return regionMgr.PathCache().Generation()
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(cluster.AddToScheme(scheme)).To(Succeed())
+
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newNode("edge-1"),
+			newNode("edge-2"),
+			newNode("isolated"),
+			newNetworkLink("edge-1-to-edge-2", "edge-1", "edge-2", 100000),
+			newNetworkLink("edge-2-to-router", "edge-2", "router", 10000),
+		).Build()
+
+		var err error
+		regionMgr, err = regionmanager.NewRegionManagerWithClient(cl)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		regionMgr.Stop()
+	})
+
+	It("builds the initial RegionGraph from the nodes and NetworkLinks", func() {
+		region := regionMgr.RegionGraph()
+		Expect(region.IsDirected()).To(BeTrue())
+		for _, nodeName := range []string{"edge-1", "edge-2", "isolated", "router"} {
+			Expect(region.NodeByLabel(nodeName)).ToNot(BeNil(), nodeName)
+		}
+		Expect(region.Edge("edge-1", "edge-2").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(100000)))
+		Expect(region.Edge("router", "edge-2")).ToNot(BeNil())
+		Expect(regionMgr.OrphanedNetworkLinks()).To(Equal([]types.NamespacedName{{Namespace: "default", Name: "edge-2-to-router"}}))
+	})
+
+	It("adds new nodes as isolated nodes and ignores modifications of known nodes", func() {
+		initialGeneration := generation()
+
+		node := newNode("edge-1")
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(node), node)).To(Succeed())
+		node.Status.Conditions = []core.NodeCondition{{Type: core.NodeReady, Status: core.ConditionTrue}}
+		Expect(cl.Update(context.TODO(), node)).To(Succeed())
+		Consistently(generation, 300*time.Millisecond).Should(Equal(initialGeneration))
+
+		// The deltas are applied in order, so the modification has been processed once the new node is visible.
+		Expect(cl.Create(context.TODO(), newNode("cloud"))).To(Succeed())
+		Eventually(hasNode("cloud"), timeout).Should(BeTrue())
+		Expect(generation()).To(Equal(initialGeneration + 1))
+		Expect(regionMgr.RegionGraph().Graph().From(regionMgr.RegionGraph().NodeByLabel("cloud").ID()).Len()).To(Equal(0))
+	})
+
+	It("removes deleted nodes, unless they are referenced by a NetworkLink", func() {
+		Expect(cl.Delete(context.TODO(), newNode("isolated"))).To(Succeed())
+		Eventually(hasNode("isolated"), timeout).Should(BeFalse())
+
+		Expect(cl.Delete(context.TODO(), newNode("edge-1"))).To(Succeed())
+		Eventually(regionMgr.OrphanedNetworkLinks, timeout).Should(ConsistOf(
+			types.NamespacedName{Namespace: "default", Name: "edge-1-to-edge-2"},
+			types.NamespacedName{Namespace: "default", Name: "edge-2-to-router"},
+		))
+		Expect(hasNode("edge-1")()).To(BeTrue())
+	})
+
+	It("resolves orphaned NetworkLinks when the referenced node is added", func() {
+		Expect(cl.Create(context.TODO(), newNode("router"))).To(Succeed())
+		Eventually(regionMgr.OrphanedNetworkLinks, timeout).Should(BeEmpty())
+	})
+
+})
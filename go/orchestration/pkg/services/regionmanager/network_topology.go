@@ -0,0 +1,244 @@
+package regionmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"sigs.k8s.io/yaml"
+)
+
+// NetworkTopology is a static description of the network of a region in an adjacency list format,
+// which can be written by hand as YAML or JSON, e.g., for setting up a new edge site or an offline cluster.
+//
+// Example:
+//
+//	nodes:
+//	  - isolated-node
+//	links:
+//	  - nodeA: edge-1
+//	    nodeB: edge-2
+//	    qos:
+//	      qualityClass: QC100Mbps
+//	      throughput:
+//	        bandwidthKbps: 100000
+//	        bandwidthVariance: 0
+//	      latency:
+//	        packetDelayMsec: 5
+//	        packetDelayVariance: 1
+//	      packetLoss:
+//	        packetLossBp: 10
+//
+// A NetworkTopology can be converted into a RegionGraph or into the corresponding NetworkLink objects.
+// Use NewNetworkTopologyFromRegionGraph() to export a RegionGraph to this format.
+type NetworkTopology struct {
+	// The names of nodes that are not connected to any other node.
+	// Nodes that are referenced by a link do not need to be listed here.
+	Nodes []string `json:"nodes,omitempty"`
+
+	// The network links between the nodes.
+	Links []NetworkTopologyLink `json:"links,omitempty"`
+}
+
+// NetworkTopologyLink describes a single network link of a NetworkTopology.
+type NetworkTopologyLink struct {
+	// The name of the NetworkLink object that is created for this link.
+	// If omitted, the name is "<nodeA>-to-<nodeB>".
+	Name string `json:"name,omitempty"`
+
+	cluster.NetworkLinkSpec `json:",inline"`
+}
+
+// ParseNetworkTopology parses a NetworkTopology from its YAML or JSON representation and validates it.
+func ParseNetworkTopology(data []byte) (*NetworkTopology, error) {
+	topology := &NetworkTopology{}
+	if err := yaml.UnmarshalStrict(data, topology); err != nil {
+		return nil, fmt.Errorf("invalid network topology: %w", err)
+	}
+	if err := topology.Validate(); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+// NewNetworkTopologyFromRegionGraph exports the nodes and edges of the region to a NetworkTopology.
+//
+// The edges for the two directions between a pair of nodes are combined into a single link. If the edges of a directed RegionGraph
+// have different QoS values, the QoS of the direction from NodeB to NodeA is stored in QoSBtoA.
+// Since a link always describes both directions, an edge without a reverse edge is exported as a link with the same QoS for both directions.
+// The links are sorted by the names of their nodes and named using the default naming scheme.
+func NewNetworkTopologyFromRegionGraph(region regiongraph.RegionGraph) *NetworkTopology {
+	topology := &NetworkTopology{
+		Nodes: make([]string, 0),
+		Links: make([]NetworkTopologyLink, 0),
+	}
+
+	for _, nodeA := range getSortedNodeNames(region) {
+		neighbors := getSortedNeighborNames(region, nodeA)
+		if len(neighbors) == 0 {
+			topology.Nodes = append(topology.Nodes, nodeA)
+			continue
+		}
+
+		for _, nodeB := range neighbors {
+			edgeAtoB := region.Edge(nodeA, nodeB)
+			edgeBtoA := region.Edge(nodeB, nodeA)
+			if nodeA > nodeB && edgeBtoA != nil {
+				// This pair of nodes has already been exported when visiting nodeB.
+				continue
+			}
+			topology.Links = append(topology.Links, newNetworkTopologyLink(nodeA, nodeB, edgeAtoB, edgeBtoA, region.IsDirected()))
+		}
+	}
+
+	return topology
+}
+
+// Validate checks if all links reference two different nodes and if the names of the links and the pairs of nodes are unique.
+func (me *NetworkTopology) Validate() error {
+	for i, node := range me.Nodes {
+		if node == "" {
+			return fmt.Errorf("invalid network topology: nodes[%d] must not be empty", i)
+		}
+	}
+
+	linkNames := make(map[string]bool, len(me.Links))
+	nodePairs := make(map[[2]string]bool, len(me.Links))
+	for i := range me.Links {
+		link := &me.Links[i]
+		if link.NodeA == "" || link.NodeB == "" {
+			return fmt.Errorf("invalid network topology: links[%d] must specify nodeA and nodeB", i)
+		}
+		if link.NodeA == link.NodeB {
+			return fmt.Errorf("invalid network topology: links[%d] must connect two different nodes, but connects %s with itself", i, link.NodeA)
+		}
+
+		name := link.GetName()
+		if linkNames[name] {
+			return fmt.Errorf("invalid network topology: the link name %s is used more than once", name)
+		}
+		linkNames[name] = true
+
+		nodePair := [2]string{link.NodeA, link.NodeB}
+		if link.NodeA > link.NodeB {
+			nodePair = [2]string{link.NodeB, link.NodeA}
+		}
+		if nodePairs[nodePair] {
+			return fmt.Errorf("invalid network topology: there is more than one link between %s and %s", link.NodeA, link.NodeB)
+		}
+		nodePairs[nodePair] = true
+	}
+
+	return nil
+}
+
+// ToNetworkLinks creates a NetworkLink object in the specified namespace for each link of the topology.
+func (me *NetworkTopology) ToNetworkLinks(namespace string) []cluster.NetworkLink {
+	networkLinks := make([]cluster.NetworkLink, len(me.Links))
+	for i := range me.Links {
+		link := &me.Links[i]
+		networkLinks[i] = cluster.NetworkLink{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: cluster.GroupVersion.String(),
+				Kind:       "NetworkLink",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      link.GetName(),
+				Namespace: namespace,
+			},
+			Spec: *link.NetworkLinkSpec.DeepCopy(),
+		}
+	}
+	return networkLinks
+}
+
+// ToRegionGraph creates a directed RegionGraph that contains all nodes of the topology and
+// the edges for both directions of each link.
+func (me *NetworkTopology) ToRegionGraph() regiongraph.RegionGraph {
+	region := regiongraph.NewDirectedRegionGraph()
+	for _, node := range me.Nodes {
+		getOrCreateNode(region, node)
+	}
+
+	networkLinks := me.ToNetworkLinks("")
+	now := time.Now()
+	for i := range networkLinks {
+		addNetworkLinkEdges(region, &networkLinks[i], now)
+	}
+	return region
+}
+
+// ToYAML returns the YAML representation of the topology.
+func (me *NetworkTopology) ToYAML() ([]byte, error) {
+	return yaml.Marshal(me)
+}
+
+// ToJSON returns the indented JSON representation of the topology.
+func (me *NetworkTopology) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(me, "", "  ")
+}
+
+// GetName returns the name of the link or the default name "<nodeA>-to-<nodeB>", if no name is set.
+func (me *NetworkTopologyLink) GetName() string {
+	if me.Name != "" {
+		return me.Name
+	}
+	return fmt.Sprintf("%s-to-%s", me.NodeA, me.NodeB)
+}
+
+// NetworkLinksToYAML returns a multi-document YAML representation of the networkLinks, which can be applied using kubectl.
+func NetworkLinksToYAML(networkLinks []cluster.NetworkLink) ([]byte, error) {
+	var buf bytes.Buffer
+	for i := range networkLinks {
+		linkYaml, err := yaml.Marshal(&networkLinks[i])
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(linkYaml)
+	}
+	return buf.Bytes(), nil
+}
+
+func newNetworkTopologyLink(nodeA, nodeB string, edgeAtoB, edgeBtoA regiongraph.Edge, directed bool) NetworkTopologyLink {
+	link := NetworkTopologyLink{
+		NetworkLinkSpec: cluster.NetworkLinkSpec{
+			NodeA: nodeA,
+			NodeB: nodeB,
+			Trust: edgeAtoB.NetworkLinkTrust().DeepCopy(),
+		},
+	}
+	if qos := edgeAtoB.NetworkLinkQoS(); qos != nil {
+		link.QoS = *qos.DeepCopy()
+	}
+	if directed && edgeBtoA != nil && edgeBtoA.NetworkLinkQoS() != nil && !equality.Semantic.DeepEqual(&link.QoS, edgeBtoA.NetworkLinkQoS()) {
+		link.QoSBtoA = edgeBtoA.NetworkLinkQoS().DeepCopy()
+	}
+	return link
+}
+
+func getSortedNodeNames(region regiongraph.RegionGraph) []string {
+	nodeNames := getNodeNames(region)
+	sort.Strings(nodeNames)
+	return nodeNames
+}
+
+// Returns the sorted names of the nodes that can be reached from the specified node using a single edge.
+func getSortedNeighborNames(region regiongraph.RegionGraph, nodeName string) []string {
+	node := region.NodeByLabel(nodeName)
+	neighbors := region.Graph().From(node.ID())
+	neighborNames := make([]string, 0, neighbors.Len())
+	for neighbors.Next() {
+		neighborNames = append(neighborNames, neighbors.Node().(regiongraph.Node).Label())
+	}
+	sort.Strings(neighborNames)
+	return neighborNames
+}
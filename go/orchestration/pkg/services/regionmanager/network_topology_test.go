@@ -0,0 +1,122 @@
+package regionmanager_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+)
+
+var _ = Describe("NetworkTopology", func() {
+
+	const topologyYaml = `
+nodes:
+  - isolated
+links:
+  - nodeA: edge-1
+    nodeB: edge-2
+    qos:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+        bandwidthVariance: 0
+      latency:
+        packetDelayMsec: 5
+        packetDelayVariance: 1
+      packetLoss:
+        packetLossBp: 10
+  - name: satellite-uplink
+    nodeA: edge-2
+    nodeB: cloud
+    qos:
+      qualityClass: QC10Mbps
+      throughput:
+        bandwidthKbps: 10000
+        bandwidthVariance: 0
+      latency:
+        packetDelayMsec: 300
+        packetDelayVariance: 20
+      packetLoss:
+        packetLossBp: 50
+    qosBtoA:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+        bandwidthVariance: 0
+      latency:
+        packetDelayMsec: 300
+        packetDelayVariance: 20
+      packetLoss:
+        packetLossBp: 50
+    trust:
+      encrypted: true
+      exposure: public
+      attested: false
+`
+
+	It("parses a topology and converts it into NetworkLinks", func() {
+		topology, err := regionmanager.ParseNetworkTopology([]byte(topologyYaml))
+		Expect(err).ToNot(HaveOccurred())
+
+		networkLinks := topology.ToNetworkLinks("edge-site")
+		Expect(networkLinks).To(HaveLen(2))
+		Expect(networkLinks[0].Name).To(Equal("edge-1-to-edge-2"))
+		Expect(networkLinks[0].Namespace).To(Equal("edge-site"))
+		Expect(networkLinks[0].Kind).To(Equal("NetworkLink"))
+		Expect(networkLinks[0].Spec.QoS.Latency.PacketDelayMsec).To(Equal(int32(5)))
+		Expect(networkLinks[1].Name).To(Equal("satellite-uplink"))
+		Expect(networkLinks[1].Spec.QoSBtoA.Throughput.BandwidthKbps).To(Equal(int64(100000)))
+		Expect(networkLinks[1].Spec.Trust.Encrypted).To(BeTrue())
+
+		linksYaml, err := regionmanager.NetworkLinksToYAML(networkLinks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(linksYaml)).To(ContainSubstring("---\n"))
+		Expect(string(linksYaml)).To(ContainSubstring("name: satellite-uplink"))
+	})
+
+	It("converts a topology into a directed RegionGraph", func() {
+		topology, err := regionmanager.ParseNetworkTopology([]byte(topologyYaml))
+		Expect(err).ToNot(HaveOccurred())
+
+		region := topology.ToRegionGraph()
+		Expect(region.IsDirected()).To(BeTrue())
+		Expect(region.NodeByLabel("isolated")).ToNot(BeNil())
+		Expect(region.Edge("edge-1", "edge-2").NetworkLinkQoS().Latency.PacketDelayMsec).To(Equal(int32(5)))
+		Expect(region.Edge("edge-2", "edge-1").NetworkLinkQoS().Latency.PacketDelayMsec).To(Equal(int32(5)))
+		Expect(region.Edge("edge-2", "cloud").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(10000)))
+		Expect(region.Edge("cloud", "edge-2").NetworkLinkQoS().Throughput.BandwidthKbps).To(Equal(int64(100000)))
+		Expect(region.Edge("edge-1", "cloud")).To(BeNil())
+	})
+
+	It("serializes a topology to YAML and JSON", func() {
+		topology, err := regionmanager.ParseNetworkTopology([]byte(topologyYaml))
+		Expect(err).ToNot(HaveOccurred())
+
+		exportedYaml, err := topology.ToYAML()
+		Expect(err).ToNot(HaveOccurred())
+		reimported, err := regionmanager.ParseNetworkTopology(exportedYaml)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reimported).To(Equal(topology))
+
+		exportedJson, err := topology.ToJSON()
+		Expect(err).ToNot(HaveOccurred())
+		reimported, err = regionmanager.ParseNetworkTopology(exportedJson)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reimported).To(Equal(topology))
+	})
+
+	It("rejects invalid topologies", func() {
+		invalidTopologies := map[string]string{
+			"unknown field":       "links:\n  - nodeA: a\n    nodeB: b\n    bandwidth: 10\n",
+			"missing node":        "links:\n  - nodeA: a\n",
+			"self loop":           "links:\n  - nodeA: a\n    nodeB: a\n",
+			"duplicate node pair": "links:\n  - nodeA: a\n    nodeB: b\n  - nodeA: b\n    nodeB: a\n",
+			"duplicate name":      "links:\n  - name: x\n    nodeA: a\n    nodeB: b\n  - name: x\n    nodeA: b\n    nodeB: c\n",
+		}
+		for name, topologyYaml := range invalidTopologies {
+			_, err := regionmanager.ParseNetworkTopology([]byte(topologyYaml))
+			Expect(err).To(HaveOccurred(), name)
+		}
+	})
+
+})
@@ -0,0 +1,42 @@
+package regionmanager
+
+import (
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+// NetworkLinkFilter decides which network links may be traversed by the paths obtained from a RegionPathCache.
+type NetworkLinkFilter interface {
+	// Key identifies the filter criteria within the cache.
+	// Two filters with the same key must accept the same network links.
+	Key() string
+
+	// Accepts returns true if the specified network link may be traversed.
+	Accepts(link regiongraph.Edge) bool
+}
+
+// RegionPathCache provides the shortest paths and their QoS for a single generation of a RegionGraph.
+//
+// The shortest paths from a source node are computed lazily, when they are requested for the first time,
+// and then cached until the RegionGraph changes. When the RegionGraph changes, the RegionManager creates
+// a new RegionPathCache with a new generation.
+//
+// All methods are thread-safe.
+type RegionPathCache interface {
+	// Generation returns the generation of the RegionGraph that this cache was created for.
+	// The generation is incremented with every change to the RegionGraph.
+	Generation() int64
+
+	// RegionGraph returns the RegionGraph that the paths of this cache are computed on.
+	// This must be treated as immutable.
+	RegionGraph() regiongraph.RegionGraph
+
+	// ShortestPath returns the QoS of the shortest path (w.r.t. packet delay) from the src node to the dest node
+	// that only traverses network links accepted by the linkFilter.
+	// If no such path exists, nil is returned.
+	ShortestPath(src, dest regiongraph.Node, linkFilter NetworkLinkFilter) *regiongraph.PathQoS
+}
+
+// NewRegionPathCache creates a new RegionPathCache for the specified RegionGraph.
+func NewRegionPathCache(region regiongraph.RegionGraph, generation int64) RegionPathCache {
+	return newRegionPathCacheImpl(region, generation)
+}
@@ -0,0 +1,94 @@
+package regionmanager
+
+import (
+	"sync"
+
+	graphpath "gonum.org/v1/gonum/graph/path"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/util"
+)
+
+var (
+	_regionPathCacheImpl *regionPathCacheImpl
+
+	_ RegionPathCache = _regionPathCacheImpl
+)
+
+// Stores the shortest paths from a single source node.
+type shortestPathsFromNode struct {
+	// The shortest paths tree, rooted at the source node.
+	paths graphpath.Shortest
+
+	// Maps the IDs of destination nodes to their cachedPathQoS.
+	pathQos sync.Map
+}
+
+// Wraps a PathQoS to allow caching the absence of a path.
+type cachedPathQoS struct {
+	pathQos *regiongraph.PathQoS
+}
+
+// Default implementation of the RegionPathCache.
+type regionPathCacheImpl struct {
+	generation int64
+	region     regiongraph.RegionGraph
+
+	// Maps NetworkLinkFilter keys to *sync.Map objects, which map the IDs of source nodes to
+	// Futures, whose results are *shortestPathsFromNode objects.
+	shortestPathsByFilter sync.Map
+}
+
+func newRegionPathCacheImpl(region regiongraph.RegionGraph, generation int64) *regionPathCacheImpl {
+	return &regionPathCacheImpl{
+		generation: generation,
+		region:     region,
+	}
+}
+
+func (me *regionPathCacheImpl) Generation() int64 {
+	return me.generation
+}
+
+func (me *regionPathCacheImpl) RegionGraph() regiongraph.RegionGraph {
+	return me.region
+}
+
+func (me *regionPathCacheImpl) ShortestPath(src, dest regiongraph.Node, linkFilter NetworkLinkFilter) *regiongraph.PathQoS {
+	shortestPaths := me.getOrComputeShortestPaths(src, linkFilter)
+	destId := dest.ID()
+
+	if cached, ok := shortestPaths.pathQos.Load(destId); ok {
+		return cached.(*cachedPathQoS).pathQos
+	}
+
+	var pathQos *regiongraph.PathQoS
+	if path, _ := shortestPaths.paths.To(destId); len(path) > 0 {
+		pathQos = regiongraph.NewPathQoS(path, me.region)
+	}
+	actual, _ := shortestPaths.pathQos.LoadOrStore(destId, &cachedPathQoS{pathQos: pathQos})
+	return actual.(*cachedPathQoS).pathQos
+}
+
+// Gets the cached shortest paths from the src node or computes them, if they have not been computed yet.
+// We use Futures to ensure that the shortest paths are computed only once, even if multiple goroutines request them concurrently.
+func (me *regionPathCacheImpl) getOrComputeShortestPaths(src regiongraph.Node, linkFilter NetworkLinkFilter) *shortestPathsFromNode {
+	filterCacheObj, _ := me.shortestPathsByFilter.LoadOrStore(linkFilter.Key(), &sync.Map{})
+	filterCache := filterCacheObj.(*sync.Map)
+
+	var handle util.Future
+	if existingHandle, ok := filterCache.Load(src.ID()); ok {
+		handle = existingHandle.(util.Future)
+	} else {
+		newHandle, resultProvider := util.NewFuture()
+		if actualHandle, loaded := filterCache.LoadOrStore(src.ID(), newHandle); loaded {
+			handle = actualHandle.(util.Future)
+		} else {
+			handle = newHandle
+			compliantRegion := regiongraph.NewFilteredGraphView(me.region, linkFilter.Accepts)
+			resultProvider(&shortestPathsFromNode{paths: graphpath.DijkstraFrom(src, compliantRegion)}, nil)
+		}
+	}
+
+	shortestPaths, _ := handle.Get()
+	return shortestPaths.(*shortestPathsFromNode)
+}
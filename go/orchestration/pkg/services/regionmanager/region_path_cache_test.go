@@ -0,0 +1,108 @@
+package regionmanager_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+)
+
+// Accepts only network links with at least the minimum bandwidth.
+type minBandwidthFilter struct {
+	minBandwidthKbps int64
+}
+
+func (me *minBandwidthFilter) Key() string {
+	return fmt.Sprintf("minBandwidth=%d", me.minBandwidthKbps)
+}
+
+func (me *minBandwidthFilter) Accepts(link regiongraph.Edge) bool {
+	return link.NetworkLinkQoS().Throughput.BandwidthKbps >= me.minBandwidthKbps
+}
+
+var _ = Describe("RegionPathCache", func() {
+
+	// edge-1 and edge-2 are connected by a fast symmetric link, edge-2 and cloud by a cellular link with
+	// a slow uplink (edge-2 -> cloud) and a fast downlink (cloud -> edge-2), and edge-1 and cloud by a slow symmetric link.
+	const topologyYaml = `
+links:
+  - nodeA: edge-1
+    nodeB: edge-2
+    qos:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+      latency:
+        packetDelayMsec: 5
+  - nodeA: edge-2
+    nodeB: cloud
+    qos:
+      qualityClass: QC1Mbps
+      throughput:
+        bandwidthKbps: 2000
+      latency:
+        packetDelayMsec: 20
+    qosBtoA:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+      latency:
+        packetDelayMsec: 20
+  - nodeA: edge-1
+    nodeB: cloud
+    qos:
+      qualityClass: QC10Mbps
+      throughput:
+        bandwidthKbps: 10000
+      latency:
+        packetDelayMsec: 100
+`
+
+	var pathCache regionmanager.RegionPathCache
+	var region regiongraph.RegionGraph
+
+	pathLabels := func(pathQos *regiongraph.PathQoS) []string {
+		labels := make([]string, len(pathQos.Path))
+		for i, node := range pathQos.Path {
+			labels[i] = node.(regiongraph.Node).Label()
+		}
+		return labels
+	}
+
+	BeforeEach(func() {
+		topology, err := regionmanager.ParseNetworkTopology([]byte(topologyYaml))
+		Expect(err).ToNot(HaveOccurred())
+		region = topology.ToRegionGraph()
+		pathCache = regionmanager.NewRegionPathCache(region, 1)
+	})
+
+	It("finds the shortest paths in the direction from the source to the destination", func() {
+		filter := &minBandwidthFilter{minBandwidthKbps: 50000}
+
+		downPath := pathCache.ShortestPath(region.NodeByLabel("cloud"), region.NodeByLabel("edge-1"), filter)
+		Expect(downPath).ToNot(BeNil())
+		Expect(pathLabels(downPath)).To(Equal([]string{"cloud", "edge-2", "edge-1"}))
+		Expect(downPath.TotalPacketDelayMsec).To(Equal(int64(25)))
+		Expect(downPath.LowestBandwidthKbps).To(Equal(int64(100000)))
+
+		// The uplink of the cellular link and the direct link are too slow.
+		Expect(pathCache.ShortestPath(region.NodeByLabel("edge-1"), region.NodeByLabel("cloud"), filter)).To(BeNil())
+	})
+
+	It("uses the QoS of each direction for the paths", func() {
+		filter := &minBandwidthFilter{}
+
+		upPath := pathCache.ShortestPath(region.NodeByLabel("edge-1"), region.NodeByLabel("cloud"), filter)
+		Expect(pathLabels(upPath)).To(Equal([]string{"edge-1", "edge-2", "cloud"}))
+		Expect(upPath.LowestBandwidthKbps).To(Equal(int64(2000)))
+
+		lowBandwidthFilter := &minBandwidthFilter{minBandwidthKbps: 5000}
+		upPath = pathCache.ShortestPath(region.NodeByLabel("edge-1"), region.NodeByLabel("cloud"), lowBandwidthFilter)
+		Expect(pathLabels(upPath)).To(Equal([]string{"edge-1", "cloud"}))
+		Expect(upPath.TotalPacketDelayMsec).To(Equal(int64(100)))
+	})
+
+})
@@ -0,0 +1,13 @@
+package regionmanager_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRegionManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RegionManager Suite")
+}
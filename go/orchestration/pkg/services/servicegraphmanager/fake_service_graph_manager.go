@@ -0,0 +1,101 @@
+package servicegraphmanager
+
+import (
+	"sync"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
+)
+
+var (
+	_fakeServiceGraphManager *FakeServiceGraphManager
+
+	_ ServiceGraphManager = _fakeServiceGraphManager
+)
+
+// FakeServiceGraphManager is an in-memory ServiceGraphManager for unit tests.
+//
+// It serves the ServiceGraphs that have been added using AddServiceGraph().
+// Unlike the real ServiceGraphManager, the ServiceGraphStates are never removed when the pods are bound,
+// so all pods of a ServiceGraph share the same state, including its placement map.
+// Since no pods are observed, reservations made through ReservePod() remain assumed until UnreservePod() is called.
+type FakeServiceGraphManager struct {
+	// Maps a service graph identifier (<namespace>.<name>) to a serviceGraphStateImpl.
+	states map[string]*serviceGraphStateImpl
+	mutex  sync.Mutex
+}
+
+// NewFakeServiceGraphManager creates a new FakeServiceGraphManager without any ServiceGraphs.
+func NewFakeServiceGraphManager() *FakeServiceGraphManager {
+	return &FakeServiceGraphManager{
+		states: make(map[string]*serviceGraphStateImpl),
+	}
+}
+
+// AddServiceGraph adds the specified ServiceGraph CRD instance to the FakeServiceGraphManager and returns its ServiceGraphState.
+// The placement map of the ServiceGraphState is computed from the placedPods, which are counted as bound pods and
+// must have their ServiceGraphNode label and their node name set.
+// If the ServiceGraph has already been added, its state is replaced.
+func (me *FakeServiceGraphManager) AddServiceGraph(crd *fogappsCRDs.ServiceGraph, placedPods ...core.Pod) ServiceGraphState {
+	crd = crd.DeepCopy()
+	indexKey := getServiceGraphMapKey(crd.Namespace, crd.Name)
+
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{podsByServiceGraphIndex: indexPodByServiceGraph})
+	for i := range placedPods {
+		pod := placedPods[i].DeepCopy()
+		pod.Namespace = crd.Namespace
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		pod.Labels[kubeutil.LabelRefServiceGraph] = crd.Name
+		podIndexer.Add(pod)
+	}
+	state := newServiceGraphStateImpl(servicegraph.FromCRDInstance(crd), crd, podIndexer, indexKey)
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	me.states[indexKey] = state
+	return state
+}
+
+func (me *FakeServiceGraphManager) GetServiceGraphState(pod *core.Pod) (ServiceGraphState, error) {
+	svcGraphName, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph)
+	if !ok {
+		return nil, nil
+	}
+	namespace := kubeutil.GetNamespace(pod)
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	if state, ok := me.states[getServiceGraphMapKey(namespace, svcGraphName)]; ok {
+		return state, nil
+	}
+	return nil, errors.NewNotFound(fogappsCRDs.GroupVersion.WithResource("servicegraphs").GroupResource(), svcGraphName)
+}
+
+// LookupServiceGraphState is the same as GetServiceGraphState(), because the FakeServiceGraphManager does not track any pods.
+func (me *FakeServiceGraphManager) LookupServiceGraphState(pod *core.Pod) (ServiceGraphState, error) {
+	return me.GetServiceGraphState(pod)
+}
+
+// GetServiceGraphCRD returns the CRD instance of a ServiceGraph that has been added using AddServiceGraph() or nil.
+func (me *FakeServiceGraphManager) GetServiceGraphCRD(namespace, name string) *fogappsCRDs.ServiceGraph {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	if state, ok := me.states[getServiceGraphMapKey(namespace, name)]; ok {
+		return state.ServiceGraphCRD()
+	}
+	return nil
+}
+
+// WatchPods has no effect, because the FakeServiceGraphManager does not observe any pods.
+func (me *FakeServiceGraphManager) WatchPods(podInformer cache.SharedIndexInformer) error {
+	return nil
+}
+
+// Stop has no effect, because the FakeServiceGraphManager does not watch any ServiceGraphs.
+func (me *FakeServiceGraphManager) Stop() {}
@@ -0,0 +1,117 @@
+package servicegraphmanager
+
+import (
+	"sync"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
+)
+
+// Caches the ServiceGraph CRD instances that have been observed by the ServiceGraphManager's watch
+// and memoizes the graphs built from them.
+//
+// The entries are immutable and are replaced whenever a ServiceGraph changes.
+// The graph of a ServiceGraph is built at most once per generation of the CRD instance,
+// i.e., changes that do not affect the spec (e.g., status updates) reuse the existing graph.
+type serviceGraphCache struct {
+	// Maps a service graph identifier (<namespace>.<name>) to a serviceGraphCacheEntry.
+	entries map[string]*serviceGraphCacheEntry
+	mutex   sync.RWMutex
+}
+
+// An immutable entry of the serviceGraphCache.
+type serviceGraphCacheEntry struct {
+	// The latest observed version of the ServiceGraph CRD instance.
+	crd *fogappsCRDs.ServiceGraph
+
+	// The graph built from the current generation of the CRD instance.
+	graph *memoizedServiceGraph
+}
+
+// Builds the graph of a ServiceGraph CRD instance when it is first accessed.
+type memoizedServiceGraph struct {
+	crd   *fogappsCRDs.ServiceGraph
+	graph servicegraph.ServiceGraph
+	once  sync.Once
+}
+
+func newServiceGraphCache() *serviceGraphCache {
+	return &serviceGraphCache{
+		entries: make(map[string]*serviceGraphCacheEntry),
+	}
+}
+
+func newServiceGraphCacheEntry(crd *fogappsCRDs.ServiceGraph) *serviceGraphCacheEntry {
+	return &serviceGraphCacheEntry{
+		crd:   crd,
+		graph: &memoizedServiceGraph{crd: crd},
+	}
+}
+
+// Gets the entry for the specified ServiceGraph or nil, if the ServiceGraph is not in the cache.
+func (me *serviceGraphCache) get(mapKey string) *serviceGraphCacheEntry {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	return me.entries[mapKey]
+}
+
+// Adds or updates the specified ServiceGraph.
+// Returns true if the ServiceGraph was already in the cache with a different generation.
+func (me *serviceGraphCache) set(crd *fogappsCRDs.ServiceGraph) bool {
+	mapKey := getServiceGraphMapKey(crd.Namespace, crd.Name)
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	return me.setInternal(mapKey, crd)
+}
+
+// Removes the specified ServiceGraph from the cache.
+func (me *serviceGraphCache) remove(mapKey string) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	delete(me.entries, mapKey)
+}
+
+// Replaces the contents of the cache with the specified list of ServiceGraphs.
+// Returns the keys of the ServiceGraphs, whose generation has changed, and the keys of the ServiceGraphs that have been removed.
+func (me *serviceGraphCache) replaceAll(crds *fogappsCRDs.ServiceGraphList) (changed []string, removed []string) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	prevEntries := me.entries
+	me.entries = make(map[string]*serviceGraphCacheEntry, len(crds.Items))
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		mapKey := getServiceGraphMapKey(crd.Namespace, crd.Name)
+		if prevEntry, ok := prevEntries[mapKey]; ok {
+			me.entries[mapKey] = prevEntry
+			delete(prevEntries, mapKey)
+		}
+		if me.setInternal(mapKey, crd) {
+			changed = append(changed, mapKey)
+		}
+	}
+
+	for mapKey := range prevEntries {
+		removed = append(removed, mapKey)
+	}
+	return changed, removed
+}
+
+// Adds or updates the specified ServiceGraph. This must be called while holding the mutex.
+func (me *serviceGraphCache) setInternal(mapKey string, crd *fogappsCRDs.ServiceGraph) bool {
+	prevEntry, ok := me.entries[mapKey]
+	if ok && prevEntry.crd.Generation == crd.Generation {
+		me.entries[mapKey] = &serviceGraphCacheEntry{crd: crd, graph: prevEntry.graph}
+		return false
+	}
+	me.entries[mapKey] = newServiceGraphCacheEntry(crd)
+	return ok
+}
+
+// Gets the graph, which is built on the first call.
+func (me *memoizedServiceGraph) get() servicegraph.ServiceGraph {
+	me.once.Do(func() {
+		me.graph = servicegraph.FromCRDInstance(me.crd)
+	})
+	return me.graph
+}
@@ -2,43 +2,77 @@ package servicegraphmanager
 
 import (
 	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var (
-	instance ServiceGraphManager
-)
-
-// We need an AcquireServiceGraphState method that
-// 1. Loads the ServiceGraph, if it has not been loaded yet and starts fetching the placement map (creates a ServiceGraphState object).
-// 2. Increments the reference count on the service graph state object.
-
 // ServiceGraphManager provides methods for obtaining the service graph for a particular application
+//
+// The ServiceGraphState of an application is kept alive as long as at least one of its pods has not been observed as bound
+// by the pod informer, i.e., while a pod is pending, waiting in the Permit phase, or bound, but the binding has not yet been
+// observed. This ensures that all pods of an initial placement see the reservations recorded by the previously scheduled pods.
+// Once all pods of the ServiceGraph have been observed as bound or deleted, the ServiceGraphState is removed from the cache
+// and the next pod of the application causes it to be loaded again.
+//
+// The ServiceGraphManager learns about pod changes through a shared pod informer, which must be passed to WatchPods().
+// The informer's cache is also used to compute the initial placement maps of the ServiceGraphs, which are then updated incrementally
+// from the pod events, so no pods need to be listed from the API server.
+// The ServiceGraph CRD instances are watched as well. When the generation of a ServiceGraph changes, its ServiceGraphState
+// is refreshed and when a ServiceGraph is deleted, its ServiceGraphState is removed.
 type ServiceGraphManager interface {
 	// Gets the ServiceGraphState for the application that the specified pod is part of.
 	// If the pod has no ServiceGraph associated, the ServiceGraphState AND the error will be nil.
 	// Note that this method only checks the pod's reference to the ServiceGraph, but not the reference
 	// to a node within that ServiceGraph.
 	//
-	// The requesting pod is added to reference count of the ServiceGraphState. When the pod's scheduling cycle ends,
-	// the state's Release() method must be called to allow it to be removed from the cache. Each pod may call
-	// AcquireServiceGraphState() multiple times, it is only counted once towards the reference count.
+	// If the pod has not been bound to a node yet, it keeps the ServiceGraphState alive until the pod informer
+	// observes that it has been bound or deleted.
 	//
 	// If the ServiceGraph has already been loaded for another pod that is currently in the pipeline, it is returned immediately.
-	// If not, the ServiceGraph CRD is fetched (blocking the caller until this has completed) and then the building of the
-	// ServiceGraphPlacementMap is started asynchronously.
-	AcquireServiceGraphState(pod *core.Pod) (ServiceGraphState, error)
+	// If not, the ServiceGraph CRD and its graph are obtained from the ServiceGraphManager's cache and the ServiceGraphPlacementMap
+	// is computed from the pod informer's cache. Only if the ServiceGraph has not been observed by the watch yet,
+	// it is fetched from the API server (blocking the caller until this has completed).
+	GetServiceGraphState(pod *core.Pod) (ServiceGraphState, error)
+
+	// Gets the ServiceGraphState for the application that the specified pod is part of, like GetServiceGraphState(),
+	// but without keeping the ServiceGraphState alive for the pod.
+	//
+	// This must be used by callers that do not schedule the pod, but only inspect it, e.g., when sorting the scheduling queue
+	// or when undoing a reservation. The pod objects passed by such callers may be outdated, e.g., a pod may already have been
+	// bound or deleted, so tracking them as unbound pods could keep the ServiceGraphState alive indefinitely.
+	LookupServiceGraphState(pod *core.Pod) (ServiceGraphState, error)
+
+	// Gets the ServiceGraph CRD instance with the specified namespace and name from the cache of observed ServiceGraphs.
+	// If the ServiceGraph has not been observed by the watch, nil is returned.
+	//
+	// Unlike GetServiceGraphState(), this does not create a ServiceGraphState, so it is suitable for inspecting the
+	// ServiceGraphs of pods that are already running, e.g., when selecting preemption victims.
+	// The returned object must be treated as immutable.
+	GetServiceGraphCRD(namespace, name string) *fogappsCRDs.ServiceGraph
+
+	// WatchPods configures the ServiceGraphManager to use the specified shared pod informer for tracking the lifecycle
+	// of the pods and for computing the placement maps.
+	// This adds an index to the informer, so it must be called before the informer is started.
+	// Calling WatchPods() multiple times with the same informer has no effect, calling it with a different informer returns an error.
+	WatchPods(podInformer cache.SharedIndexInformer) error
+
+	// Stops watching the ServiceGraph CRD instances.
+	Stop()
 }
 
-// GetServiceGraphManager returns the singleton instance of the ServiceGraphManager.
-func GetServiceGraphManager() ServiceGraphManager {
-	if instance != nil {
-		return instance
+// NewServiceGraphManager creates a new ServiceGraphManager, which loads ServiceGraphs and pods from the cluster configured by the ConfigManager.
+func NewServiceGraphManager(configMgr configmanager.ConfigManager) (ServiceGraphManager, error) {
+	cl, err := client.NewWithWatch(configMgr.RestConfig(), client.Options{Scheme: configMgr.Scheme()})
+	if err != nil {
+		return nil, err
 	}
-	panic("ServiceGraphManager singleton has not been initialized. Did you call InitServiceGraphManager()?")
+	return NewServiceGraphManagerWithClient(cl)
 }
 
-// Initializes the singleton instance of the ServiceGraphManager.
-func InitServiceGraphManager() ServiceGraphManager {
-	instance = newServiceGraphManagerImpl()
-	return instance
+// NewServiceGraphManagerWithClient creates a new ServiceGraphManager, which uses the specified client for watching ServiceGraphs.
+// This allows running the ServiceGraphManager against an in-memory client, e.g., in a simulation.
+func NewServiceGraphManagerWithClient(cl client.WithWatch) (ServiceGraphManager, error) {
+	return newServiceGraphManagerImpl(cl)
 }
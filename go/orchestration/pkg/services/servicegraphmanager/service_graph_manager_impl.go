@@ -7,11 +7,10 @@ import (
 
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -20,6 +19,11 @@ var (
 	_ ServiceGraphManager = (*serviceGraphManagerImpl)(nil)
 )
 
+const (
+	// The name of the pod informer index, which indexes the pods by their ServiceGraph (<namespace>.<name>).
+	podsByServiceGraphIndex = "rainbow-h2020.eu/service-graph"
+)
+
 type serviceGraphID struct {
 	types.NamespacedName
 
@@ -32,44 +36,259 @@ type serviceGraphManagerImpl struct {
 	// result is a serviceGraphStateImpl.
 	activeStates sync.Map
 
-	// The K8s client used to load ServiceGraph CRD instances.
+	// Maps a service graph identifier (<namespace>.<name>) to the names of its pods that have not been observed as bound yet.
+	// A ServiceGraphState is removed from activeStates when the last pod of its set of unbound pods has been observed as bound or deleted.
+	unboundPods map[string]map[string]bool
+
+	// Synchronizes access to the unboundPods map and the removal of states from activeStates.
+	unboundPodsMutex sync.Mutex
+
+	// The pod informer, whose cache is used for computing the placement maps.
+	podInformer cache.SharedIndexInformer
+
+	// Synchronizes access to podInformer.
+	podInformerMutex sync.RWMutex
+
+	// The ServiceGraph CRD instances observed by the svcGraphsWatcher.
+	svcGraphs *serviceGraphCache
+
+	// Watches the ServiceGraph CRD instances.
+	svcGraphsWatcher kubeutil.ListWatcher
+
+	// The K8s client used to load ServiceGraph CRD instances that have not been observed by the svcGraphsWatcher yet.
 	client client.Client
 }
 
-func newServiceGraphManagerImpl() *serviceGraphManagerImpl {
-	configMgr := configmanager.GetConfigManager()
-	k8sClient, err := client.New(configMgr.RestConfig(), client.Options{Scheme: configMgr.Scheme()})
+func newServiceGraphManagerImpl(k8sClient client.WithWatch) (*serviceGraphManagerImpl, error) {
+	svcGraphsWatcher, err := kubeutil.StartListWatcherWithClient(&fogappsCRDs.ServiceGraphList{}, k8sClient)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	svcGraphMgr := &serviceGraphManagerImpl{
+		activeStates:     sync.Map{},
+		unboundPods:      make(map[string]map[string]bool),
+		svcGraphs:        newServiceGraphCache(),
+		svcGraphsWatcher: svcGraphsWatcher,
+		client:           k8sClient,
+	}
+	svcGraphMgr.svcGraphs.replaceAll(svcGraphsWatcher.InitialList().(*fogappsCRDs.ServiceGraphList))
+
+	go svcGraphMgr.watchServiceGraphs()
+	return svcGraphMgr, nil
+}
+
+func (me *serviceGraphManagerImpl) GetServiceGraphState(pod *core.Pod) (ServiceGraphState, error) {
+	return me.getServiceGraphState(pod, true)
+}
 
-	return &serviceGraphManagerImpl{
-		activeStates: sync.Map{},
-		client:       k8sClient,
+func (me *serviceGraphManagerImpl) LookupServiceGraphState(pod *core.Pod) (ServiceGraphState, error) {
+	return me.getServiceGraphState(pod, false)
+}
+
+func (me *serviceGraphManagerImpl) GetServiceGraphCRD(namespace, name string) *fogappsCRDs.ServiceGraph {
+	if entry := me.svcGraphs.get(getServiceGraphMapKey(namespace, name)); entry != nil {
+		return entry.crd
 	}
+	return nil
 }
 
-func (me *serviceGraphManagerImpl) AcquireServiceGraphState(pod *core.Pod) (ServiceGraphState, error) {
-	if svcGraphID := me.getServiceGraphID(pod); svcGraphID != nil {
-		return me.acquireServiceGraphStateInternal(svcGraphID, pod)
+// Gets or creates the ServiceGraphState for the pod's ServiceGraph.
+// If trackPod is true and the pod has not been bound yet, the pod keeps the ServiceGraphState alive
+// until the pod informer observes that it has been bound or deleted.
+func (me *serviceGraphManagerImpl) getServiceGraphState(pod *core.Pod, trackPod bool) (ServiceGraphState, error) {
+	svcGraphID := me.getServiceGraphID(pod)
+	if svcGraphID == nil {
+		return nil, nil
+	}
+	podIndexer := me.getPodIndexer()
+	if podIndexer == nil {
+		return nil, fmt.Errorf("the ServiceGraphManager is not watching any pod informer, WatchPods() must be called first")
+	}
+
+	// The pod must be tracked before getting the state, such that the state cannot be removed
+	// between getting it and tracking the pod.
+	if trackPod && pod.Spec.NodeName == "" {
+		me.trackUnboundPod(svcGraphID, pod)
 	}
-	return nil, nil
+	svcGraphState, err := me.getOrCreateServiceGraphState(svcGraphID, podIndexer)
+	if err != nil {
+		return nil, err
+	}
+	return svcGraphState, nil
 }
 
-func (me *serviceGraphManagerImpl) acquireServiceGraphStateInternal(svcGraphID *serviceGraphID, pod *core.Pod) (ServiceGraphState, error) {
-	var svcGraphState *serviceGraphStateImpl
-	var err error
+func (me *serviceGraphManagerImpl) WatchPods(podInformer cache.SharedIndexInformer) error {
+	me.podInformerMutex.Lock()
+	defer me.podInformerMutex.Unlock()
 
-	// We use a loop to get and acquire the ServiceGraphState, because we might get a state from the map
-	// and then the last goroutine using it releases it and marks it for deletion, before we can acquire it.
-	// In such a case acquire() will return false and we need to try again (this time we will likely create a new ServiceGraphState).
-	for acquired := false; !acquired; {
-		if svcGraphState, err = me.getOrCreateServiceGraphState(svcGraphID); err != nil {
-			return nil, err
+	if me.podInformer == podInformer {
+		return nil
+	}
+	if me.podInformer != nil {
+		return fmt.Errorf("the ServiceGraphManager is already watching another pod informer")
+	}
+
+	if _, exists := podInformer.GetIndexer().GetIndexers()[podsByServiceGraphIndex]; !exists {
+		if err := podInformer.AddIndexers(cache.Indexers{podsByServiceGraphIndex: indexPodByServiceGraph}); err != nil {
+			return err
 		}
-		acquired = svcGraphState.acquire(pod)
 	}
-	return svcGraphState, err
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    me.onPodAddedOrUpdated,
+		UpdateFunc: func(oldObj, newObj interface{}) { me.onPodAddedOrUpdated(newObj) },
+		DeleteFunc: me.onPodDeleted,
+	})
+	me.podInformer = podInformer
+	return nil
+}
+
+func (me *serviceGraphManagerImpl) Stop() {
+	me.svcGraphsWatcher.Stop()
+}
+
+// Updates the cache of ServiceGraph CRD instances until the svcGraphsWatcher is stopped.
+//
+// When the generation of a ServiceGraph changes, its active ServiceGraphState is refreshed.
+// When only the status of a ServiceGraph changes, the CRD instance of its active ServiceGraphState is replaced.
+// When a ServiceGraph is deleted, its active ServiceGraphState is removed.
+func (me *serviceGraphManagerImpl) watchServiceGraphs() {
+	for delta := range me.svcGraphsWatcher.DeltaChan() {
+		switch delta.Type {
+		case kubeutil.ListWatchAdded, kubeutil.ListWatchModified:
+			crd := delta.Object.(*fogappsCRDs.ServiceGraph)
+			mapKey := getServiceGraphMapKey(crd.Namespace, crd.Name)
+			if me.svcGraphs.set(crd) {
+				me.refreshServiceGraphState(mapKey)
+			} else {
+				me.updateServiceGraphStateCRD(mapKey)
+			}
+		case kubeutil.ListWatchDeleted:
+			mapKey := getServiceGraphMapKey(delta.Object.GetNamespace(), delta.Object.GetName())
+			me.svcGraphs.remove(mapKey)
+			me.activeStates.Delete(mapKey)
+		case kubeutil.ListWatchResync:
+			crds := delta.List.(*fogappsCRDs.ServiceGraphList)
+			changed, removed := me.svcGraphs.replaceAll(crds)
+			for _, mapKey := range changed {
+				me.refreshServiceGraphState(mapKey)
+			}
+			for i := range crds.Items {
+				me.updateServiceGraphStateCRD(getServiceGraphMapKey(crds.Items[i].Namespace, crds.Items[i].Name))
+			}
+			for _, mapKey := range removed {
+				me.activeStates.Delete(mapKey)
+			}
+		}
+	}
+}
+
+// Replaces the active ServiceGraphState of the specified ServiceGraph with a new state that is based on the
+// current generation of its CRD instance. The in-flight reservations are transferred to the new state.
+//
+// Plugins that have obtained the previous state before the refresh continue to work with that state until the end of their scheduling cycle.
+func (me *serviceGraphManagerImpl) refreshServiceGraphState(mapKey string) {
+	prevState := me.getExistingServiceGraphStateByKey(mapKey)
+	entry := me.svcGraphs.get(mapKey)
+	if prevState == nil || entry == nil {
+		return
+	}
+
+	svcGraphState := newServiceGraphStateImpl(entry.graph.get(), entry.crd, prevState.podIndexer, mapKey)
+	svcGraphState.takeOverReservations(prevState)
+	handle, resultProvider := util.NewFuture()
+	resultProvider(svcGraphState, nil)
+	me.activeStates.Store(mapKey, handle)
+	klog.Infof("Refreshed the ServiceGraphState of %s for generation %v", mapKey, entry.crd.Generation)
+}
+
+// Replaces the CRD instance of the active ServiceGraphState of the specified ServiceGraph with the one from the cache,
+// if both have the same generation.
+func (me *serviceGraphManagerImpl) updateServiceGraphStateCRD(mapKey string) {
+	svcGraphState := me.getExistingServiceGraphStateByKey(mapKey)
+	entry := me.svcGraphs.get(mapKey)
+	if svcGraphState == nil || entry == nil {
+		return
+	}
+	svcGraphState.updateCRD(entry.crd)
+}
+
+func (me *serviceGraphManagerImpl) getPodIndexer() cache.Indexer {
+	me.podInformerMutex.RLock()
+	defer me.podInformerMutex.RUnlock()
+	if me.podInformer == nil {
+		return nil
+	}
+	return me.podInformer.GetIndexer()
+}
+
+func (me *serviceGraphManagerImpl) onPodAddedOrUpdated(obj interface{}) {
+	pod, ok := obj.(*core.Pod)
+	if !ok {
+		return
+	}
+	if svcGraphID := me.getServiceGraphID(pod); svcGraphID != nil {
+		if svcGraphState := me.getExistingServiceGraphState(svcGraphID); svcGraphState != nil {
+			svcGraphState.onPodChanged(pod, false)
+		}
+		if pod.Spec.NodeName == "" && !isPodTerminated(pod) {
+			me.trackUnboundPod(svcGraphID, pod)
+		} else {
+			me.untrackPod(svcGraphID, pod)
+		}
+	}
+}
+
+func (me *serviceGraphManagerImpl) onPodDeleted(obj interface{}) {
+	var pod *core.Pod
+	switch t := obj.(type) {
+	case *core.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		if p, ok := t.Obj.(*core.Pod); ok {
+			pod = p
+		}
+	}
+	if pod == nil {
+		return
+	}
+	if svcGraphID := me.getServiceGraphID(pod); svcGraphID != nil {
+		if svcGraphState := me.getExistingServiceGraphState(svcGraphID); svcGraphState != nil {
+			svcGraphState.onPodChanged(pod, true)
+		}
+		me.untrackPod(svcGraphID, pod)
+	}
+}
+
+// Records that the pod has not been bound yet, which keeps the ServiceGraphState alive.
+func (me *serviceGraphManagerImpl) trackUnboundPod(svcGraphID *serviceGraphID, pod *core.Pod) {
+	me.unboundPodsMutex.Lock()
+	defer me.unboundPodsMutex.Unlock()
+
+	pods, ok := me.unboundPods[svcGraphID.mapKey]
+	if !ok {
+		pods = make(map[string]bool)
+		me.unboundPods[svcGraphID.mapKey] = pods
+	}
+	pods[pod.Name] = true
+}
+
+// Records that the pod has been observed as bound or deleted.
+// If this was the last unbound pod of the ServiceGraph, its ServiceGraphState is removed.
+// Pods that have never been tracked do not affect the ServiceGraphState, because the informer reports
+// every update of a bound pod and the states created by LookupServiceGraphState() do not track any pods.
+func (me *serviceGraphManagerImpl) untrackPod(svcGraphID *serviceGraphID, pod *core.Pod) {
+	me.unboundPodsMutex.Lock()
+	defer me.unboundPodsMutex.Unlock()
+
+	pods := me.unboundPods[svcGraphID.mapKey]
+	if !pods[pod.Name] {
+		return
+	}
+	delete(pods, pod.Name)
+	if len(pods) == 0 {
+		delete(me.unboundPods, svcGraphID.mapKey)
+		me.activeStates.Delete(svcGraphID.mapKey)
+	}
 }
 
 // Returns the service graph identifier from the pod
@@ -82,20 +301,32 @@ func (me *serviceGraphManagerImpl) getServiceGraphID(pod *core.Pod) *serviceGrap
 				Namespace: namespace,
 				Name:      svcGraphName,
 			},
-			mapKey: me.getServiceGraphMapKey(namespace, svcGraphName),
+			mapKey: getServiceGraphMapKey(namespace, svcGraphName),
 		}
 	}
 	return nil
 }
 
-// Returns the map key for the ServiceGraph with the given namespace and name.
-func (me *serviceGraphManagerImpl) getServiceGraphMapKey(namespace, svcGraphName string) string {
-	return fmt.Sprintf("%s.%s", namespace, svcGraphName)
+// Gets the ServiceGraphState from the activeStates map, if it exists and has been loaded successfully, otherwise returns nil.
+func (me *serviceGraphManagerImpl) getExistingServiceGraphState(svcGraphID *serviceGraphID) *serviceGraphStateImpl {
+	return me.getExistingServiceGraphStateByKey(svcGraphID.mapKey)
+}
+
+// Gets the ServiceGraphState with the specified map key from the activeStates map, if it exists and has been loaded successfully, otherwise returns nil.
+func (me *serviceGraphManagerImpl) getExistingServiceGraphStateByKey(mapKey string) *serviceGraphStateImpl {
+	handle, ok := me.activeStates.Load(mapKey)
+	if !ok {
+		return nil
+	}
+	if svcGraphState, err := handle.(util.Future).Get(); err == nil && svcGraphState != nil {
+		return svcGraphState.(*serviceGraphStateImpl)
+	}
+	return nil
 }
 
 // Gets a ServiceGraphState handle from the activeStates map and resolves the handle to the state object or
 // creates a new handle and state object.
-func (me *serviceGraphManagerImpl) getOrCreateServiceGraphState(svcGraphID *serviceGraphID) (*serviceGraphStateImpl, error) {
+func (me *serviceGraphManagerImpl) getOrCreateServiceGraphState(svcGraphID *serviceGraphID, podIndexer cache.Indexer) (*serviceGraphStateImpl, error) {
 	var handle util.Future
 
 	if existingHandle, ok := me.activeStates.Load(svcGraphID.mapKey); ok {
@@ -109,101 +340,55 @@ func (me *serviceGraphManagerImpl) getOrCreateServiceGraphState(svcGraphID *serv
 			handle = actualHandle.(util.Future)
 		} else {
 			handle = newHandle
-			me.createServiceGraphState(svcGraphID, resultProvider)
+			me.createServiceGraphState(svcGraphID, podIndexer, resultProvider)
 		}
 	}
 
 	svcGraphState, err := handle.Get()
-	return svcGraphState.(*serviceGraphStateImpl), err
+	if err != nil {
+		return nil, err
+	}
+	return svcGraphState.(*serviceGraphStateImpl), nil
 }
 
-// Loads the ServiceGraph CRD, assembles the respective graph, and starts asynchronously loading the placement map.
-func (me *serviceGraphManagerImpl) createServiceGraphState(svcGraphID *serviceGraphID, resultProvider util.ResultProvider) {
-	var crd fogappsCRDs.ServiceGraph
-	if err := me.client.Get(context.TODO(), svcGraphID.NamespacedName, &crd); err != nil {
-		resultProvider(nil, err)
+// Gets the ServiceGraph CRD and its graph from the cache and computes the placement map from the pod informer's cache.
+//
+// If the ServiceGraph has not been observed by the watch yet (e.g., because it has just been created), it is loaded using the client.
+// If the ServiceGraph cannot be loaded, the handle is removed from the activeStates map, such that the error is not cached.
+func (me *serviceGraphManagerImpl) createServiceGraphState(svcGraphID *serviceGraphID, podIndexer cache.Indexer, resultProvider util.ResultProvider) {
+	entry := me.svcGraphs.get(svcGraphID.mapKey)
+	if entry == nil {
+		var crd fogappsCRDs.ServiceGraph
+		if err := me.client.Get(context.TODO(), svcGraphID.NamespacedName, &crd); err != nil {
+			me.activeStates.Delete(svcGraphID.mapKey)
+			resultProvider(nil, err)
+			return
+		}
+		entry = newServiceGraphCacheEntry(&crd)
 	}
 
-	graph := servicegraph.FromCRDInstance(&crd)
-	placementMapHandle, placementMapResultProvider := util.NewFuture()
-
-	svcGraphState := newServiceGraphStateImpl(
-		graph,
-		&crd,
-		placementMapHandle,
-		func(state *serviceGraphStateImpl) { me.deleteServiceGraphState(state) },
-	)
+	svcGraphState := newServiceGraphStateImpl(entry.graph.get(), entry.crd, podIndexer, svcGraphID.mapKey)
 	resultProvider(svcGraphState, nil)
-	go me.buildPlacementMap(svcGraphState, placementMapResultProvider)
-}
-
-func (me *serviceGraphManagerImpl) deleteServiceGraphState(svcGraphState ServiceGraphState) {
-	crd := svcGraphState.ServiceGraphCRD()
-	mapKey := me.getServiceGraphMapKey(crd.Namespace, crd.Name)
-	me.activeStates.Delete(mapKey)
 }
 
-// Fetches the Deployments and StatefulSet statuses to determine on which nodes the pods of the ServiceGraph nodes have been scheduled.
-func (me *serviceGraphManagerImpl) buildPlacementMap(svcGraphState ServiceGraphState, resultProvider util.ResultProvider) {
-	svcGraphCRD := svcGraphState.ServiceGraphCRD()
-
-	pods, err := me.loadAllPodsForServiceGraph(svcGraphCRD)
-	if err != nil {
-		resultProvider(nil, err)
-		return
-	}
-
-	placementMap := me.computePlacementMap(svcGraphCRD, pods)
-	resultProvider(placementMap, nil)
+// Returns the map key for the ServiceGraph with the given namespace and name.
+func getServiceGraphMapKey(namespace, svcGraphName string) string {
+	return fmt.Sprintf("%s.%s", namespace, svcGraphName)
 }
 
-func (me *serviceGraphManagerImpl) loadAllPodsForServiceGraph(svcGraphCRD *fogappsCRDs.ServiceGraph) ([]core.Pod, error) {
-	labelsQuery := map[string]string{
-		kubeutil.LabelRefServiceGraph: svcGraphCRD.ObjectMeta.Name,
+// Indexes a pod by the map key of the ServiceGraph that it belongs to.
+func indexPodByServiceGraph(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*core.Pod)
+	if !ok {
+		return []string{}, nil
 	}
-	pods := core.PodList{}
-
-	err := me.client.List(
-		context.TODO(),
-		&pods,
-		client.InNamespace(svcGraphCRD.ObjectMeta.Namespace),
-		client.MatchingLabels(labelsQuery),
-	)
-	if err != nil {
-		return nil, err
+	if svcGraphName, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph); ok {
+		return []string{getServiceGraphMapKey(kubeutil.GetNamespace(pod), svcGraphName)}, nil
 	}
-	return pods.Items, nil
+	return []string{}, nil
 }
 
-func (me *serviceGraphManagerImpl) computePlacementMap(svcGraphCRD *fogappsCRDs.ServiceGraph, pods []core.Pod) serviceplacement.ServiceGraphPlacementMap {
-	// Maps each ServiceGraph node name to the set of K8s nodes, where at least one pod of it is placed.
-	servicePlacements := make(map[string]util.StringSet, len(svcGraphCRD.Spec.Nodes))
-
-	addK8sNode := func(svcGraphNodeName, k8sNodeName string) {
-		k8sNodes, ok := servicePlacements[svcGraphNodeName]
-		if !ok {
-			k8sNodes = util.NewStringSet()
-			servicePlacements[svcGraphNodeName] = k8sNodes
-		}
-		k8sNodes.Add(k8sNodeName)
-	}
-
-	for i := range pods {
-		pod := &pods[i]
-		svcGraphNode, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode)
-		if !ok {
-			continue
-		}
-		if k8sNode := pod.Spec.NodeName; k8sNode != "" {
-			addK8sNode(svcGraphNode, k8sNode)
-		}
-	}
-
-	isInitialPlacement := len(servicePlacements) == 0
-	placementMap := serviceplacement.NewServicePlacementMap(isInitialPlacement)
-	for svcGraphNode, placement := range servicePlacements {
-		k8sNodes := placement.Entries()
-		placementMap.SetKubernetesNodes(svcGraphNode, func(prev []string) []string { return k8sNodes })
-	}
-	return placementMap
+// Returns true if all containers of the pod have terminated and will not be restarted.
+func isPodTerminated(pod *core.Pod) bool {
+	return pod.Status.Phase == core.PodSucceeded || pod.Status.Phase == core.PodFailed
 }
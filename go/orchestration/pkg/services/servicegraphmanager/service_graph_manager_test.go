@@ -0,0 +1,351 @@
+package servicegraphmanager_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ServiceGraphManager", func() {
+
+	const timeout = 5 * time.Second
+
+	var svcGraphMgr servicegraphmanager.ServiceGraphManager
+	var clientset kubernetes.Interface
+	var crdClient client.WithWatch
+	var stopCh chan struct{}
+
+	newPod := func(name string, svcGraphNode string, nodeName string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels: map[string]string{
+					kubeutil.LabelRefServiceGraph:     "app",
+					kubeutil.LabelRefServiceGraphNode: svcGraphNode,
+				},
+			},
+			Spec: core.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	createPod := func(pod *core.Pod) {
+		_, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, meta.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	bindPod := func(pod *core.Pod, nodeName string) {
+		boundPod := pod.DeepCopy()
+		boundPod.Spec.NodeName = nodeName
+		_, err := clientset.CoreV1().Pods(pod.Namespace).Update(context.TODO(), boundPod, meta.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	getState := func(pod *core.Pod) servicegraphmanager.ServiceGraphState {
+		state, err := svcGraphMgr.GetServiceGraphState(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).ToNot(BeNil())
+		return state
+	}
+
+	getK8sNodes := func(state servicegraphmanager.ServiceGraphState, svcGraphNode string) func() []string {
+		return func() []string {
+			placementMap, err := state.PlacementMap()
+			Expect(err).ToNot(HaveOccurred())
+			return placementMap.GetKubernetesNodes(svcGraphNode)
+		}
+	}
+
+	// Starts the pod informer with the specified pods already present in the cluster.
+	startInformer := func(pods ...*core.Pod) {
+		objects := make([]runtime.Object, len(pods))
+		for i, pod := range pods {
+			objects[i] = pod
+		}
+		clientset = clientsetfake.NewSimpleClientset(objects...)
+		informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+		podInformer := informerFactory.Core().V1().Pods().Informer()
+		Expect(svcGraphMgr.WatchPods(podInformer)).To(Succeed())
+		Expect(svcGraphMgr.WatchPods(podInformer)).To(Succeed())
+
+		informerFactory.Start(stopCh)
+		Expect(cache.WaitForCacheSync(stopCh, podInformer.HasSynced)).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(fogappsCRDs.AddToScheme(scheme)).To(Succeed())
+
+		svcGraph := &fogappsCRDs.ServiceGraph{
+			ObjectMeta: meta.ObjectMeta{Name: "app", Namespace: "test"},
+			Spec: fogappsCRDs.ServiceGraphSpec{
+				Nodes: []fogappsCRDs.ServiceGraphNode{
+					{Name: "frontend", NodeType: fogappsCRDs.ServiceNode},
+					{Name: "backend", NodeType: fogappsCRDs.ServiceNode},
+				},
+			},
+		}
+		crdClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(svcGraph).Build()
+		var err error
+		svcGraphMgr, err = servicegraphmanager.NewServiceGraphManagerWithClient(crdClient)
+		Expect(err).ToNot(HaveOccurred())
+		stopCh = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		svcGraphMgr.Stop()
+		close(stopCh)
+	})
+
+	// Updates the ServiceGraph CRD instance, using the specified generation.
+	updateServiceGraph := func(generation int64, updateFn func(svcGraph *fogappsCRDs.ServiceGraph)) {
+		var svcGraph fogappsCRDs.ServiceGraph
+		Expect(crdClient.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: "app"}, &svcGraph)).To(Succeed())
+		svcGraph.Generation = generation
+		updateFn(&svcGraph)
+		Expect(crdClient.Update(context.TODO(), &svcGraph)).To(Succeed())
+	}
+
+	It("returns an error if no pod informer has been configured", func() {
+		_, err := svcGraphMgr.GetServiceGraphState(newPod("frontend-0", "frontend", ""))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns nil for pods without a ServiceGraph", func() {
+		startInformer()
+		state, err := svcGraphMgr.GetServiceGraphState(&core.Pod{ObjectMeta: meta.ObjectMeta{Name: "other", Namespace: "test"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(BeNil())
+	})
+
+	It("computes the placement map from the pod informer's cache", func() {
+		startInformer(
+			newPod("frontend-0", "frontend", "node-2"),
+			newPod("frontend-1", "frontend", "node-1"),
+			newPod("frontend-2", "frontend", "node-2"),
+			newPod("backend-0", "backend", ""),
+		)
+
+		state := getState(newPod("backend-0", "backend", ""))
+		placementMap, err := state.PlacementMap()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placementMap.IsInitialPlacement()).To(BeFalse())
+		Expect(placementMap.GetKubernetesNodes("frontend")).To(Equal([]string{"node-1", "node-2"}))
+		Expect(placementMap.GetKubernetesNodes("backend")).To(BeNil())
+		Expect(placementMap.GetPodCounts("frontend", "node-2")).To(Equal(serviceplacement.PodCounts{Bound: 2}))
+		Expect(placementMap.GetPodCounts("frontend", "node-3")).To(Equal(serviceplacement.PodCounts{}))
+		Expect(placementMap.GetAllPodCounts("frontend")).To(HaveLen(2))
+	})
+
+	It("merges in-flight reservations with the bound pods", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		backend := newPod("backend-0", "backend", "")
+		startInformer(frontend, backend)
+
+		state := getState(frontend)
+		state.ReservePod(frontend, "node-1")
+		Expect(getK8sNodes(state, "frontend")()).To(Equal([]string{"node-1"}))
+
+		// A new reservation of the same pod replaces the previous one.
+		state.ReservePod(frontend, "node-2")
+		Expect(getK8sNodes(state, "frontend")()).To(Equal([]string{"node-2"}))
+
+		// The pod informer observes the binding, which replaces the reservation.
+		bindPod(frontend, "node-3")
+		Eventually(getK8sNodes(state, "frontend"), timeout).Should(Equal([]string{"node-3"}))
+	})
+
+	It("counts reservations as assumed pods until they are unreserved", func() {
+		frontend0 := newPod("frontend-0", "frontend", "node-1")
+		frontend1 := newPod("frontend-1", "frontend", "")
+		frontend2 := newPod("frontend-2", "frontend", "")
+		startInformer(frontend0, frontend1, frontend2)
+
+		state := getState(frontend1)
+		state.ReservePod(frontend1, "node-1")
+		state.ReservePod(frontend2, "node-2")
+		placementMap, err := state.PlacementMap()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placementMap.GetPodCounts("frontend", "node-1")).To(Equal(serviceplacement.PodCounts{Assumed: 1, Bound: 1}))
+		Expect(placementMap.GetKubernetesNodes("frontend")).To(Equal([]string{"node-1", "node-2"}))
+
+		// Unreserving a pod must not remove a node that still hosts a bound pod.
+		state.UnreservePod(frontend1)
+		Expect(placementMap.GetPodCounts("frontend", "node-1")).To(Equal(serviceplacement.PodCounts{Bound: 1}))
+		state.UnreservePod(frontend2)
+		Expect(placementMap.GetKubernetesNodes("frontend")).To(Equal([]string{"node-1"}))
+
+		// Unreserving a pod that has not been reserved has no effect.
+		state.UnreservePod(frontend2)
+		Expect(placementMap.GetKubernetesNodes("frontend")).To(Equal([]string{"node-1"}))
+	})
+
+	It("keeps the state of an initial placement until all pods have been observed as bound", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		backend := newPod("backend-0", "backend", "")
+		startInformer(frontend, backend)
+
+		state := getState(frontend)
+		placementMap, err := state.PlacementMap()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placementMap.IsInitialPlacement()).To(BeTrue())
+
+		// The frontend pod is bound, but the backend pod is still pending, so the state must be retained.
+		bindPod(frontend, "node-1")
+		Eventually(getK8sNodes(state, "frontend"), timeout).Should(Equal([]string{"node-1"}))
+		Expect(getState(backend)).To(BeIdenticalTo(state))
+
+		// Once the backend pod has been observed as bound, the state is removed and a new one is created on the next request.
+		bindPod(backend, "node-2")
+		Eventually(func() servicegraphmanager.ServiceGraphState {
+			return getState(newPod("frontend-0", "frontend", "node-1"))
+		}, timeout).ShouldNot(BeIdenticalTo(state))
+	})
+
+	It("does not keep the state alive for pods that are only looked up", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		startInformer(frontend)
+		state := getState(frontend)
+
+		// The scheduling queue may still hold an outdated copy of a pod that has already been bound or deleted.
+		lookedUpState, err := svcGraphMgr.LookupServiceGraphState(newPod("backend-0", "backend", ""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lookedUpState).To(BeIdenticalTo(state))
+
+		// The frontend pod was the only pending pod, so the state is removed once its binding has been observed.
+		bindPod(frontend, "node-1")
+		Eventually(func() servicegraphmanager.ServiceGraphState {
+			return getState(newPod("frontend-0", "frontend", "node-1"))
+		}, timeout).ShouldNot(BeIdenticalTo(state))
+	})
+
+	It("keeps the state when pods that have never been pending are updated", func() {
+		startInformer(newPod("frontend-0", "frontend", "node-1"))
+		state, err := svcGraphMgr.LookupServiceGraphState(newPod("backend-0", "backend", ""))
+		Expect(err).ToNot(HaveOccurred())
+
+		// The pods are added one after another, such that the handler of the first one has completed
+		// once the second one is contained in the placement map.
+		createPod(newPod("frontend-1", "frontend", "node-2"))
+		Eventually(getK8sNodes(state, "frontend"), timeout).Should(ConsistOf("node-1", "node-2"))
+		createPod(newPod("backend-0", "backend", "node-3"))
+		Eventually(getK8sNodes(state, "backend"), timeout).Should(Equal([]string{"node-3"}))
+
+		Expect(getState(newPod("frontend-0", "frontend", "node-1"))).To(BeIdenticalTo(state))
+	})
+
+	It("refreshes the state when the generation of the ServiceGraph changes", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		startInformer(frontend)
+		state := getState(frontend)
+		state.ReservePod(frontend, "node-1")
+		Expect(state.ServiceGraph().NodeByLabel("database")).To(BeNil())
+
+		updateServiceGraph(2, func(svcGraph *fogappsCRDs.ServiceGraph) {
+			svcGraph.Spec.Nodes = append(svcGraph.Spec.Nodes, fogappsCRDs.ServiceGraphNode{Name: "database", NodeType: fogappsCRDs.ServiceNode})
+		})
+		Eventually(func() servicegraphmanager.ServiceGraphState {
+			return getState(frontend)
+		}, timeout).ShouldNot(BeIdenticalTo(state))
+
+		// The new state is based on the new generation and has taken over the reservations.
+		newState := getState(frontend)
+		Expect(newState.ServiceGraph().NodeByLabel("database")).ToNot(BeNil())
+		Expect(newState.ServiceGraphCRD().Generation).To(Equal(int64(2)))
+		Expect(getK8sNodes(newState, "frontend")()).To(Equal([]string{"node-1"}))
+	})
+
+	It("keeps the state when the ServiceGraph changes without a new generation", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		startInformer(frontend)
+		state := getState(frontend)
+
+		updateServiceGraph(0, func(svcGraph *fogappsCRDs.ServiceGraph) {
+			svcGraph.Labels = map[string]string{"updated": "true"}
+		})
+		Consistently(func() servicegraphmanager.ServiceGraphState {
+			return getState(frontend)
+		}, time.Second).Should(BeIdenticalTo(state))
+
+		// The CRD instance of the state is replaced by the updated one.
+		Expect(state.ServiceGraphCRD().Labels).To(HaveKeyWithValue("updated", "true"))
+	})
+
+	It("returns the observed ServiceGraph CRD instances", func() {
+		Eventually(func() *fogappsCRDs.ServiceGraph {
+			return svcGraphMgr.GetServiceGraphCRD("test", "app")
+		}, timeout).ShouldNot(BeNil())
+		Expect(svcGraphMgr.GetServiceGraphCRD("test", "app").Spec.Nodes).To(HaveLen(2))
+		Expect(svcGraphMgr.GetServiceGraphCRD("test", "unknown")).To(BeNil())
+	})
+
+	It("counts the assumed and bound pods of a generation", func() {
+		newPodOfGeneration := func(name string, nodeName string, generation string) *core.Pod {
+			pod := newPod(name, "frontend", nodeName)
+			pod.Annotations = map[string]string{kubeutil.AnnotationServiceGraphGeneration: generation}
+			return pod
+		}
+		oldPod := newPodOfGeneration("frontend-0", "node-1", "1")
+		boundPod := newPodOfGeneration("frontend-1", "node-1", "2")
+		pendingPod := newPodOfGeneration("frontend-2", "", "2")
+		startInformer(oldPod, boundPod, pendingPod)
+
+		state := getState(pendingPod)
+		Expect(state.CountPodsOfGeneration("frontend", 1)).To(Equal(1))
+		Expect(state.CountPodsOfGeneration("frontend", 2)).To(Equal(1))
+
+		state.ReservePod(pendingPod, "node-2")
+		Expect(state.CountPodsOfGeneration("frontend", 2)).To(Equal(2))
+		Expect(state.CountPodsOfGeneration("backend", 2)).To(Equal(0))
+
+		state.UnreservePod(pendingPod)
+		Expect(state.CountPodsOfGeneration("frontend", 2)).To(Equal(1))
+	})
+
+	It("removes the state when the ServiceGraph is deleted", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		startInformer(frontend)
+		getState(frontend)
+
+		Expect(crdClient.Delete(context.TODO(), &fogappsCRDs.ServiceGraph{ObjectMeta: meta.ObjectMeta{Name: "app", Namespace: "test"}})).To(Succeed())
+		Eventually(func() error {
+			_, err := svcGraphMgr.GetServiceGraphState(frontend)
+			return err
+		}, timeout).Should(HaveOccurred())
+	})
+
+	It("removes the state when the last pending pod is deleted", func() {
+		frontend := newPod("frontend-0", "frontend", "")
+		startInformer(frontend)
+		state := getState(frontend)
+
+		createPod(newPod("frontend-1", "frontend", "node-1"))
+		Eventually(getK8sNodes(state, "frontend"), timeout).Should(Equal([]string{"node-1"}))
+		Expect(getState(newPod("frontend-1", "frontend", "node-1"))).To(BeIdenticalTo(state))
+
+		Expect(clientset.CoreV1().Pods("test").Delete(context.TODO(), frontend.Name, meta.DeleteOptions{})).To(Succeed())
+		Eventually(func() servicegraphmanager.ServiceGraphState {
+			return getState(newPod("frontend-1", "frontend", "node-1"))
+		}, timeout).ShouldNot(BeIdenticalTo(state))
+	})
+
+})
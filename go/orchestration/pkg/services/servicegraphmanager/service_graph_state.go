@@ -4,10 +4,11 @@ import (
 	"sync"
 
 	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/util"
 )
 
 var (
@@ -18,10 +19,8 @@ var (
 // a CRD object and as a traversable graph and allows tracking the
 // placement of pods on Kubernetes nodes.
 //
-// When obtaining a ServiceGraphState from the ServiceGraphManager its internal reference counter
-// that tracks the pods it is used by is incremented. When the pod's scheduling cycle ends, it
-// must Release() the ServiceGraphState to decrement the reference count and allow the state to
-// be removed from the shared cache.
+// The ServiceGraphManager keeps a ServiceGraphState in its cache as long as pods of the application
+// have not been observed as bound, so there is no need to release it.
 type ServiceGraphState interface {
 	// Gets the ServiceGraph graph object.
 	// This must be treated as immutable.
@@ -29,23 +28,45 @@ type ServiceGraphState interface {
 
 	// Gets the ServiceGraph CRD instance.
 	// This must be treated as immutable.
+	//
+	// The spec of the returned instance always matches the graph returned by ServiceGraph(). When the status of the ServiceGraph
+	// changes, without changing its generation, the instance is replaced by the updated one.
 	ServiceGraphCRD() *fogappsCRDs.ServiceGraph
 
 	// Gets the ServicePlacementMap for the service graph.
 	//
-	// If the map has not yet been loaded, this method will block until the loading has completed.
+	// The map contains the pods that have been observed as bound by the pod informer and the
+	// in-flight reservations made through ReservePod(), which are counted as assumed pods.
+	// It is kept up to date as long as the ServiceGraphState is in the ServiceGraphManager's cache.
+	// The map must not be modified directly, use ReservePod() and UnreservePod() instead.
 	PlacementMap() (serviceplacement.ServiceGraphPlacementMap, error)
 
 	// Gets the map that contains the scheduling priorities of the nodes.
 	NodePriorityMap() NodePriorityMap
 
-	// Removes the specified pod from the reference count list.
-	Release(pod *core.Pod)
+	// ReservePod records that the pod has been reserved on the specified K8s node and adds the node to the placement map.
+	// The reservation is kept until the pod informer observes that the pod has been bound or deleted.
+	// If the pod has already been reserved on another node, the previous reservation is replaced.
+	ReservePod(pod *core.Pod, nodeName string)
+
+	// UnreservePod removes the reservation of the pod, e.g., because its binding has failed, and updates the placement map.
+	// If the pod has not been reserved, this has no effect.
+	UnreservePod(pod *core.Pod)
+
+	// CountPodsOfGeneration returns the number of assumed and bound pods of the ServiceGraphNode, which have been created
+	// from the pod template of the specified generation of the ServiceGraph (see kubeutil.AnnotationServiceGraphGeneration).
+	CountPodsOfGeneration(svcGraphNode string, generation int64) int
 }
 
-// Used to notify the ServiceGraphManager when all references to a ServiceGraphState have been cleared
-// and the state is ready for being deleted.
-type onServiceGraphStateUnreferencedFn func(svcGraphState *serviceGraphStateImpl)
+// The placement of a pod on a K8s node, which is either a reservation that has not been observed as bound yet
+// or a binding that has been observed by the pod informer.
+type podPlacement struct {
+	svcGraphNode string
+	nodeName     string
+
+	// The value of the pod's AnnotationServiceGraphGeneration annotation or 0, if the pod does not have this annotation.
+	generation int64
+}
 
 // Default implementation of ServiceGraphState.
 type serviceGraphStateImpl struct {
@@ -57,39 +78,62 @@ type serviceGraphStateImpl struct {
 	crd *fogappsCRDs.ServiceGraph
 
 	// The ServiceGraphPlacementMap that indicates on which nodes a service has been placed.
-	placementMap util.Future
+	placementMap serviceplacement.ServiceGraphPlacementMap
+
+	// The cache of the pod informer, which contains the pods of all ServiceGraphs.
+	podIndexer cache.Indexer
+
+	// The key of this ServiceGraph in the podsByServiceGraphIndex.
+	indexKey string
+
+	// Maps the names of the pods that have been reserved, but not yet observed as bound, to their reservations.
+	reservations map[string]*podPlacement
+
+	// Maps the names of the pods that have been observed as bound and have not terminated to their placements.
+	boundPods map[string]*podPlacement
+
+	// The assumed and bound pod counts of each ServiceGraph node (first key) on each K8s node (second key).
+	// These are updated incrementally from the reservations and the pod events and copied into the placement map.
+	podCounts map[string]map[string]serviceplacement.PodCounts
+
+	// Synchronizes the updates of the placement map and the access to the reservations, boundPods, podCounts, and crd.
+	mutex sync.Mutex
 
 	// The scheduling priorities of the nodes.
 	// This field is initialized lazily.
 	nodePriorities NodePriorityMap
 
-	// Tracks the pod names that this object is referenced by in a set like fashion.
-	// When the reference count drop to 0 after the initial acquire(),
-	// this map will be set to nil to make the ServiceGraphState object unavailable for
-	// future acquisition (i.e., the ServiceGraph must be fetched again and a new state must be created).
-	referencedBy map[string]bool
-
-	// Synchronizes access to the referencedBy map.
-	referencedByMutex sync.Mutex
-
-	// Called when the reference count drops to zero.
-	readyForDeletionCallback onServiceGraphStateUnreferencedFn
+	// Synchronizes the lazy initialization of nodePriorities.
+	nodePrioritiesOnce sync.Once
 }
 
 func newServiceGraphStateImpl(
 	graph servicegraph.ServiceGraph,
 	crd *fogappsCRDs.ServiceGraph,
-	placementMap util.Future,
-	readyForDeletionCallback onServiceGraphStateUnreferencedFn,
+	podIndexer cache.Indexer,
+	indexKey string,
 ) *serviceGraphStateImpl {
-	return &serviceGraphStateImpl{
-		graph:                    graph,
-		crd:                      crd,
-		placementMap:             placementMap,
-		referencedBy:             make(map[string]bool),
-		referencedByMutex:        sync.Mutex{},
-		readyForDeletionCallback: readyForDeletionCallback,
+	state := &serviceGraphStateImpl{
+		graph:        graph,
+		crd:          crd,
+		podIndexer:   podIndexer,
+		indexKey:     indexKey,
+		reservations: make(map[string]*podPlacement),
+		boundPods:    make(map[string]*podPlacement),
+		podCounts:    make(map[string]map[string]serviceplacement.PodCounts),
+	}
+
+	for _, pod := range state.getIndexedPods() {
+		if placement := newBoundPodPlacement(pod); placement != nil {
+			state.boundPods[pod.Name] = placement
+			state.addToPodCounts(placement, 0, 1)
+		}
+	}
+	state.placementMap = serviceplacement.NewServicePlacementMap(len(state.boundPods) == 0)
+	for svcGraphNode, svcNodeCounts := range state.podCounts {
+		state.placementMap.SetPodCounts(svcGraphNode, svcNodeCounts)
 	}
+	return state
 }
 
 func (me *serviceGraphStateImpl) ServiceGraph() servicegraph.ServiceGraph {
@@ -97,42 +141,185 @@ func (me *serviceGraphStateImpl) ServiceGraph() servicegraph.ServiceGraph {
 }
 
 func (me *serviceGraphStateImpl) ServiceGraphCRD() *fogappsCRDs.ServiceGraph {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
 	return me.crd
 }
 
 func (me *serviceGraphStateImpl) PlacementMap() (serviceplacement.ServiceGraphPlacementMap, error) {
-	placementMap, err := me.placementMap.Get()
-	return placementMap.(serviceplacement.ServiceGraphPlacementMap), err
+	return me.placementMap, nil
 }
 
 func (me *serviceGraphStateImpl) NodePriorityMap() NodePriorityMap {
-	if me.nodePriorities == nil {
+	me.nodePrioritiesOnce.Do(func() {
 		me.nodePriorities = NewNodePriorityMapFromServiceGraph(me.graph)
-	}
+	})
 	return me.nodePriorities
 }
 
-func (me *serviceGraphStateImpl) Release(pod *core.Pod) {
-	me.referencedByMutex.Lock()
-	defer me.referencedByMutex.Unlock()
+func (me *serviceGraphStateImpl) ReservePod(pod *core.Pod, nodeName string) {
+	svcGraphNode, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode)
+	if !ok {
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	generation, _ := kubeutil.GetServiceGraphGeneration(pod)
+	me.removeReservation(pod.Name)
+	reservation := &podPlacement{svcGraphNode: svcGraphNode, nodeName: nodeName, generation: generation}
+	me.reservations[pod.Name] = reservation
+	me.updatePodCounts(reservation, 1, 0)
+}
+
+func (me *serviceGraphStateImpl) UnreservePod(pod *core.Pod) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	me.removeReservation(pod.Name)
+}
+
+func (me *serviceGraphStateImpl) CountPodsOfGeneration(svcGraphNode string, generation int64) int {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	count := 0
+	for _, placements := range []map[string]*podPlacement{me.boundPods, me.reservations} {
+		for _, placement := range placements {
+			if placement.svcGraphNode == svcGraphNode && placement.generation == generation {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Replaces the CRD instance with an updated instance of the same generation, e.g., after a status update.
+func (me *serviceGraphStateImpl) updateCRD(crd *fogappsCRDs.ServiceGraph) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	if me.crd.Generation == crd.Generation {
+		me.crd = crd
+	}
+}
+
+// Copies the reservations of the previous ServiceGraphState of the same ServiceGraph into this state and updates the placement map.
+func (me *serviceGraphStateImpl) takeOverReservations(prevState *serviceGraphStateImpl) {
+	prevState.mutex.Lock()
+	reservations := make(map[string]*podPlacement, len(prevState.reservations))
+	for podName, reservation := range prevState.reservations {
+		reservations[podName] = reservation
+	}
+	prevState.mutex.Unlock()
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	for podName, reservation := range reservations {
+		if me.boundPods[podName] != nil {
+			// The binding of the pod has already been observed when this state was created.
+			continue
+		}
+		me.removeReservation(podName)
+		me.reservations[podName] = reservation
+		me.updatePodCounts(reservation, 1, 0)
+	}
+}
+
+// Updates the placement map after the pod informer has observed a change of the specified pod.
+//
+// The pod counts are updated incrementally from the pod's previous and current placement, so applying the same change
+// twice (e.g., when the state has been created from the informer's cache after the change, but before its event was delivered) has no effect.
+func (me *serviceGraphStateImpl) onPodChanged(pod *core.Pod, deleted bool) {
+	if _, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode); !ok {
+		return
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	if deleted || pod.Spec.NodeName != "" || isPodTerminated(pod) {
+		me.removeReservation(pod.Name)
+	}
+	var placement *podPlacement
+	if !deleted {
+		placement = newBoundPodPlacement(pod)
+	}
+	me.setBoundPod(pod.Name, placement)
+}
+
+// Removes the reservation of the pod, if there is one, and updates the placement map.
+// This must be called while holding the mutex.
+func (me *serviceGraphStateImpl) removeReservation(podName string) {
+	if reservation := me.reservations[podName]; reservation != nil {
+		delete(me.reservations, podName)
+		me.updatePodCounts(reservation, -1, 0)
+	}
+}
 
-	delete(me.referencedBy, pod.Name)
-	if len(me.referencedBy) == 0 {
-		me.referencedBy = nil
-		me.readyForDeletionCallback(me)
+// Records the placement of a pod that has been observed as bound or removes the pod's placement if placement is nil,
+// and updates the placement map.
+// This must be called while holding the mutex.
+func (me *serviceGraphStateImpl) setBoundPod(podName string, placement *podPlacement) {
+	if prevPlacement := me.boundPods[podName]; prevPlacement != nil {
+		if placement != nil && *placement == *prevPlacement {
+			return
+		}
+		delete(me.boundPods, podName)
+		me.updatePodCounts(prevPlacement, 0, -1)
 	}
+	if placement != nil {
+		me.boundPods[podName] = placement
+		me.updatePodCounts(placement, 0, 1)
+	}
+}
+
+// Adds the deltas to the pod counts of the placement's ServiceGraph node on the placement's K8s node
+// and copies the pod counts of that ServiceGraph node into the placement map.
+// This must be called while holding the mutex.
+func (me *serviceGraphStateImpl) updatePodCounts(placement *podPlacement, assumedDelta, boundDelta int) {
+	me.addToPodCounts(placement, assumedDelta, boundDelta)
+	me.placementMap.SetPodCounts(placement.svcGraphNode, me.podCounts[placement.svcGraphNode])
 }
 
-// Adds the specified pod to the reference count of this state.
-func (me *serviceGraphStateImpl) acquire(pod *core.Pod) bool {
-	me.referencedByMutex.Lock()
-	defer me.referencedByMutex.Unlock()
+// Adds the deltas to the pod counts of the placement's ServiceGraph node on the placement's K8s node.
+func (me *serviceGraphStateImpl) addToPodCounts(placement *podPlacement, assumedDelta, boundDelta int) {
+	svcNodeCounts, ok := me.podCounts[placement.svcGraphNode]
+	if !ok {
+		svcNodeCounts = make(map[string]serviceplacement.PodCounts)
+		me.podCounts[placement.svcGraphNode] = svcNodeCounts
+	}
 
-	if me.referencedBy != nil {
-		me.referencedBy[pod.Name] = true
-		return true
+	counts := svcNodeCounts[placement.nodeName]
+	counts.Assumed += assumedDelta
+	counts.Bound += boundDelta
+	if counts.Assumed == 0 && counts.Bound == 0 {
+		delete(svcNodeCounts, placement.nodeName)
+	} else {
+		svcNodeCounts[placement.nodeName] = counts
 	}
+}
 
-	// This state object is ready for deletion, it cannot be acquired anymore.
-	return false
+// Gets the pods of this ServiceGraph from the pod informer's cache.
+func (me *serviceGraphStateImpl) getIndexedPods() []*core.Pod {
+	objs, err := me.podIndexer.ByIndex(podsByServiceGraphIndex, me.indexKey)
+	if err != nil {
+		return []*core.Pod{}
+	}
+	pods := make([]*core.Pod, 0, len(objs))
+	for _, obj := range objs {
+		if pod, ok := obj.(*core.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// Returns the placement of the pod if it has been bound and has not terminated, otherwise nil.
+func newBoundPodPlacement(pod *core.Pod) *podPlacement {
+	svcGraphNode, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode)
+	if !ok || pod.Spec.NodeName == "" || isPodTerminated(pod) {
+		return nil
+	}
+	generation, _ := kubeutil.GetServiceGraphGeneration(pod)
+	return &podPlacement{svcGraphNode: svcGraphNode, nodeName: pod.Spec.NodeName, generation: generation}
 }
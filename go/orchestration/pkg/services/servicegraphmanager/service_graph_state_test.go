@@ -0,0 +1,140 @@
+package servicegraphmanager
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
+)
+
+var _ = Describe("serviceGraphStateImpl pod counts", func() {
+
+	var state *serviceGraphStateImpl
+	var podIndexer cache.Indexer
+
+	newPod := func(name string, svcGraphNode string, nodeName string, generation string) *core.Pod {
+		pod := &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels: map[string]string{
+					kubeutil.LabelRefServiceGraph:     "app",
+					kubeutil.LabelRefServiceGraphNode: svcGraphNode,
+				},
+			},
+			Spec: core.PodSpec{NodeName: nodeName},
+		}
+		if generation != "" {
+			pod.Annotations = map[string]string{kubeutil.AnnotationServiceGraphGeneration: generation}
+		}
+		return pod
+	}
+
+	// Updates the pod in the informer's cache and notifies the state, like the ServiceGraphManager does.
+	updatePod := func(pod *core.Pod) {
+		Expect(podIndexer.Update(pod)).To(Succeed())
+		state.onPodChanged(pod, false)
+	}
+
+	deletePod := func(pod *core.Pod) {
+		Expect(podIndexer.Delete(pod)).To(Succeed())
+		state.onPodChanged(pod, true)
+	}
+
+	podCounts := func(svcGraphNode string) map[string]serviceplacement.PodCounts {
+		return state.placementMap.GetAllPodCounts(svcGraphNode)
+	}
+
+	BeforeEach(func() {
+		podIndexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{podsByServiceGraphIndex: indexPodByServiceGraph})
+		for _, pod := range []*core.Pod{
+			newPod("frontend-0", "frontend", "node-1", "1"),
+			newPod("frontend-1", "frontend", "node-1", "2"),
+			newPod("backend-0", "backend", "", "1"),
+		} {
+			Expect(podIndexer.Add(pod)).To(Succeed())
+		}
+
+		crd := &fogappsCRDs.ServiceGraph{
+			ObjectMeta: meta.ObjectMeta{Name: "app", Namespace: "test"},
+			Spec: fogappsCRDs.ServiceGraphSpec{
+				Nodes: []fogappsCRDs.ServiceGraphNode{
+					{Name: "frontend", NodeType: fogappsCRDs.ServiceNode},
+					{Name: "backend", NodeType: fogappsCRDs.ServiceNode},
+				},
+			},
+		}
+		state = newServiceGraphStateImpl(servicegraph.FromCRDInstance(crd), crd, podIndexer, getServiceGraphMapKey("test", "app"))
+	})
+
+	It("counts the bound pods of the informer's cache", func() {
+		Expect(state.placementMap.IsInitialPlacement()).To(BeFalse())
+		Expect(podCounts("frontend")).To(Equal(map[string]serviceplacement.PodCounts{"node-1": {Bound: 2}}))
+		Expect(podCounts("backend")).To(BeNil())
+		Expect(state.CountPodsOfGeneration("frontend", 1)).To(Equal(1))
+		Expect(state.CountPodsOfGeneration("frontend", 2)).To(Equal(1))
+	})
+
+	It("ignores repeated events for a pod that has already been counted", func() {
+		pod := newPod("frontend-0", "frontend", "node-1", "1")
+		state.onPodChanged(pod, false)
+		state.onPodChanged(pod, false)
+		Expect(podCounts("frontend")).To(Equal(map[string]serviceplacement.PodCounts{"node-1": {Bound: 2}}))
+		Expect(state.CountPodsOfGeneration("frontend", 1)).To(Equal(1))
+	})
+
+	It("turns a reservation into a bound pod once the binding is observed", func() {
+		backend := newPod("backend-0", "backend", "", "1")
+		state.ReservePod(backend, "node-2")
+		Expect(podCounts("backend")).To(Equal(map[string]serviceplacement.PodCounts{"node-2": {Assumed: 1}}))
+		Expect(state.CountPodsOfGeneration("backend", 1)).To(Equal(1))
+
+		// A reservation on another node replaces the previous one.
+		state.ReservePod(backend, "node-1")
+		Expect(podCounts("backend")).To(Equal(map[string]serviceplacement.PodCounts{"node-1": {Assumed: 1}}))
+
+		updatePod(newPod("backend-0", "backend", "node-1", "1"))
+		Expect(podCounts("backend")).To(Equal(map[string]serviceplacement.PodCounts{"node-1": {Bound: 1}}))
+		Expect(state.CountPodsOfGeneration("backend", 1)).To(Equal(1))
+	})
+
+	It("removes terminated and deleted pods", func() {
+		terminated := newPod("frontend-0", "frontend", "node-1", "1")
+		terminated.Status.Phase = core.PodSucceeded
+		updatePod(terminated)
+		Expect(podCounts("frontend")).To(Equal(map[string]serviceplacement.PodCounts{"node-1": {Bound: 1}}))
+		Expect(state.CountPodsOfGeneration("frontend", 1)).To(Equal(0))
+
+		deletePod(newPod("frontend-1", "frontend", "node-1", "2"))
+		Expect(podCounts("frontend")).To(BeEmpty())
+		Expect(state.placementMap.GetKubernetesNodes("frontend")).To(BeEmpty())
+
+		// Deleting a pod again has no effect.
+		deletePod(newPod("frontend-1", "frontend", "node-1", "2"))
+		Expect(podCounts("frontend")).To(BeEmpty())
+	})
+
+	It("moves the counts of a pod whose ServiceGraph node changes", func() {
+		updatePod(newPod("frontend-1", "backend", "node-2", "2"))
+		Expect(podCounts("frontend")).To(Equal(map[string]serviceplacement.PodCounts{"node-1": {Bound: 1}}))
+		Expect(podCounts("backend")).To(Equal(map[string]serviceplacement.PodCounts{"node-2": {Bound: 1}}))
+	})
+
+	It("takes over the reservations of a previous state, unless their bindings have already been observed", func() {
+		state.ReservePod(newPod("backend-0", "backend", "", "1"), "node-2")
+		state.ReservePod(newPod("backend-1", "backend", "", "1"), "node-2")
+		Expect(podIndexer.Update(newPod("backend-1", "backend", "node-2", "1"))).To(Succeed())
+
+		newState := newServiceGraphStateImpl(state.graph, state.crd, podIndexer, state.indexKey)
+		newState.takeOverReservations(state)
+		Expect(newState.placementMap.GetAllPodCounts("backend")).To(Equal(map[string]serviceplacement.PodCounts{"node-2": {Assumed: 1, Bound: 1}}))
+		Expect(newState.CountPodsOfGeneration("backend", 1)).To(Equal(2))
+	})
+
+})
@@ -0,0 +1,13 @@
+package servicegraphmanager_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestServiceGraphManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ServiceGraphManager Suite")
+}
@@ -49,6 +49,7 @@ func (me *stringSetImpl) Entries() []string {
 	i := 0
 	for key := range me.entries {
 		ret[i] = key
+		i++
 	}
 	return ret
 }
@@ -23,6 +23,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/component-base/logs"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -30,15 +31,9 @@ import (
 	fogappsv1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
 	slov1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/slo/v1"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
 
-	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/atomicdeployment"
-	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/networkqos"
-	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/nodecost"
-	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/podspernode"
-	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraph"
-	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/workloadtype"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/registry"
 )
 
 var (
@@ -57,22 +52,24 @@ func main() {
 	rand.Seed(time.Now().UnixNano())
 	initScheme()
 
-	// Initialize the ConfigManager, RegionManager, and ServiceGraphManager.
-	configmanager.InitConfigManager(ctrl.GetConfigOrDie(), scheme)
-	regionmanager.InitRegionManager()
-	servicegraphmanager.InitServiceGraphManager()
+	// Create the RegionManager, ServiceGraphManager, and BandwidthLedger that are shared by the plugins.
+	configMgr := configmanager.NewConfigManager(ctrl.GetConfigOrDie(), scheme)
+	services, err := pluginservices.NewPluginServices(configMgr)
+	if err != nil {
+		klog.ErrorS(err, "Unable to create the plugin services")
+		os.Exit(1)
+	}
+	defer services.Stop()
 
 	// When executed, the command returned by NewSchedulerCommand(), uses
 	// scheduler.WithFrameworkOutOfTreeRegistry(outOfTreeRegistry) to append the specified plugins to
 	// the default plugins (see Kubernetes source: cmd/kube-scheduler/app/server.go).
-	command := app.NewSchedulerCommand(
-		app.WithPlugin(servicegraph.PluginName, servicegraph.New),
-		app.WithPlugin(networkqos.PluginName, networkqos.New),
-		app.WithPlugin(podspernode.PluginName, podspernode.New),
-		app.WithPlugin(nodecost.PluginName, nodecost.New),
-		app.WithPlugin(workloadtype.PluginName, workloadtype.New),
-		app.WithPlugin(atomicdeployment.PluginName, atomicdeployment.New),
-	)
+	polarisRegistry := registry.NewPolarisRegistry(services)
+	options := make([]app.Option, 0, len(polarisRegistry))
+	for name, factory := range polarisRegistry {
+		options = append(options, app.WithPlugin(name, factory))
+	}
+	command := app.NewSchedulerCommand(options...)
 
 	logs.InitLogs()
 	defer logs.FlushLogs()
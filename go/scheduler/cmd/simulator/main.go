@@ -0,0 +1,111 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/simulator"
+)
+
+const usage = `Usage: simulator [flags] <file>...
+
+Schedules the pods of the ServiceGraphs in the specified files against a simulated cluster, using the Polaris scheduler plugins,
+and prints the resulting placements and the per-plugin scores of the nodes.
+The files may contain Nodes, NetworkLinks, ServiceGraphs, and Pods as multi-document YAML or JSON.
+
+Flags:
+`
+
+func main() {
+	topologyFile := flag.String("topology", "", "A NetworkTopology file that describes additional NetworkLinks.")
+	schedulerConfigFile := flag.String("scheduler-config", "", "A KubeSchedulerConfiguration file, from which the plugin args of the polaris-scheduler profile are loaded.")
+	synthesizeNodes := flag.Bool("synthesize-nodes", true, "Create a node with default resources for every node name that is referenced by a NetworkLink, but not defined in the files.")
+	output := flag.String("output", "yaml", "The output format of the report: yaml or json.")
+	klog.InitFlags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 && *topologyFile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output != "yaml" && *output != "json" {
+		exitWithError(fmt.Errorf("unknown output format: %s", *output))
+	}
+
+	input, err := simulator.LoadSimulationInput(flag.Args()...)
+	if err != nil {
+		exitWithError(err)
+	}
+	if *topologyFile != "" {
+		data, err := os.ReadFile(*topologyFile)
+		if err != nil {
+			exitWithError(err)
+		}
+		topology, err := regionmanager.ParseNetworkTopology(data)
+		if err != nil {
+			exitWithError(fmt.Errorf("%s: %w", *topologyFile, err))
+		}
+		input.AddNetworkTopology(topology)
+	}
+	if *synthesizeNodes {
+		input.SynthesizeMissingNodes()
+	}
+
+	profile := simulator.NewDefaultProfile()
+	if *schedulerConfigFile != "" {
+		if profile.PluginConfig, err = simulator.LoadPluginConfig(*schedulerConfigFile, profile.SchedulerName); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	sim, err := simulator.NewSimulator(input, &simulator.SimulatorOptions{Profile: profile})
+	if err != nil {
+		exitWithError(err)
+	}
+	report, err := sim.Run(context.Background())
+	if err != nil {
+		exitWithError(err)
+	}
+
+	var reportData []byte
+	if *output == "json" {
+		reportData, err = report.ToJSON()
+	} else {
+		reportData, err = report.ToYAML()
+	}
+	if err != nil {
+		exitWithError(err)
+	}
+	klog.Flush()
+	os.Stdout.Write(reportData)
+}
+
+func exitWithError(err error) {
+	klog.Flush()
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
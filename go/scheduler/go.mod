@@ -12,10 +12,11 @@ require (
 	k8s.io/klog/v2 v2.9.0
 	// k8s.io/kube-openapi should be the same version as the one used by k8s.io/apiserver.
 	k8s.io/kube-openapi v0.0.0-20211109043538-20434351676c
-	k8s.io/kube-scheduler v0.22.9 // indirect
+	k8s.io/kube-scheduler v0.22.9
 	k8s.io/kubernetes v1.22.9
 	k8s.rainbow-h2020.eu/rainbow/orchestration v0.0.1
 	sigs.k8s.io/controller-runtime v0.10.3
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
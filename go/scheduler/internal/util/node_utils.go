@@ -26,14 +26,25 @@ func GetNodeByName(handle framework.Handle, nodeName string) (*framework.NodeInf
 
 // Returns the hourly cost of the specified node or 0 if none can be found.
 func GetNodeCost(nodeInfo *framework.NodeInfo) float64 {
-	costStr, ok := kubeutil.GetLabel(nodeInfo.Node(), kubeutil.LabelNodeCost)
+	cost, _ := getNodeCostLabel(nodeInfo.Node(), kubeutil.LabelNodeCost)
+	return cost
+}
+
+// Returns the hourly spot price of the specified node or false if the node is not a spot instance.
+func GetNodeSpotCost(nodeInfo *framework.NodeInfo) (float64, bool) {
+	return getNodeCostLabel(nodeInfo.Node(), kubeutil.LabelNodeSpotCost)
+}
+
+// Parses the cost stored in the specified label of the node. Negative or invalid costs are ignored.
+func getNodeCostLabel(node *v1.Node, label string) (float64, bool) {
+	costStr, ok := kubeutil.GetLabel(node, label)
 	if !ok {
-		return 0
+		return 0, false
 	}
-	if cost, err := strconv.ParseFloat(costStr, 64); err != nil {
-		return cost
+	if cost, err := strconv.ParseFloat(costStr, 64); err == nil && cost >= 0 {
+		return cost, true
 	}
-	return 0
+	return 0, false
 }
 
 // IsCloudNode returns true if the node is a cloud node, otherwise false.
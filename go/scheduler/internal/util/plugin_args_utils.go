@@ -0,0 +1,41 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	kubeschedulerconfigv1beta2 "k8s.io/kubernetes/pkg/scheduler/apis/config/v1beta2"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+)
+
+// DecodePluginArgs decodes the args object, which has been passed to the factory of a plugin, into the specified args object.
+//
+// The obj may be nil (the args are left unchanged), a runtime.Unknown (e.g., if the args have been specified using the
+// v1beta1 KubeSchedulerConfiguration, for which the args types are not registered), or an instance of the args type
+// (if the args have been decoded using the scheme), in which case it is copied into args.
+func DecodePluginArgs(pluginName string, obj runtime.Object, args runtime.Object) error {
+	if obj == nil {
+		return nil
+	}
+	if reflect.TypeOf(obj) == reflect.TypeOf(args) {
+		reflect.ValueOf(args).Elem().Set(reflect.ValueOf(obj.DeepCopyObject()).Elem())
+		return nil
+	}
+	if err := frameworkruntime.DecodeInto(obj, args); err != nil {
+		return fmt.Errorf("invalid %s args: %w", pluginName, err)
+	}
+	return nil
+}
+
+// AddPluginArgsToScheme registers the args type of a plugin as the kind <pluginName>Args with the internal and the v1beta2 versions
+// of the KubeSchedulerConfiguration API group and registers the setDefaultsFn as its defaulting function.
+//
+// Since the same type is used for both versions, no conversion functions are needed.
+func AddPluginArgsToScheme(scheme *runtime.Scheme, pluginName string, args runtime.Object, setDefaultsFn func(obj interface{})) {
+	kind := pluginName + "Args"
+	scheme.AddKnownTypeWithName(kubeschedulerconfig.SchemeGroupVersion.WithKind(kind), args)
+	scheme.AddKnownTypeWithName(kubeschedulerconfigv1beta2.SchemeGroupVersion.WithKind(kind), args)
+	scheme.AddTypeDefaultingFunc(args, setDefaultsFn)
+}
@@ -25,3 +25,31 @@ func CalcTotalRequiredResources(pod *v1.Pod) (*framework.Resource, error) {
 	}
 	return required, nil
 }
+
+// CalcContainersRequests calculates the resources requested by a pod with the specified containers and init containers
+// in the same way as the kube-scheduler, i.e., the maximum of the sum of the containers' requests and each init container's requests.
+// If a container does not specify the request for a resource, its limit is used, which is also how the API server defaults the requests.
+func CalcContainersRequests(containers []core.Container, initContainers []core.Container) *framework.Resource {
+	required := &framework.Resource{}
+	for i := range containers {
+		required.Add(getContainerRequests(&containers[i]))
+	}
+	for i := range initContainers {
+		required.SetMaxResource(getContainerRequests(&initContainers[i]))
+	}
+	return required
+}
+
+// Returns the requests of the container, using the limits for all resources that have no request.
+func getContainerRequests(container *core.Container) core.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = make(core.ResourceList)
+	}
+	for name, limit := range container.Resources.Limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = limit
+		}
+	}
+	return requests
+}
@@ -0,0 +1,66 @@
+package pluginservices
+
+import (
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/bandwidthledger"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/configmanager"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PluginServices bundles the services that are shared by the Polaris scheduler plugins.
+//
+// The services are passed to the factories of the plugins that need them, so that all plugins of a scheduler
+// share the same instances, e.g., the bandwidth reserved by the NetworkQoS plugin is tracked in a single BandwidthLedger.
+// For unit tests, the fields can be set to fake implementations, such as regionmanager.FakeRegionManager
+// and servicegraphmanager.FakeServiceGraphManager.
+type PluginServices struct {
+	RegionManager       regionmanager.RegionManager
+	ServiceGraphManager servicegraphmanager.ServiceGraphManager
+	BandwidthLedger     bandwidthledger.BandwidthLedger
+}
+
+// NewPluginServices creates the PluginServices for the cluster configured by the ConfigManager.
+func NewPluginServices(configMgr configmanager.ConfigManager) (*PluginServices, error) {
+	regionMgr, err := regionmanager.NewRegionManager(configMgr)
+	if err != nil {
+		return nil, err
+	}
+	svcGraphMgr, err := servicegraphmanager.NewServiceGraphManager(configMgr)
+	if err != nil {
+		regionMgr.Stop()
+		return nil, err
+	}
+
+	return &PluginServices{
+		RegionManager:       regionMgr,
+		ServiceGraphManager: svcGraphMgr,
+		BandwidthLedger:     bandwidthledger.NewBandwidthLedger(regionMgr.RegionGraph().IsDirected()),
+	}, nil
+}
+
+// NewPluginServicesWithClient creates the PluginServices, which use the specified client for accessing the cluster.
+// This allows running the plugins against an in-memory client, e.g., in a simulation.
+func NewPluginServicesWithClient(cl client.WithWatch) (*PluginServices, error) {
+	regionMgr, err := regionmanager.NewRegionManagerWithClient(cl)
+	if err != nil {
+		return nil, err
+	}
+	svcGraphMgr, err := servicegraphmanager.NewServiceGraphManagerWithClient(cl)
+	if err != nil {
+		regionMgr.Stop()
+		return nil, err
+	}
+
+	return &PluginServices{
+		RegionManager:       regionMgr,
+		ServiceGraphManager: svcGraphMgr,
+		BandwidthLedger:     bandwidthledger.NewBandwidthLedger(regionMgr.RegionGraph().IsDirected()),
+	}, nil
+}
+
+// Stop stops the services that watch the cluster.
+func (me *PluginServices) Stop() {
+	me.RegionManager.Stop()
+	me.ServiceGraphManager.Stop()
+}
@@ -0,0 +1,89 @@
+package atomicdeployment
+
+import (
+	"fmt"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	defaultWaitTimeout = 60 * time.Second
+)
+
+// AtomicDeploymentArgs configures the AtomicDeploymentPlugin.
+type AtomicDeploymentArgs struct {
+	meta.TypeMeta `json:",inline"`
+
+	// The maximum time that the pods of a gang wait in the Permit phase for the gang to be completed, i.e.,
+	// for the minimum number of pods of every ServiceNode to be reserved a node.
+	// If the timeout expires, all waiting pods of the gang are rejected and rescheduled.
+	// Must be greater than zero.
+	//
+	// Default: "60s"
+	WaitTimeout *meta.Duration `json:"waitTimeout,omitempty"`
+}
+
+// Decodes the AtomicDeploymentArgs from the runtime.Object passed to New(), sets the defaults, and validates them.
+func decodeAtomicDeploymentArgs(obj runtime.Object) (*AtomicDeploymentArgs, error) {
+	args := &AtomicDeploymentArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	setDefaultsAtomicDeploymentArgs(args)
+	if err := validateAtomicDeploymentArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// AddArgsToScheme registers the AtomicDeploymentArgs and their defaults with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &AtomicDeploymentArgs{}, func(obj interface{}) {
+		setDefaultsAtomicDeploymentArgs(obj.(*AtomicDeploymentArgs))
+	})
+}
+
+func setDefaultsAtomicDeploymentArgs(args *AtomicDeploymentArgs) {
+	if args.WaitTimeout == nil {
+		args.WaitTimeout = &meta.Duration{Duration: defaultWaitTimeout}
+	}
+}
+
+func validateAtomicDeploymentArgs(args *AtomicDeploymentArgs) error {
+	if args.WaitTimeout.Duration <= 0 {
+		return fmt.Errorf("invalid %s args: waitTimeout must be greater than zero, but is %v", PluginName, args.WaitTimeout.Duration)
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *AtomicDeploymentArgs) DeepCopyInto(out *AtomicDeploymentArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+	if me.WaitTimeout != nil {
+		waitTimeout := *me.WaitTimeout
+		out.WaitTimeout = &waitTimeout
+	}
+}
+
+// DeepCopy creates a deep copy of the AtomicDeploymentArgs.
+func (me *AtomicDeploymentArgs) DeepCopy() *AtomicDeploymentArgs {
+	if me == nil {
+		return nil
+	}
+	out := &AtomicDeploymentArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the AtomicDeploymentArgs as a runtime.Object.
+func (me *AtomicDeploymentArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package atomicdeployment
+
+import (
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDecodeAtomicDeploymentArgs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		obj      runtime.Object
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "nil args", obj: nil, expected: 60 * time.Second},
+		{name: "unknown args", obj: &runtime.Unknown{Raw: []byte(`{"waitTimeout": "2m"}`)}, expected: 2 * time.Minute},
+		{name: "typed args", obj: &AtomicDeploymentArgs{WaitTimeout: &meta.Duration{Duration: 10 * time.Second}}, expected: 10 * time.Second},
+		{name: "zero timeout", obj: &runtime.Unknown{Raw: []byte(`{"waitTimeout": "0s"}`)}, wantErr: true},
+		{name: "invalid timeout", obj: &runtime.Unknown{Raw: []byte(`{"waitTimeout": "soon"}`)}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		args, err := decodeAtomicDeploymentArgs(tc.obj)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if args.WaitTimeout.Duration != tc.expected {
+			t.Errorf("%s: expected waitTimeout %v, but got %v", tc.name, tc.expected, args.WaitTimeout.Duration)
+		}
+	}
+}
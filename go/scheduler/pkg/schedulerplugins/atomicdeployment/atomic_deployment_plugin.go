@@ -2,48 +2,67 @@ package atomicdeployment
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	framework "k8s.io/kubernetes/pkg/scheduler/framework"
 
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
-	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
 	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
 )
 
 const (
 	// PluginName is the name of this scheduler plugin.
 	PluginName = "AtomicDeployment"
-
-	waitMsec = "60000ms"
 )
 
 var (
 	_atomicDeploymentPlugin *AtomicDeploymentPlugin
 
-	_ framework.Plugin       = _atomicDeploymentPlugin
-	_ framework.PermitPlugin = _atomicDeploymentPlugin
+	_ framework.Plugin          = _atomicDeploymentPlugin
+	_ framework.PreFilterPlugin = _atomicDeploymentPlugin
+	_ framework.ReservePlugin   = _atomicDeploymentPlugin
+	_ framework.PermitPlugin    = _atomicDeploymentPlugin
 )
 
-// AtomicDeploymentPlugin is a Permit plugin that ensures that all of an application's pods are permitted at the same time or not at all.
+// AtomicDeploymentPlugin ensures that the pods of an application are scheduled as a gang, i.e.,
+// that they are bound only once at least the minimum number of pods of every ServiceNode have been reserved a node.
+//
+// Gangs are scoped to generations of the ServiceGraph: the pods of all ServiceNodes that have been added or changed in the same
+// generation form a gang. Thus, ServiceNodes that are added to a running ServiceGraph are placed atomically as well.
+//
+// The minimum number of pods of a ServiceNode is configured by its Replicas.GangMinMembers and defaults to Replicas.Min.
+//
+// This plugin hooks into the following phases:
+//   - PreFilter: Reject the pod if the cluster does not have enough free resources for the pods that are missing to complete the gang.
+//   - Permit: Delay the pod until its gang is complete and then allow all waiting pods of the gang.
+//   - Unreserve: Reject the other waiting pods of the gang if a pod of an incomplete gang is rejected, e.g., because the gang's deadline has expired.
 type AtomicDeploymentPlugin struct {
+	// The maximum time that the pods of a gang wait in the Permit phase for the gang to be completed.
 	waitDuration time.Duration
 
 	// The Scheduling framework handle.
 	frameworkHandle framework.Handle
+
+	// The gangs that have pods waiting in the Permit phase, indexed by their gang keys.
+	gangs map[string]*gang
+
+	// Synchronizes access to the gangs map.
+	mutex sync.Mutex
 }
 
 // New creates a new AtomicDeploymentPlugin instance.
 func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	waitDuration, err := time.ParseDuration(waitMsec)
+	args, err := decodeAtomicDeploymentArgs(obj)
 	if err != nil {
 		return nil, err
 	}
 	return &AtomicDeploymentPlugin{
-		waitDuration:    waitDuration,
+		waitDuration:    args.WaitTimeout.Duration,
 		frameworkHandle: handle,
+		gangs:           make(map[string]*gang),
 	}, nil
 }
 
@@ -52,9 +71,61 @@ func (me *AtomicDeploymentPlugin) Name() string {
 	return PluginName
 }
 
-// If this is the initial placement of the ServiceGraph associated with the pod,
-// we delay the pod until all other pods have been reserved a node as well (atomic placement).
-// In all other cases, the pod is permitted immediately.
+// PreFilter rejects the pod if its gang is incomplete and the free resources of the entire cluster
+// are not sufficient for all pods that are missing to complete the gang.
+func (me *AtomicDeploymentPlugin) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod) *framework.Status {
+	svcGraphState, noSvcGraphStatus := util.GetServiceGraphFromCycleStateOrStatus(cycleState)
+	if noSvcGraphStatus != nil {
+		return noSvcGraphStatus
+	}
+
+	gang := getGangID(pod, svcGraphState.ServiceGraphCRD())
+	missingMembers, ok := getMissingGangMembersFromState(gang, svcGraphState)
+	if !ok || len(missingMembers) == 0 {
+		return framework.NewStatus(framework.Success)
+	}
+
+	nodeInfos, err := me.frameworkHandle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	required := calcMissingGangResources(gang.svcGraph, missingMembers)
+	if err := checkGangFits(required, calcFreeResources(nodeInfos)); err != nil {
+		return framework.NewStatus(framework.Unschedulable, err.Error())
+	}
+	return framework.NewStatus(framework.Success)
+}
+
+// PreFilterExtensions returns nil, because this plugin does not need to be notified about added or removed pods.
+func (me *AtomicDeploymentPlugin) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Reserve does nothing, this plugin only needs Unreserve to reject the gang of a rejected pod.
+func (me *AtomicDeploymentPlugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) *framework.Status {
+	return framework.NewStatus(framework.Success)
+}
+
+// Unreserve rejects all waiting pods of the gang of the specified pod, if that gang is still incomplete.
+// This is the case if the pod's deadline has expired while waiting in the Permit phase or if the pod was rejected
+// by another plugin before being permitted.
+//
+// The gang is identified from the pod alone, because the ServiceGraphPlugin removes the ServiceGraphState
+// from the CycleState in the Permit phase.
+func (me *AtomicDeploymentPlugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) {
+	gang, ok := getGangIDOfPod(pod)
+	if !ok {
+		return
+	}
+	if !me.removeGang(gang.key()) {
+		return
+	}
+	me.rejectWaitingPods(gang, fmt.Sprintf("the gang of pod %s.%s has been rejected", pod.Namespace, pod.Name))
+}
+
+// Permit delays the pod until its gang is complete, i.e., until every ServiceNode of the gang has at least
+// the minimum number of assumed or bound pods. The pod that completes the gang allows all waiting pods of the gang.
+// If the gang is not completed before its deadline, all of its waiting pods are rejected.
 //
 // Permit is called before binding a pod (and before prebind plugins). Permit
 // plugins are used to prevent or delay the binding of a Pod. A permit plugin
@@ -68,37 +139,54 @@ func (me *AtomicDeploymentPlugin) Permit(ctx context.Context, cycleState *framew
 		return noSvcGraphStatus, 0
 	}
 
-	placementMap, _ := svcGraphState.PlacementMap()
-	if !placementMap.IsInitialPlacement() {
+	gang := getGangID(pod, svcGraphState.ServiceGraphCRD())
+	if missingMembers, ok := getMissingGangMembersFromState(gang, svcGraphState); ok && len(missingMembers) == 0 {
+		// The pod that completes the gang needs to approve all pods that have been placed in the waiting state.
+		me.removeGang(gang.key())
+		me.frameworkHandle.IterateOverWaitingPods(func(wp framework.WaitingPod) {
+			if gang.isMember(wp.GetPod()) {
+				wp.Allow(PluginName)
+			}
+		})
 		return framework.NewStatus(framework.Success), 0
 	}
 
-	allNodes := svcGraphState.ServiceGraphCRD().Spec.Nodes
-	for i := range allNodes {
-		svcNodeName := allNodes[i].Name
-		placement := placementMap.GetKubernetesNodes(svcNodeName)
-		if len(placement) == 0 {
-			return framework.NewStatus(framework.Wait), me.waitDuration
-		}
+	remaining := time.Until(me.getOrCreateGang(gang.key()).deadline)
+	if remaining <= 0 {
+		// Unreserve is called for this pod, which removes the gang and rejects its waiting pods.
+		return framework.NewStatus(framework.Unschedulable, "the gang has not been completed before its deadline"), 0
 	}
-
-	// The last pod of a ServiceGraph needs to approve all pods that have been placed in the waiting state.
-	me.frameworkHandle.IterateOverWaitingPods(func(wp framework.WaitingPod) {
-		if me.isSameServiceGraph(wp, svcGraphState) {
-			wp.Allow(PluginName)
-		}
-	})
-
-	return framework.NewStatus(framework.Success), 0
+	return framework.NewStatus(framework.Wait), remaining
 }
 
-func (me *AtomicDeploymentPlugin) isSameServiceGraph(wp framework.WaitingPod, currPodSvcGraphState servicegraphmanager.ServiceGraphState) bool {
-	wpPod := wp.GetPod()
-	wpSvcGraph, ok := kubeutil.GetLabel(wpPod, kubeutil.LabelRefServiceGraph)
+// Returns the gang with the specified key or creates a new one, whose deadline is set to waitDuration from now.
+func (me *AtomicDeploymentPlugin) getOrCreateGang(gangKey string) *gang {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	g, ok := me.gangs[gangKey]
 	if !ok {
-		return false
+		g = &gang{deadline: time.Now().Add(me.waitDuration)}
+		me.gangs[gangKey] = g
 	}
+	return g
+}
+
+// Removes the gang with the specified key and returns true if it existed.
+func (me *AtomicDeploymentPlugin) removeGang(gangKey string) bool {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
 
-	currPodSvcGraphCRD := currPodSvcGraphState.ServiceGraphCRD()
-	return wpPod.Namespace == currPodSvcGraphCRD.Namespace && wpSvcGraph == currPodSvcGraphCRD.Name
+	_, ok := me.gangs[gangKey]
+	delete(me.gangs, gangKey)
+	return ok
+}
+
+// Rejects all waiting pods of the gang.
+func (me *AtomicDeploymentPlugin) rejectWaitingPods(gang *gangID, msg string) {
+	me.frameworkHandle.IterateOverWaitingPods(func(wp framework.WaitingPod) {
+		if gang.isMember(wp.GetPod()) {
+			wp.Reject(PluginName, msg)
+		}
+	})
 }
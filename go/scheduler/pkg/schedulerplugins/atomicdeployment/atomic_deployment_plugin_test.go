@@ -0,0 +1,106 @@
+package atomicdeployment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+// A framework.Handle that only supports iterating over the waiting pods.
+type fakeHandle struct {
+	framework.Handle
+	waitingPods []*fakeWaitingPod
+}
+
+func (me *fakeHandle) IterateOverWaitingPods(callback func(framework.WaitingPod)) {
+	for _, wp := range me.waitingPods {
+		callback(wp)
+	}
+}
+
+type fakeWaitingPod struct {
+	framework.WaitingPod
+	pod      *core.Pod
+	allowed  bool
+	rejected bool
+}
+
+func (me *fakeWaitingPod) GetPod() *core.Pod {
+	return me.pod
+}
+
+func (me *fakeWaitingPod) Allow(pluginName string) {
+	me.allowed = true
+}
+
+func (me *fakeWaitingPod) Reject(pluginName, msg string) {
+	me.rejected = true
+}
+
+func TestUnreserveRejectsGangAfterDeadline(t *testing.T) {
+	svcGraphMgr := servicegraphmanager.NewFakeServiceGraphManager()
+	svcGraphState := svcGraphMgr.AddServiceGraph(newTestServiceGraph())
+	handle := &fakeHandle{}
+	plugin := &AtomicDeploymentPlugin{
+		waitDuration:    50 * time.Millisecond,
+		frameworkHandle: handle,
+		gangs:           make(map[string]*gang),
+	}
+
+	newPod := func(name string, svcNode string) *core.Pod {
+		pod := &core.Pod{}
+		pod.Namespace = "test"
+		pod.Name = name
+		pod.Labels = map[string]string{
+			kubeutil.LabelRefServiceGraph:     "app",
+			kubeutil.LabelRefServiceGraphNode: svcNode,
+		}
+		return pod
+	}
+	newCycleState := func() *framework.CycleState {
+		cycleState := framework.NewCycleState()
+		util.WriteServiceGraphToCycleState(cycleState, svcGraphState)
+		return cycleState
+	}
+
+	// Two pods of the incomplete gang enter the Permit phase and have to wait.
+	for _, pod := range []*core.Pod{newPod("collector-0", "collector"), newPod("analyzer-0", "analyzer")} {
+		status, timeout := plugin.Permit(context.Background(), newCycleState(), pod, "node-1")
+		if status.Code() != framework.Wait || timeout <= 0 {
+			t.Fatalf("expected pod %s to wait, but got %v with timeout %v", pod.Name, status.Code(), timeout)
+		}
+		handle.waitingPods = append(handle.waitingPods, &fakeWaitingPod{pod: pod})
+	}
+	otherGangPod := newPod("other-0", "collector")
+	otherGangPod.Labels[kubeutil.LabelRefServiceGraph] = "other-app"
+	handle.waitingPods = append(handle.waitingPods, &fakeWaitingPod{pod: otherGangPod})
+
+	// The deadline of the first pod expires, so the framework calls Unreserve with a CycleState,
+	// from which the ServiceGraphPlugin has already removed the ServiceGraphState.
+	time.Sleep(plugin.waitDuration)
+	expiredPod := handle.waitingPods[0]
+	plugin.Unreserve(context.Background(), framework.NewCycleState(), expiredPod.pod, "node-1")
+
+	if !handle.waitingPods[1].rejected {
+		t.Errorf("expected the other waiting pod of the gang to be rejected")
+	}
+	if handle.waitingPods[2].rejected {
+		t.Errorf("expected the waiting pod of another gang not to be rejected")
+	}
+	if len(plugin.gangs) != 0 {
+		t.Errorf("expected the gang to be removed, but got %d gangs", len(plugin.gangs))
+	}
+
+	// The next attempt of the gang must wait again instead of failing immediately.
+	status, timeout := plugin.Permit(context.Background(), newCycleState(), expiredPod.pod, "node-1")
+	if status.Code() != framework.Wait || timeout <= 0 {
+		t.Errorf("expected the next attempt of the gang to wait, but got %v with timeout %v", status.Code(), timeout)
+	}
+}
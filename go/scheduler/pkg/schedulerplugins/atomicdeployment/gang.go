@@ -0,0 +1,263 @@
+package atomicdeployment
+
+import (
+	"fmt"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	// The default maxSurge of a Deployment's rolling update is 25% of its replicas, i.e., a quarter.
+	defaultDeploymentMaxSurgeDivisor = 4
+)
+
+// Identifies the gang of a pod.
+//
+// A gang consists of the pods of all ServiceNodes of a ServiceGraph that have been added or changed
+// in the same generation of the ServiceGraph. The generation of a pod is indicated by its
+// kubeutil.AnnotationServiceGraphGeneration annotation and the generation of each ServiceNode's pod template
+// is tracked in the ServiceGraph's status.
+// The pods of a changed ServiceNode are replaced by a rolling update, so only the pods of ServiceNodes that have been
+// added in the gang's generation must reach their full minimum, see gangID.getMinMembers().
+//
+// If a pod does not have a generation annotation, its gang consists of the pods of all ServiceNodes of the ServiceGraph.
+type gangID struct {
+	// The namespace of the gang's ServiceGraph.
+	namespace string
+
+	// The name of the gang's ServiceGraph.
+	svcGraphName string
+
+	// The ServiceGraph of the gang.
+	// This is nil if the gangID has been obtained from the pod alone using getGangIDOfPod().
+	svcGraph *fogappsCRDs.ServiceGraph
+
+	// The generation of the ServiceGraph, in which the pod templates of the gang's pods were last changed,
+	// or 0 if the gang consists of all ServiceNodes.
+	generation int64
+}
+
+// A gang that is tracked by the AtomicDeploymentPlugin from the time its first pod enters the Permit phase
+// until the gang is complete or rejected.
+type gang struct {
+	// The time at which the pods of the gang, which are waiting in the Permit phase, are rejected
+	// if the gang has not been completed by then.
+	deadline time.Time
+}
+
+// Creates the ID of the gang of the specified ServiceGraph and generation.
+func newGangID(svcGraph *fogappsCRDs.ServiceGraph, generation int64) *gangID {
+	return &gangID{
+		namespace:    svcGraph.Namespace,
+		svcGraphName: svcGraph.Name,
+		svcGraph:     svcGraph,
+		generation:   generation,
+	}
+}
+
+// Returns the ID of the gang of the specified pod.
+func getGangID(pod *core.Pod, svcGraph *fogappsCRDs.ServiceGraph) *gangID {
+	generation, _ := kubeutil.GetServiceGraphGeneration(pod)
+	return newGangID(svcGraph, generation)
+}
+
+// Returns the ID of the gang of the specified pod without its ServiceGraph, i.e., the returned ID only supports key() and isMember().
+// This is needed in phases, where the ServiceGraphState is no longer available in the CycleState.
+// If the pod does not belong to a ServiceGraph, false is returned.
+func getGangIDOfPod(pod *core.Pod) (*gangID, bool) {
+	svcGraphName, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph)
+	if !ok {
+		return nil, false
+	}
+	generation, _ := kubeutil.GetServiceGraphGeneration(pod)
+	return &gangID{
+		namespace:    kubeutil.GetNamespace(pod),
+		svcGraphName: svcGraphName,
+		generation:   generation,
+	}, true
+}
+
+// Returns the key that identifies the gang in the gangs map of the AtomicDeploymentPlugin.
+func (me *gangID) key() string {
+	return fmt.Sprintf("%s.%s.%v", me.namespace, me.svcGraphName, me.generation)
+}
+
+// Returns true if the specified pod is a member of this gang.
+func (me *gangID) isMember(pod *core.Pod) bool {
+	svcGraphName, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph)
+	if !ok || kubeutil.GetNamespace(pod) != me.namespace || svcGraphName != me.svcGraphName {
+		return false
+	}
+	generation, _ := kubeutil.GetServiceGraphGeneration(pod)
+	return generation == me.generation
+}
+
+// Returns the ServiceNodes, whose pods are members of this gang.
+//
+// If the status of the ServiceGraph does not describe the gang's generation yet, false is returned, because the members are not known yet.
+func (me *gangID) getMemberNodes() ([]*fogappsCRDs.ServiceGraphNode, bool) {
+	if me.generation > me.svcGraph.Status.ObservedGeneration {
+		return nil, false
+	}
+
+	memberNodes := make([]*fogappsCRDs.ServiceGraphNode, 0, len(me.svcGraph.Spec.Nodes))
+	for i := range me.svcGraph.Spec.Nodes {
+		svcNode := &me.svcGraph.Spec.Nodes[i]
+		if svcNode.NodeType == fogappsCRDs.UserNode {
+			continue
+		}
+		if me.generation != 0 {
+			nodeStatus, ok := me.svcGraph.Status.NodeStates[svcNode.Name]
+			if !ok || nodeStatus == nil || nodeStatus.PodTemplateGeneration != me.generation {
+				continue
+			}
+		}
+		memberNodes = append(memberNodes, svcNode)
+	}
+	return memberNodes, true
+}
+
+// Returns the minimum number of pods of the ServiceGraphNode that must be reserved a node before the gang may be bound.
+//
+// The StatefulSet of a Stateful ServiceGraphNode creates its pods one after another and only creates the next pod
+// once the previous one is running and ready. Thus, at most one of its pods can ever wait in the Permit phase
+// and the minimum is capped at 1 for such nodes.
+func getGangMinMembers(svcNode *fogappsCRDs.ServiceGraphNode) int {
+	minMembers := int(svcNode.Replicas.Min)
+	if svcNode.Replicas.GangMinMembers != nil {
+		minMembers = int(*svcNode.Replicas.GangMinMembers)
+	}
+	if svcNode.Replicas.SetType == fogappsCRDs.StatefulReplicaSet && minMembers > 1 {
+		minMembers = 1
+	}
+	return minMembers
+}
+
+// Returns the minimum number of pods of the member ServiceGraphNode that must be reserved a node before this gang may be bound.
+//
+// If the ServiceGraphNode has been changed rather than added in the gang's generation, its Deployment or StatefulSet
+// replaces the existing pods in a rolling update, which creates only a few new pods at a time.
+// In this case the minimum is capped at the number of pods that are created at once.
+func (me *gangID) getMinMembers(svcNode *fogappsCRDs.ServiceGraphNode) int {
+	minMembers := getGangMinMembers(svcNode)
+	if me.generation == 0 {
+		return minMembers
+	}
+	if nodeStatus := me.svcGraph.Status.NodeStates[svcNode.Name]; nodeStatus != nil && nodeStatus.CreationGeneration == me.generation {
+		return minMembers
+	}
+	if batchSize := getRollingUpdateBatchSize(svcNode); minMembers > batchSize {
+		minMembers = batchSize
+	}
+	return minMembers
+}
+
+// Returns the number of new pods that the Deployment or StatefulSet of the ServiceGraphNode creates at once during a rolling update.
+//
+// The orchestrator does not configure an update strategy, so a StatefulSet replaces one pod at a time and a Deployment
+// surges by 25% of its replicas, rounded up. Since the replicas of a Deployment may be scaled beyond Replicas.Min,
+// the batch size computed from Replicas.Min is a lower bound.
+func getRollingUpdateBatchSize(svcNode *fogappsCRDs.ServiceGraphNode) int {
+	if svcNode.Replicas.SetType == fogappsCRDs.StatefulReplicaSet {
+		return 1
+	}
+	return int(svcNode.Replicas.Min+defaultDeploymentMaxSurgeDivisor-1) / defaultDeploymentMaxSurgeDivisor
+}
+
+// Returns the number of pods that are still missing for each ServiceNode of the gang to reach its minimum members.
+// countPods must return the number of assumed or bound pods of the gang for a ServiceNode.
+//
+// ServiceNodes that already have enough assumed or bound pods are not contained in the returned map.
+// If the returned map is empty, the gang is complete.
+// If the members of the gang are not known yet, false is returned.
+func getMissingGangMembers(gang *gangID, countPods func(svcNode string) int) (map[string]int, bool) {
+	memberNodes, ok := gang.getMemberNodes()
+	if !ok {
+		return nil, false
+	}
+
+	missingMembers := make(map[string]int)
+	for _, svcNode := range memberNodes {
+		if missing := gang.getMinMembers(svcNode) - countPods(svcNode.Name); missing > 0 {
+			missingMembers[svcNode.Name] = missing
+		}
+	}
+	return missingMembers, true
+}
+
+// Returns the number of pods that are still missing for each ServiceNode of the gang to reach its minimum members,
+// based on the assumed and bound pods in the ServiceGraphState.
+// If the members of the gang are not known yet, false is returned.
+func getMissingGangMembersFromState(gang *gangID, svcGraphState servicegraphmanager.ServiceGraphState) (map[string]int, bool) {
+	if gang.generation != 0 {
+		return getMissingGangMembers(gang, func(svcNode string) int {
+			return svcGraphState.CountPodsOfGeneration(svcNode, gang.generation)
+		})
+	}
+
+	placementMap, _ := svcGraphState.PlacementMap()
+	return getMissingGangMembers(gang, func(svcNode string) int {
+		podsCount := 0
+		for _, podCounts := range placementMap.GetAllPodCounts(svcNode) {
+			podsCount += podCounts.Total()
+		}
+		return podsCount
+	})
+}
+
+// Calculates the resources that are required by the pods, which are still missing to complete the gang.
+func calcMissingGangResources(svcGraph *fogappsCRDs.ServiceGraph, missingMembers map[string]int) *framework.Resource {
+	required := &framework.Resource{}
+	for i := range svcGraph.Spec.Nodes {
+		svcNode := &svcGraph.Spec.Nodes[i]
+		missing, ok := missingMembers[svcNode.Name]
+		if !ok {
+			continue
+		}
+
+		podRequests := util.CalcContainersRequests(svcNode.Containers, svcNode.InitContainers)
+		required.MilliCPU += podRequests.MilliCPU * int64(missing)
+		required.Memory += podRequests.Memory * int64(missing)
+		required.AllowedPodNumber += missing
+	}
+	return required
+}
+
+// Sums up the resources that are still available on the specified nodes.
+func calcFreeResources(nodeInfos []*framework.NodeInfo) *framework.Resource {
+	free := &framework.Resource{}
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Node() == nil {
+			continue
+		}
+		free.MilliCPU += nodeInfo.Allocatable.MilliCPU - nodeInfo.Requested.MilliCPU
+		free.Memory += nodeInfo.Allocatable.Memory - nodeInfo.Requested.Memory
+		free.AllowedPodNumber += nodeInfo.Allocatable.AllowedPodNumber - len(nodeInfo.Pods)
+	}
+	return free
+}
+
+// Checks if the free resources are sufficient for the required resources and returns an error that describes
+// the insufficient resources otherwise.
+//
+// This check considers the resources of the entire cluster as a single pool, so it can only detect gangs that
+// can never fit, but not guarantee that a gang fits.
+func checkGangFits(required *framework.Resource, free *framework.Resource) error {
+	if required.MilliCPU > free.MilliCPU {
+		return fmt.Errorf("the gang requires %dm CPU, but only %dm are available in the cluster", required.MilliCPU, free.MilliCPU)
+	}
+	if required.Memory > free.Memory {
+		return fmt.Errorf("the gang requires %d bytes of memory, but only %d bytes are available in the cluster", required.Memory, free.Memory)
+	}
+	if required.AllowedPodNumber > free.AllowedPodNumber {
+		return fmt.Errorf("the gang requires %d pods, but only %d pods can be added to the cluster", required.AllowedPodNumber, free.AllowedPodNumber)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package atomicdeployment
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+)
+
+func newTestServiceGraph() *fogappsCRDs.ServiceGraph {
+	gangMinMembers := int32(1)
+	container := core.Container{
+		Name: "main",
+		Resources: core.ResourceRequirements{
+			Limits: core.ResourceList{
+				core.ResourceCPU:    resource.MustParse("500m"),
+				core.ResourceMemory: resource.MustParse("1Ki"),
+			},
+		},
+	}
+
+	svcGraph := &fogappsCRDs.ServiceGraph{}
+	svcGraph.Namespace = "test"
+	svcGraph.Name = "app"
+	svcGraph.Spec.Nodes = []fogappsCRDs.ServiceGraphNode{
+		{Name: "user", NodeType: fogappsCRDs.UserNode},
+		{Name: "collector", NodeType: fogappsCRDs.ServiceNode, Containers: []core.Container{container}, Replicas: fogappsCRDs.ReplicasConfig{Min: 3}},
+		{Name: "analyzer", NodeType: fogappsCRDs.ServiceNode, Containers: []core.Container{container}, Replicas: fogappsCRDs.ReplicasConfig{Min: 2, GangMinMembers: &gangMinMembers}},
+	}
+	return svcGraph
+}
+
+func TestGetMissingGangMembers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		generation int64
+		podCounts  map[string]int
+		expected   map[string]int
+	}{
+		{
+			name:     "no pods",
+			expected: map[string]int{"collector": 3, "analyzer": 1},
+		},
+		{
+			name:      "incomplete gang",
+			podCounts: map[string]int{"collector": 2, "analyzer": 1},
+			expected:  map[string]int{"collector": 1},
+		},
+		{
+			name:      "complete gang",
+			podCounts: map[string]int{"collector": 3, "analyzer": 1},
+			expected:  map[string]int{},
+		},
+		{
+			name:       "gang of the generation, in which the analyzer was added",
+			generation: 2,
+			expected:   map[string]int{"analyzer": 1},
+		},
+		{
+			name:       "gang of a generation without ServiceNodes",
+			generation: 3,
+			expected:   map[string]int{},
+		},
+		{
+			name:       "gang of the generation, in which the collector was changed, needs only one rolling update batch",
+			generation: 4,
+			expected:   map[string]int{"collector": 1},
+		},
+		{
+			name:       "gang of the generation, in which the collector was changed, with a complete rolling update batch",
+			generation: 4,
+			podCounts:  map[string]int{"collector": 1},
+			expected:   map[string]int{},
+		},
+	}
+
+	svcGraph := newTestServiceGraph()
+	svcGraph.Generation = 4
+	svcGraph.Status.ObservedGeneration = 4
+	svcGraph.Status.NodeStates = map[string]*fogappsCRDs.ServiceGraphNodeStatus{
+		"collector": {CreationGeneration: 1, PodTemplateGeneration: 4},
+		"analyzer":  {CreationGeneration: 2, PodTemplateGeneration: 2},
+	}
+	for _, tc := range testCases {
+		gang := newGangID(svcGraph, tc.generation)
+		missingMembers, ok := getMissingGangMembers(gang, func(svcNode string) int { return tc.podCounts[svcNode] })
+		if !ok {
+			t.Errorf("%s: expected the gang members to be known", tc.name)
+			continue
+		}
+		if len(missingMembers) != len(tc.expected) {
+			t.Errorf("%s: expected %v, but got %v", tc.name, tc.expected, missingMembers)
+			continue
+		}
+		for svcNode, expected := range tc.expected {
+			if missingMembers[svcNode] != expected {
+				t.Errorf("%s: expected %v, but got %v", tc.name, tc.expected, missingMembers)
+				break
+			}
+		}
+	}
+}
+
+func TestGetGangMinMembers(t *testing.T) {
+	int32Ptr := func(value int32) *int32 { return &value }
+
+	testCases := []struct {
+		name     string
+		replicas fogappsCRDs.ReplicasConfig
+		expected int
+	}{
+		{name: "Simple node", replicas: fogappsCRDs.ReplicasConfig{Min: 3, SetType: fogappsCRDs.SimpleReplicaSet}, expected: 3},
+		{name: "Simple node with gangMinMembers", replicas: fogappsCRDs.ReplicasConfig{Min: 3, GangMinMembers: int32Ptr(2)}, expected: 2},
+		{name: "Stateful node", replicas: fogappsCRDs.ReplicasConfig{Min: 3, SetType: fogappsCRDs.StatefulReplicaSet}, expected: 1},
+		{name: "Stateful node with gangMinMembers", replicas: fogappsCRDs.ReplicasConfig{Min: 3, GangMinMembers: int32Ptr(2), SetType: fogappsCRDs.StatefulReplicaSet}, expected: 1},
+		{name: "Stateful node without replicas", replicas: fogappsCRDs.ReplicasConfig{Min: 0, SetType: fogappsCRDs.StatefulReplicaSet}, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		svcNode := &fogappsCRDs.ServiceGraphNode{Name: "node", NodeType: fogappsCRDs.ServiceNode, Replicas: tc.replicas}
+		if actual := getGangMinMembers(svcNode); actual != tc.expected {
+			t.Errorf("%s: expected %d, but got %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestGetRollingUpdateBatchSize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		replicas fogappsCRDs.ReplicasConfig
+		expected int
+	}{
+		{name: "Deployment with 1 replica", replicas: fogappsCRDs.ReplicasConfig{Min: 1}, expected: 1},
+		{name: "Deployment with 4 replicas", replicas: fogappsCRDs.ReplicasConfig{Min: 4}, expected: 1},
+		{name: "Deployment with 5 replicas", replicas: fogappsCRDs.ReplicasConfig{Min: 5}, expected: 2},
+		{name: "Deployment with 10 replicas", replicas: fogappsCRDs.ReplicasConfig{Min: 10}, expected: 3},
+		{name: "StatefulSet with 10 replicas", replicas: fogappsCRDs.ReplicasConfig{Min: 10, SetType: fogappsCRDs.StatefulReplicaSet}, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		svcNode := &fogappsCRDs.ServiceGraphNode{Name: "node", NodeType: fogappsCRDs.ServiceNode, Replicas: tc.replicas}
+		if actual := getRollingUpdateBatchSize(svcNode); actual != tc.expected {
+			t.Errorf("%s: expected %d, but got %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestGangOfChangedNodeWithRollingUpdate(t *testing.T) {
+	svcGraph := newTestServiceGraph()
+	svcGraph.Spec.Nodes[1].Replicas.Min = 10
+	svcGraph.Generation = 2
+	svcGraph.Status.ObservedGeneration = 2
+	svcGraph.Status.NodeStates = map[string]*fogappsCRDs.ServiceGraphNodeStatus{
+		// The collector was changed in generation 2, so its Deployment surges by 3 new pods.
+		"collector": {CreationGeneration: 1, PodTemplateGeneration: 2},
+		// The analyzer was added before the orchestrator started tracking generations and has not changed since.
+		"analyzer": {},
+	}
+
+	gang := newGangID(svcGraph, 2)
+	missingMembers, ok := getMissingGangMembers(gang, func(svcNode string) int { return 0 })
+	if !ok {
+		t.Fatalf("expected the gang members to be known")
+	}
+	if len(missingMembers) != 1 || missingMembers["collector"] != 3 {
+		t.Errorf("expected 3 missing collector pods, but got %v", missingMembers)
+	}
+}
+
+func TestGangWithStatefulNode(t *testing.T) {
+	svcGraph := newTestServiceGraph()
+	svcGraph.Spec.Nodes[1].Replicas.SetType = fogappsCRDs.StatefulReplicaSet
+
+	// Only the first pod of the collector's StatefulSet is created before the gang is bound.
+	gang := newGangID(svcGraph, 0)
+	missingMembers, ok := getMissingGangMembers(gang, func(svcNode string) int {
+		if svcNode == "collector" {
+			return 1
+		}
+		return 0
+	})
+	if !ok {
+		t.Fatalf("expected the gang members to be known")
+	}
+	if len(missingMembers) != 1 || missingMembers["analyzer"] != 1 {
+		t.Errorf("expected only the analyzer to be missing, but got %v", missingMembers)
+	}
+}
+
+func TestGangMembersOfUnobservedGeneration(t *testing.T) {
+	svcGraph := newTestServiceGraph()
+	svcGraph.Generation = 2
+	svcGraph.Status.ObservedGeneration = 1
+
+	gang := newGangID(svcGraph, 2)
+	if _, ok := getMissingGangMembers(gang, func(svcNode string) int { return 0 }); ok {
+		t.Errorf("expected the members of a generation that has not been observed by the controller to be unknown")
+	}
+}
+
+func TestGangMembership(t *testing.T) {
+	svcGraph := newTestServiceGraph()
+	gang := newGangID(svcGraph, 2)
+
+	newPod := func(namespace string, svcGraphName string, generation string) *core.Pod {
+		pod := &core.Pod{}
+		pod.Namespace = namespace
+		pod.Labels = map[string]string{kubeutil.LabelRefServiceGraph: svcGraphName}
+		if generation != "" {
+			pod.Annotations = map[string]string{kubeutil.AnnotationServiceGraphGeneration: generation}
+		}
+		return pod
+	}
+
+	testCases := []struct {
+		name     string
+		pod      *core.Pod
+		expected bool
+	}{
+		{name: "same generation", pod: newPod("test", "app", "2"), expected: true},
+		{name: "other generation", pod: newPod("test", "app", "1"), expected: false},
+		{name: "no generation", pod: newPod("test", "app", ""), expected: false},
+		{name: "other ServiceGraph", pod: newPod("test", "other-app", "2"), expected: false},
+		{name: "other namespace", pod: newPod("other", "app", "2"), expected: false},
+	}
+
+	for _, tc := range testCases {
+		if actual := gang.isMember(tc.pod); actual != tc.expected {
+			t.Errorf("%s: expected %v, but got %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestCheckGangFits(t *testing.T) {
+	svcGraph := newTestServiceGraph()
+	required := calcMissingGangResources(svcGraph, map[string]int{"collector": 2, "analyzer": 1})
+	if required.MilliCPU != 1500 || required.Memory != 3*1024 || required.AllowedPodNumber != 3 {
+		t.Fatalf("unexpected required resources: %+v", required)
+	}
+
+	testCases := []struct {
+		name    string
+		free    framework.Resource
+		wantErr bool
+	}{
+		{name: "sufficient resources", free: framework.Resource{MilliCPU: 1500, Memory: 4096, AllowedPodNumber: 3}},
+		{name: "insufficient CPU", free: framework.Resource{MilliCPU: 1000, Memory: 4096, AllowedPodNumber: 3}, wantErr: true},
+		{name: "insufficient memory", free: framework.Resource{MilliCPU: 2000, Memory: 2048, AllowedPodNumber: 3}, wantErr: true},
+		{name: "insufficient pods", free: framework.Resource{MilliCPU: 2000, Memory: 4096, AllowedPodNumber: 2}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		free := tc.free
+		err := checkGangFits(required, &free)
+		if tc.wantErr && err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+		if !tc.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+		}
+	}
+}
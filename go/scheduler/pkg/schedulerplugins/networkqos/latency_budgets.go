@@ -0,0 +1,199 @@
+package networkqos
+
+import (
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+)
+
+// Stores information about a LatencyBudget, whose chain contains the ServiceGraphNode of the current pod.
+type latencyBudgetInfo struct {
+	budget *fogappsCRDs.LatencyBudget
+
+	// The sum of the packet delays of the chain's hops that do not involve the pod's ServiceGraphNode
+	// and whose ServiceGraphNodes have both been placed already.
+	placedHopsDelayMsec int64
+
+	// True if there is no compliant network path for one of the hops, whose ServiceGraphNodes have both been placed already.
+	// In this case the budget cannot be met by any candidate node.
+	hasUnreachablePlacedHop bool
+
+	// The service links of the chain's hops that connect the pod's ServiceGraphNode with an already placed ServiceGraphNode.
+	// The delays of these hops depend on the candidate node.
+	candidateHops []*serviceLinkInfo
+}
+
+// Finds the LatencyBudgets of the ServiceGraph, whose chains contain the podSvcNode, and computes
+// the delays of their hops that do not depend on the candidate node.
+//
+// Hops, whose ServiceGraphNodes have not all been placed yet, do not count towards the budget, because their delay
+// is not known yet. They are checked when the pods of the missing ServiceGraphNodes are scheduled.
+// Hops, whose ServiceGraphNodes have both been placed, but are not connected by a compliant network path, violate the budget.
+func (me *NetworkQosPlugin) getLatencyBudgets(
+	svcGraphState servicegraphmanager.ServiceGraphState,
+	podSvcNode servicegraph.Node,
+	svcLinks []*serviceLinkInfo,
+	pathCache regionmanager.RegionPathCache,
+) ([]*latencyBudgetInfo, error) {
+	budgets := svcGraphState.ServiceGraphCRD().Spec.LatencyBudgets
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+
+	placementMap, err := svcGraphState.PlacementMap()
+	if err != nil {
+		return nil, err
+	}
+	svcGraph := svcGraphState.ServiceGraph()
+	podSvcNodeName := podSvcNode.Label()
+	budgetInfos := make([]*latencyBudgetInfo, 0)
+
+	for i := range budgets {
+		budget := &budgets[i]
+		if !me.chainContains(budget.Chain, podSvcNodeName) {
+			continue
+		}
+
+		budgetInfo := &latencyBudgetInfo{
+			budget:        budget,
+			candidateHops: make([]*serviceLinkInfo, 0),
+		}
+		for hop := 0; hop < len(budget.Chain)-1; hop++ {
+			from := budget.Chain[hop]
+			to := budget.Chain[hop+1]
+
+			switch podSvcNodeName {
+			case from:
+				if svcLink := me.findServiceLinkToPlacedPeer(svcLinks, to); svcLink != nil {
+					budgetInfo.candidateHops = append(budgetInfo.candidateHops, svcLink)
+				}
+			case to:
+				if svcLink := me.findServiceLinkToPlacedPeer(svcLinks, from); svcLink != nil {
+					budgetInfo.candidateHops = append(budgetInfo.candidateHops, svcLink)
+				}
+			default:
+				delayMsec, reachable := me.getPlacedHopDelayMsec(svcGraph, placementMap, pathCache, from, to)
+				budgetInfo.placedHopsDelayMsec += delayMsec
+				budgetInfo.hasUnreachablePlacedHop = budgetInfo.hasUnreachablePlacedHop || !reachable
+			}
+		}
+		budgetInfos = append(budgetInfos, budgetInfo)
+	}
+
+	return budgetInfos, nil
+}
+
+// Checks if the candidate node meets all LatencyBudgets, given the shortest paths for the service links.
+//
+// Returns the highest cumulative delay among all budgets' chains and nil, if all budgets are met,
+// or the first violated LatencyBudget.
+func (me *NetworkQosPlugin) checkLatencyBudgets(
+	budgets []*latencyBudgetInfo,
+	pathsBySvcLink map[*serviceLinkInfo]*regiongraph.PathQoS,
+) (int64, *fogappsCRDs.LatencyBudget) {
+	var highestDelayMsec int64
+
+	for _, budgetInfo := range budgets {
+		if budgetInfo.hasUnreachablePlacedHop {
+			return 0, budgetInfo.budget
+		}
+		delayMsec := budgetInfo.placedHopsDelayMsec
+		for _, hop := range budgetInfo.candidateHops {
+			if path, ok := pathsBySvcLink[hop]; ok {
+				delayMsec += path.TotalPacketDelayMsec
+			}
+		}
+
+		if delayMsec > int64(budgetInfo.budget.MaxPacketDelayMsec) {
+			return 0, budgetInfo.budget
+		}
+		if delayMsec > highestDelayMsec {
+			highestDelayMsec = delayMsec
+		}
+	}
+
+	return highestDelayMsec, nil
+}
+
+// Returns the packet delay of the shortest path between the K8s nodes of two already placed ServiceGraphNodes and true or
+// 0 and true, if one of them has not been placed yet.
+// If both have been placed, but there is no compliant path between any of their K8s nodes, false is returned.
+//
+// If the ServiceGraphNodes are placed on multiple K8s nodes, we use the lowest delay, because
+// requests can be routed to the closest replica.
+func (me *NetworkQosPlugin) getPlacedHopDelayMsec(
+	svcGraph servicegraph.ServiceGraph,
+	placementMap serviceplacement.ServiceGraphPlacementMap,
+	pathCache regionmanager.RegionPathCache,
+	fromSvcNodeName, toSvcNodeName string,
+) (int64, bool) {
+	fromSvcNode := svcGraph.NodeByLabel(fromSvcNodeName)
+	toSvcNode := svcGraph.NodeByLabel(toSvcNodeName)
+	if fromSvcNode == nil || toSvcNode == nil {
+		return 0, true
+	}
+
+	region := pathCache.RegionGraph()
+	fromK8sNodes := me.getPlacedK8sNodes(fromSvcNode, placementMap, region)
+	toK8sNodes := me.getPlacedK8sNodes(toSvcNode, placementMap, region)
+	if len(fromK8sNodes) == 0 || len(toK8sNodes) == 0 {
+		return 0, true
+	}
+	linkFilter := newQosLinkFilter(me.getServiceLinkBetween(svcGraph, fromSvcNode, toSvcNode), me.trustModel)
+
+	var lowestDelay *int64
+	for _, fromK8sNode := range fromK8sNodes {
+		for _, toK8sNode := range toK8sNodes {
+			path := pathCache.ShortestPath(fromK8sNode, toK8sNode, linkFilter)
+			if path != nil && (lowestDelay == nil || path.TotalPacketDelayMsec < *lowestDelay) {
+				lowestDelay = &path.TotalPacketDelayMsec
+			}
+		}
+	}
+
+	if lowestDelay == nil {
+		return 0, false
+	}
+	return *lowestDelay, true
+}
+
+// Returns the ServiceLink between the two ServiceGraphNodes, regardless of its direction,
+// or nil if they are not connected by a ServiceLink.
+func (me *NetworkQosPlugin) getServiceLinkBetween(svcGraph servicegraph.ServiceGraph, nodeA, nodeB servicegraph.Node) *fogappsCRDs.ServiceLink {
+	if edge := svcGraph.Graph().Edge(nodeA.ID(), nodeB.ID()); edge != nil {
+		return edge.(servicegraph.Edge).ServiceLink()
+	}
+	if edge := svcGraph.Graph().Edge(nodeB.ID(), nodeA.ID()); edge != nil {
+		return edge.(servicegraph.Edge).ServiceLink()
+	}
+	return nil
+}
+
+// Finds the service link, whose peer is the ServiceGraphNode with the specified name and has already been placed.
+func (me *NetworkQosPlugin) findServiceLinkToPlacedPeer(svcLinks []*serviceLinkInfo, peerSvcNodeName string) *serviceLinkInfo {
+	for _, svcLink := range svcLinks {
+		if len(svcLink.k8sPeerNodes) == 0 {
+			continue
+		}
+		peer := svcLink.link.From()
+		if svcLink.outgoing {
+			peer = svcLink.link.To()
+		}
+		if peer.(servicegraph.Node).Label() == peerSvcNodeName {
+			return svcLink
+		}
+	}
+	return nil
+}
+
+func (me *NetworkQosPlugin) chainContains(chain []string, svcNodeName string) bool {
+	for _, name := range chain {
+		if name == svcNodeName {
+			return true
+		}
+	}
+	return false
+}
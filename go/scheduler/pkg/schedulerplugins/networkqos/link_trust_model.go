@@ -0,0 +1,26 @@
+package networkqos
+
+import (
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+// LinkTrustModel decides if a network link may carry the traffic of a ServiceLink with specific LinkTrustRequirements.
+//
+// The NetworkQosPlugin only considers paths, whose network links are all trusted according to the LinkTrustModel.
+// Thus, a path that crosses a single untrusted network link or administrative domain is rejected.
+type LinkTrustModel interface {
+	// RequirementsKey returns a key that identifies the requirements within this LinkTrustModel.
+	// Two requirements with the same key must trust the same network links.
+	RequirementsKey(requirements *fogappsCRDs.LinkTrustRequirements) string
+
+	// IsTrusted returns true if the network link meets the requirements.
+	// If requirements is nil, every network link must be trusted.
+	IsTrusted(link regiongraph.Edge, requirements *fogappsCRDs.LinkTrustRequirements) bool
+}
+
+// NewDefaultLinkTrustModel creates a LinkTrustModel that matches the LinkTrustRequirements
+// against the trust attributes of the NetworkLinks.
+func NewDefaultLinkTrustModel() LinkTrustModel {
+	return newDefaultLinkTrustModelImpl()
+}
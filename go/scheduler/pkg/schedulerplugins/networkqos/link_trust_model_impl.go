@@ -0,0 +1,89 @@
+package networkqos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	clusterCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+var (
+	_defaultLinkTrustModelImpl *defaultLinkTrustModelImpl
+
+	_ LinkTrustModel = _defaultLinkTrustModelImpl
+)
+
+// Default implementation of the LinkTrustModel.
+//
+// A network link without trust information is considered to be unencrypted, public, unattested,
+// and not to belong to any administrative domain.
+type defaultLinkTrustModelImpl struct {
+	// Used for network links without trust information.
+	untrustedLink clusterCRDs.NetworkLinkTrust
+}
+
+func newDefaultLinkTrustModelImpl() *defaultLinkTrustModelImpl {
+	return &defaultLinkTrustModelImpl{
+		untrustedLink: clusterCRDs.NetworkLinkTrust{
+			Exposure: clusterCRDs.PublicNetworkLink,
+		},
+	}
+}
+
+func (me *defaultLinkTrustModelImpl) RequirementsKey(requirements *fogappsCRDs.LinkTrustRequirements) string {
+	if requirements == nil {
+		return "trust:none"
+	}
+
+	domains := make([]string, len(requirements.AdministrativeDomains))
+	copy(domains, requirements.AdministrativeDomains)
+	sort.Strings(domains)
+
+	return fmt.Sprintf(
+		"trust:%t/%t/%t/%s",
+		requirements.Encrypted,
+		requirements.Private,
+		requirements.Attested,
+		strings.Join(domains, ","),
+	)
+}
+
+func (me *defaultLinkTrustModelImpl) IsTrusted(link regiongraph.Edge, requirements *fogappsCRDs.LinkTrustRequirements) bool {
+	if requirements == nil {
+		return true
+	}
+
+	linkTrust := link.NetworkLinkTrust()
+	if linkTrust == nil {
+		linkTrust = &me.untrustedLink
+	}
+
+	if requirements.Encrypted && !linkTrust.Encrypted {
+		return false
+	}
+	if requirements.Private && !linkTrust.IsPrivate() {
+		return false
+	}
+	if requirements.Attested && !linkTrust.Attested {
+		return false
+	}
+	if len(requirements.AdministrativeDomains) > 0 && !me.containsDomain(requirements.AdministrativeDomains, linkTrust.AdministrativeDomain) {
+		return false
+	}
+	return true
+}
+
+func (me *defaultLinkTrustModelImpl) containsDomain(domains []string, domain string) bool {
+	if domain == "" {
+		return false
+	}
+	for _, allowedDomain := range domains {
+		if allowedDomain == domain {
+			return true
+		}
+	}
+	return false
+}
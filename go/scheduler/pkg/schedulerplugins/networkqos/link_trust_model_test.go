@@ -0,0 +1,74 @@
+package networkqos
+
+import (
+	"testing"
+
+	clusterCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+)
+
+func newTestEdge(trust *clusterCRDs.NetworkLinkTrust) regiongraph.Edge {
+	region := regiongraph.NewRegionGraph()
+	nodeA := region.NewNode("node-a")
+	region.AddNode(nodeA)
+	nodeB := region.NewNode("node-b")
+	region.AddNode(nodeB)
+	return region.NewEdge(nodeA, nodeB, &clusterCRDs.NetworkLinkQoS{}, trust)
+}
+
+func TestDefaultLinkTrustModel(t *testing.T) {
+	trustedLink := &clusterCRDs.NetworkLinkTrust{
+		Encrypted:            true,
+		Exposure:             clusterCRDs.PrivateNetworkLink,
+		Attested:             true,
+		AdministrativeDomain: "operator-a",
+	}
+	publicLink := &clusterCRDs.NetworkLinkTrust{
+		Encrypted:            true,
+		Exposure:             clusterCRDs.PublicNetworkLink,
+		AdministrativeDomain: "operator-b",
+	}
+
+	testCases := []struct {
+		name         string
+		linkTrust    *clusterCRDs.NetworkLinkTrust
+		requirements *fogappsCRDs.LinkTrustRequirements
+		expected     bool
+	}{
+		{name: "no requirements", linkTrust: nil, requirements: nil, expected: true},
+		{name: "empty requirements", linkTrust: nil, requirements: &fogappsCRDs.LinkTrustRequirements{}, expected: true},
+		{name: "encryption without trust info", linkTrust: nil, requirements: &fogappsCRDs.LinkTrustRequirements{Encrypted: true}, expected: false},
+		{name: "all attributes met", linkTrust: trustedLink, requirements: &fogappsCRDs.LinkTrustRequirements{
+			Encrypted:             true,
+			Private:               true,
+			Attested:              true,
+			AdministrativeDomains: []string{"operator-b", "operator-a"},
+		}, expected: true},
+		{name: "private required", linkTrust: publicLink, requirements: &fogappsCRDs.LinkTrustRequirements{Private: true}, expected: false},
+		{name: "attestation required", linkTrust: publicLink, requirements: &fogappsCRDs.LinkTrustRequirements{Attested: true}, expected: false},
+		{name: "untrusted domain", linkTrust: publicLink, requirements: &fogappsCRDs.LinkTrustRequirements{AdministrativeDomains: []string{"operator-a"}}, expected: false},
+		{name: "no domain", linkTrust: &clusterCRDs.NetworkLinkTrust{}, requirements: &fogappsCRDs.LinkTrustRequirements{AdministrativeDomains: []string{"operator-a"}}, expected: false},
+	}
+
+	trustModel := NewDefaultLinkTrustModel()
+	for _, tc := range testCases {
+		if actual := trustModel.IsTrusted(newTestEdge(tc.linkTrust), tc.requirements); actual != tc.expected {
+			t.Errorf("%s: expected IsTrusted() to return %t, but got %t", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestDefaultLinkTrustModelRequirementsKey(t *testing.T) {
+	trustModel := NewDefaultLinkTrustModel()
+	keyA := trustModel.RequirementsKey(&fogappsCRDs.LinkTrustRequirements{Encrypted: true, AdministrativeDomains: []string{"b", "a"}})
+	keyB := trustModel.RequirementsKey(&fogappsCRDs.LinkTrustRequirements{Encrypted: true, AdministrativeDomains: []string{"a", "b"}})
+	keyC := trustModel.RequirementsKey(&fogappsCRDs.LinkTrustRequirements{Encrypted: true, Private: true, AdministrativeDomains: []string{"a", "b"}})
+
+	if keyA != keyB {
+		t.Errorf("expected the keys of equivalent requirements to be equal, but got %q and %q", keyA, keyB)
+	}
+	if keyB == keyC {
+		t.Errorf("expected the keys of different requirements to differ, but both are %q", keyB)
+	}
+}
@@ -0,0 +1,171 @@
+package networkqos
+
+import (
+	"fmt"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	defaultLatencyWeight           int64 = 1
+	defaultJitterWeight            int64 = 1
+	defaultBandwidthHeadroomWeight int64 = 1
+	defaultPacketLossWeight        int64 = 1
+
+	defaultUnconnectedNodePolicy = RejectUnconnectedNodes
+)
+
+// UnconnectedNodePolicy determines how the NetworkQosPlugin treats nodes that are not connected to any other node by a NetworkLink.
+type UnconnectedNodePolicy string
+
+const (
+	// Unconnected nodes are unschedulable for all pods that are part of a ServiceGraph.
+	RejectUnconnectedNodes UnconnectedNodePolicy = "Reject"
+
+	// Unconnected nodes pass the Filter phase without any NetworkQoS checks and receive the lowest NetworkQoS score.
+	// This is useful if the network topology of the cluster has not been (completely) modeled using NetworkLinks.
+	IgnoreUnconnectedNodes UnconnectedNodePolicy = "Ignore"
+)
+
+// NetworkQosArgs configures the NetworkQosPlugin.
+//
+// The score of a node is the weighted average of the scores for the individual network QoS metrics.
+// Each metric's score is normalized across all nodes that have passed the Filter phase.
+// Weights must not be negative and at least one weight must be greater than zero.
+type NetworkQosArgs struct {
+	meta.TypeMeta `json:",inline"`
+
+	// The weight of the packet delay of the paths to the node and of the latency budgets.
+	//
+	// Default: 1
+	LatencyWeight *int64 `json:"latencyWeight,omitempty"`
+
+	// The weight of the packet delay variance (i.e., jitter) of the paths to the node.
+	//
+	// Default: 1
+	JitterWeight *int64 `json:"jitterWeight,omitempty"`
+
+	// The weight of the bandwidth that remains available on the paths to the node after placing the pod.
+	//
+	// Default: 1
+	BandwidthHeadroomWeight *int64 `json:"bandwidthHeadroomWeight,omitempty"`
+
+	// The weight of the packet loss of the paths to the node.
+	//
+	// Default: 1
+	PacketLossWeight *int64 `json:"packetLossWeight,omitempty"`
+
+	// Determines how nodes that are not connected to any other node by a NetworkLink are treated.
+	// Valid values are "Reject" and "Ignore".
+	//
+	// Default: "Reject"
+	UnconnectedNodePolicy *UnconnectedNodePolicy `json:"unconnectedNodePolicy,omitempty"`
+}
+
+// Decodes the NetworkQosArgs from the runtime.Object passed to New(), sets the defaults, and validates them.
+func decodeNetworkQosArgs(obj runtime.Object) (*NetworkQosArgs, error) {
+	args := &NetworkQosArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	setDefaultsNetworkQosArgs(args)
+	if err := validateNetworkQosArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// AddArgsToScheme registers the NetworkQosArgs and their defaults with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &NetworkQosArgs{}, func(obj interface{}) {
+		setDefaultsNetworkQosArgs(obj.(*NetworkQosArgs))
+	})
+}
+
+func setDefaultsNetworkQosArgs(args *NetworkQosArgs) {
+	setDefaultWeight := func(weight **int64, defaultValue int64) {
+		if *weight == nil {
+			value := defaultValue
+			*weight = &value
+		}
+	}
+	setDefaultWeight(&args.LatencyWeight, defaultLatencyWeight)
+	setDefaultWeight(&args.JitterWeight, defaultJitterWeight)
+	setDefaultWeight(&args.BandwidthHeadroomWeight, defaultBandwidthHeadroomWeight)
+	setDefaultWeight(&args.PacketLossWeight, defaultPacketLossWeight)
+
+	if args.UnconnectedNodePolicy == nil {
+		policy := defaultUnconnectedNodePolicy
+		args.UnconnectedNodePolicy = &policy
+	}
+}
+
+func validateNetworkQosArgs(args *NetworkQosArgs) error {
+	weights := map[string]int64{
+		"latencyWeight":           *args.LatencyWeight,
+		"jitterWeight":            *args.JitterWeight,
+		"bandwidthHeadroomWeight": *args.BandwidthHeadroomWeight,
+		"packetLossWeight":        *args.PacketLossWeight,
+	}
+	for name, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("invalid %s args: %s must not be negative, but is %d", PluginName, name, weight)
+		}
+	}
+	if args.totalWeight() == 0 {
+		return fmt.Errorf("invalid %s args: at least one weight must be greater than zero", PluginName)
+	}
+	switch *args.UnconnectedNodePolicy {
+	case RejectUnconnectedNodes, IgnoreUnconnectedNodes:
+	default:
+		return fmt.Errorf("invalid %s args: unknown unconnectedNodePolicy %q", PluginName, *args.UnconnectedNodePolicy)
+	}
+	return nil
+}
+
+// Returns the sum of all weights.
+func (me *NetworkQosArgs) totalWeight() int64 {
+	return *me.LatencyWeight + *me.JitterWeight + *me.BandwidthHeadroomWeight + *me.PacketLossWeight
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *NetworkQosArgs) DeepCopyInto(out *NetworkQosArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+	copyInt64 := func(value *int64) *int64 {
+		if value == nil {
+			return nil
+		}
+		ret := *value
+		return &ret
+	}
+	out.LatencyWeight = copyInt64(me.LatencyWeight)
+	out.JitterWeight = copyInt64(me.JitterWeight)
+	out.BandwidthHeadroomWeight = copyInt64(me.BandwidthHeadroomWeight)
+	out.PacketLossWeight = copyInt64(me.PacketLossWeight)
+	if me.UnconnectedNodePolicy != nil {
+		policy := *me.UnconnectedNodePolicy
+		out.UnconnectedNodePolicy = &policy
+	}
+}
+
+// DeepCopy creates a deep copy of the NetworkQosArgs.
+func (me *NetworkQosArgs) DeepCopy() *NetworkQosArgs {
+	if me == nil {
+		return nil
+	}
+	out := &NetworkQosArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the NetworkQosArgs as a runtime.Object.
+func (me *NetworkQosArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package networkqos
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDecodeNetworkQosArgs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		obj      runtime.Object
+		expected NetworkQosArgs
+		wantErr  bool
+	}{
+		{
+			name:     "nil args",
+			obj:      nil,
+			expected: newTestArgs(1, 1, 1, 1),
+		},
+		{
+			name:     "partial args",
+			obj:      &runtime.Unknown{Raw: []byte(`{"latencyWeight": 4, "packetLossWeight": 0}`)},
+			expected: newTestArgs(4, 1, 1, 0),
+		},
+		{
+			name:    "negative weight",
+			obj:     &runtime.Unknown{Raw: []byte(`{"jitterWeight": -1}`)},
+			wantErr: true,
+		},
+		{
+			name:     "unconnected node policy",
+			obj:      &runtime.Unknown{Raw: []byte(`{"unconnectedNodePolicy": "Ignore"}`)},
+			expected: newTestArgsWithPolicy(1, 1, 1, 1, IgnoreUnconnectedNodes),
+		},
+		{
+			name:    "unknown unconnected node policy",
+			obj:     &runtime.Unknown{Raw: []byte(`{"unconnectedNodePolicy": "Allow"}`)},
+			wantErr: true,
+		},
+		{
+			name:    "all weights zero",
+			obj:     &runtime.Unknown{Raw: []byte(`{"latencyWeight": 0, "jitterWeight": 0, "bandwidthHeadroomWeight": 0, "packetLossWeight": 0}`)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		args, err := decodeNetworkQosArgs(tc.obj)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if *args.LatencyWeight != *tc.expected.LatencyWeight || *args.JitterWeight != *tc.expected.JitterWeight ||
+			*args.BandwidthHeadroomWeight != *tc.expected.BandwidthHeadroomWeight || *args.PacketLossWeight != *tc.expected.PacketLossWeight {
+			t.Errorf("%s: expected weights %d/%d/%d/%d, but got %d/%d/%d/%d", tc.name,
+				*tc.expected.LatencyWeight, *tc.expected.JitterWeight, *tc.expected.BandwidthHeadroomWeight, *tc.expected.PacketLossWeight,
+				*args.LatencyWeight, *args.JitterWeight, *args.BandwidthHeadroomWeight, *args.PacketLossWeight)
+		}
+		expectedPolicy := RejectUnconnectedNodes
+		if tc.expected.UnconnectedNodePolicy != nil {
+			expectedPolicy = *tc.expected.UnconnectedNodePolicy
+		}
+		if *args.UnconnectedNodePolicy != expectedPolicy {
+			t.Errorf("%s: expected unconnectedNodePolicy %s, but got %s", tc.name, expectedPolicy, *args.UnconnectedNodePolicy)
+		}
+	}
+}
+
+func newTestArgsWithPolicy(latency, jitter, bandwidthHeadroom, packetLoss int64, policy UnconnectedNodePolicy) NetworkQosArgs {
+	args := newTestArgs(latency, jitter, bandwidthHeadroom, packetLoss)
+	args.UnconnectedNodePolicy = &policy
+	return args
+}
@@ -2,24 +2,38 @@ package networkqos
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 
 	"gonum.org/v1/gonum/graph"
 	graphpath "gonum.org/v1/gonum/graph/path"
 	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
 	framework "k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
 	clusterCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
 	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/bandwidthledger"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
 	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
 )
 
 const (
@@ -33,22 +47,79 @@ var (
 	_ framework.Plugin          = _networkQosPlugin
 	_ framework.PreFilterPlugin = _networkQosPlugin
 	_ framework.FilterPlugin    = _networkQosPlugin
+	_ framework.PreScorePlugin  = _networkQosPlugin
 	_ framework.ScorePlugin     = _networkQosPlugin
 	_ framework.ScoreExtensions = _networkQosPlugin
+	_ framework.ReservePlugin   = _networkQosPlugin
+	_ framework.PreBindPlugin   = _networkQosPlugin
 )
 
 // NetworkQosPlugin is a Filter plugin that filters out nodes that violate the network QoS constraints of the application.
+// Network paths that cross network links, which are not trusted according to the LinkTrustModel, are not considered.
+//
+// The bandwidth that the ServiceLinks of already placed pods consume on the network links is recorded in the BandwidthLedger,
+// such that the Filter phase only considers the remaining bandwidth of a network link.
+// The reservations of each pod are persisted in its kubeutil.AnnotationBandwidthReservations annotation in the PreBind phase,
+// such that the BandwidthLedger can be restored when the scheduler is restarted.
+//
+// The nodes that pass the Filter phase are scored based on the latency, jitter, bandwidth headroom, and packet loss
+// of their paths, weighted according to the NetworkQosArgs.
 type NetworkQosPlugin struct {
-	regionManager regionmanager.RegionManager
+	args *NetworkQosArgs
+
+	regionManager   regionmanager.RegionManager
+	svcGraphManager servicegraphmanager.ServiceGraphManager
+	bandwidthLedger bandwidthledger.BandwidthLedger
+
+	// Decides which network links may be traversed by the paths of a ServiceLink with trust requirements.
+	trustModel LinkTrustModel
+
+	// Used to list the existing pods when rebuilding the BandwidthLedger.
+	podLister corelisters.PodLister
+
+	// Used to resolve the attachment points of UserNodes.
+	nodeLister corelisters.NodeLister
+
+	// Used to persist the bandwidth reservations of the pods.
+	clientSet kubernetes.Interface
+
+	// Ensures that the BandwidthLedger is rebuilt from the existing pods only once.
+	rebuildLedgerOnce sync.Once
 }
 
 var _ framework.FilterPlugin = &NetworkQosPlugin{}
 
-// New creates a new NetworkQosPlugin instance.
-func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	return &NetworkQosPlugin{
-		regionManager: regionmanager.GetRegionManager(),
-	}, nil
+// NewFactory returns a PluginFactory that creates NetworkQosPlugin instances, which use the specified services.
+func NewFactory(services *pluginservices.PluginServices) frameworkruntime.PluginFactory {
+	return func(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		return newNetworkQosPlugin(obj, handle, services)
+	}
+}
+
+func newNetworkQosPlugin(obj runtime.Object, handle framework.Handle, services *pluginservices.PluginServices) (*NetworkQosPlugin, error) {
+	args, err := decodeNetworkQosArgs(obj)
+	if err != nil {
+		return nil, err
+	}
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods()
+
+	plugin := &NetworkQosPlugin{
+		args:            args,
+		regionManager:   services.RegionManager,
+		svcGraphManager: services.ServiceGraphManager,
+		bandwidthLedger: services.BandwidthLedger,
+		trustModel:      NewDefaultLinkTrustModel(),
+		podLister:       podInformer.Lister(),
+		nodeLister:      handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
+		clientSet:       handle.ClientSet(),
+	}
+
+	// Release the bandwidth reserved by a pod, when it is deleted.
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: plugin.onPodDeleted,
+	})
+
+	return plugin, nil
 }
 
 // Name returns the name of this scheduler plugin.
@@ -56,32 +127,50 @@ func (me *NetworkQosPlugin) Name() string {
 	return PluginName
 }
 
-// PreScore finds incoming links in ServiceGraph and caches them in the networkQosStateData with the following information:
-// - the ServiceLink itself
-// - the network QoS requirements
-// - SRC = { K8s nodes that have the Service Link’s source pod scheduled on them and the shortest paths from them }
+// PreFilter finds incoming links and outgoing links to already placed targets in ServiceGraph and caches them in the networkQosStateData
+// with the following information:
+//   - the ServiceLink itself
+//   - the network QoS requirements
+//   - PEERS = { K8s nodes that have the Service Link’s source pod (incoming links) or target pod (outgoing links) scheduled on them
+//     or, if the source or target is a UserNode, the UserNode's attachment points }
+//
+// Additionally, it computes the delays of the already placed hops of the LatencyBudgets that involve the pod's ServiceGraph node.
 func (me *NetworkQosPlugin) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod) *framework.Status {
 	svcGraphState, noSvcGraphStatus := util.GetServiceGraphFromCycleStateOrStatus(cycleState)
 	if noSvcGraphStatus != nil {
 		return noSvcGraphStatus
 	}
 
+	// The scheduler waits for its informer caches to sync before the first scheduling cycle,
+	// so we can rebuild the BandwidthLedger from the existing pods here.
+	me.rebuildLedgerOnce.Do(me.rebuildBandwidthLedger)
+
 	svcGraph := svcGraphState.ServiceGraph()
 	podSvcNode, _ := util.GetServiceGraphNode(svcGraph, pod)
-	region := me.regionManager.RegionGraph()
+	pathCache := me.regionManager.PathCache()
+	region := pathCache.RegionGraph()
 	incomingLinks, err := me.getIncomingSvcLinks(svcGraphState, podSvcNode, region)
 	if err != nil {
 		return framework.AsStatus(err)
 	}
+	outgoingLinks, err := me.getOutgoingSvcLinksToPlacedTargets(svcGraphState, podSvcNode, region)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
 	minNetworkQosReqs := me.getMinNetworkRequirements(svcGraphState, podSvcNode, incomingLinks)
 
 	qosState := networkQosStateData{
 		svcGraphState:          svcGraphState,
 		regionGraph:            region,
+		pathCache:              pathCache,
 		podSvcNode:             podSvcNode,
 		incomingLinks:          incomingLinks,
+		outgoingLinks:          outgoingLinks,
 		minNetworkRequirements: minNetworkQosReqs,
-		k8sNodeScores:          sync.Map{},
+		k8sNodeMetrics:         &sync.Map{},
+	}
+	if qosState.latencyBudgets, err = me.getLatencyBudgets(svcGraphState, podSvcNode, qosState.serviceLinks(), pathCache); err != nil {
+		return framework.AsStatus(err)
 	}
 	cycleState.Write(networkQosStateKey, &qosState)
 
@@ -97,11 +186,18 @@ func (me *NetworkQosPlugin) PreFilterExtensions() framework.PreFilterExtensions
 // If the node does not meet the requirements, Filter() returns an unschedulable status.
 //
 // Filter() performs the following operations:
+//  0. If the candidate K8s node is not connected to any other node by a NetworkLink, apply the UnconnectedNodePolicy from the args.
 //  1. Check if the candidate K8s node's network links support the minNetworkRequirements.
-//  2. FOR EACH incoming service link:
-//     2.1. Compute the shortest paths (latency-wise) from all SRC nodes (see PreFilter) to the candidate K8s node.
+//  2. FOR EACH incoming service link and outgoing service link to an already placed target:
+//     2.1. Get the shortest paths (latency-wise) between all PEERS nodes (see PreFilter) and the candidate K8s node.
+//     The paths follow the direction of the Service Link, i.e., from the PEERS nodes to the candidate node for incoming links
+//     and from the candidate node to the PEERS nodes for outgoing links, because network links may have asymmetric QoS.
+//     These paths only traverse network links that individually meet the Service Link's QoS and trust requirements and
+//     that have enough unreserved bandwidth left (see findShortestCompliantPathWithResidualBandwidth()).
 //     2.2. Pick shortest path that meets the network QoS requirements of the Service Link. If there is none, the candidate node is not suitable.
-//     2.3. If the candidate node is suitable, store the path’s highest bandwidth and latency variance values in the networkQosStateData.
+//  3. FOR EACH LatencyBudget that involves the pod's ServiceGraph node:
+//     Check if the delays of the already placed hops plus the delays of the paths to the candidate node (from step 2) exceed the budget.
+//  4. If the candidate node is suitable, store the QoS metrics of its paths in the networkQosStateData for the Score phase.
 func (me *NetworkQosPlugin) Filter(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, candidateK8sNodeInfo *framework.NodeInfo) *framework.Status {
 	qosState, noSvcGraphStatus := getNetworkQosStateDataOrStatus(cycleState)
 	if noSvcGraphStatus != nil {
@@ -109,9 +205,9 @@ func (me *NetworkQosPlugin) Filter(ctx context.Context, cycleState *framework.Cy
 	}
 
 	region := qosState.regionGraph
-	candidateK8sNode, err := me.getK8sNodeFromRegion(region, candidateK8sNodeInfo.Node().Name)
-	if err != nil {
-		return framework.AsStatus(err)
+	candidateK8sNode := me.getConnectedK8sNode(region, candidateK8sNodeInfo.Node().Name)
+	if candidateK8sNode == nil {
+		return me.filterUnconnectedNode(qosState, candidateK8sNodeInfo.Node().Name)
 	}
 
 	// Check if any of the candidate node's network connections meets the minimum requirements from all incoming and outgoing service links.
@@ -124,22 +220,58 @@ func (me *NetworkQosPlugin) Filter(ctx context.Context, cycleState *framework.Cy
 
 	// Loop through the incoming service links and if the candidate node fails to meet the QoS requirements
 	// even for a single link, return Unschedulable.
-	shortestPaths := make([]*networkPathInfo, len(qosState.incomingLinks))
-	for i, incomingServiceLink := range qosState.incomingLinks {
-		shortestCompliantPath := me.findShortestCompliantPath(incomingServiceLink, candidateK8sNode, region)
+	svcLinks := qosState.serviceLinks()
+	shortestPaths := make([]*regiongraph.PathQoS, len(svcLinks))
+	for i, svcLink := range svcLinks {
+		shortestCompliantPath := me.findShortestCompliantPathWithResidualBandwidth(svcLink, candidateK8sNode, qosState.pathCache)
 		if shortestCompliantPath == nil {
 			return framework.NewStatus(
 				framework.Unschedulable,
-				fmt.Sprintf("Node %s does not meet the NetworkQoS requirements for ServiceLink from %s.", candidateK8sNode.Label(), incomingServiceLink.link.ServiceLink().Source),
+				fmt.Sprintf("Node %s does not meet the NetworkQoS and trust requirements for %s.", candidateK8sNode.Label(), me.describeServiceLink(svcLink)),
 			)
 		}
 		shortestPaths[i] = shortestCompliantPath
 	}
 
-	// Compute the node's score and store it for the Score phase.
-	// We do the computation now to not require us to store the networkPathInfos for all nodes.
-	nodeScore := me.computeNodeScore(shortestPaths)
-	qosState.k8sNodeScores.Store(candidateK8sNodeInfo.Node().Name, nodeScore)
+	var highestChainDelayMsec int64
+	if len(qosState.latencyBudgets) > 0 {
+		pathsBySvcLink := make(map[*serviceLinkInfo]*regiongraph.PathQoS, len(svcLinks))
+		for i, svcLink := range svcLinks {
+			pathsBySvcLink[svcLink] = shortestPaths[i]
+		}
+		chainDelayMsec, violatedBudget := me.checkLatencyBudgets(qosState.latencyBudgets, pathsBySvcLink)
+		if violatedBudget != nil {
+			return framework.NewStatus(
+				framework.Unschedulable,
+				fmt.Sprintf("Node %s exceeds the latency budget %s of %d ms.", candidateK8sNode.Label(), violatedBudget.Name, violatedBudget.MaxPacketDelayMsec),
+			)
+		}
+		highestChainDelayMsec = chainDelayMsec
+	}
+
+	// Compute the node's QoS metrics and store them for the Score phase.
+	// We do the computation now to not require us to store the PathQoS objects for all nodes.
+	metrics := me.computeNodeQosMetrics(region, svcLinks, shortestPaths, highestChainDelayMsec)
+	qosState.k8sNodeMetrics.Store(candidateK8sNodeInfo.Node().Name, metrics)
+
+	return framework.NewStatus(framework.Success)
+}
+
+// PreScore computes the range of each QoS metric across all nodes that have passed the Filter phase,
+// which is needed for normalizing the metrics in the Score phase.
+func (me *NetworkQosPlugin) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodes []*core.Node) *framework.Status {
+	qosState, noSvcGraphStatus := getNetworkQosStateDataOrStatus(cycleState)
+	if noSvcGraphStatus != nil {
+		return noSvcGraphStatus
+	}
+
+	allMetrics := make([]*nodeQosMetrics, 0, len(nodes))
+	for _, node := range nodes {
+		if metrics, ok := qosState.k8sNodeMetrics.Load(node.Name); ok && !metrics.(*nodeQosMetrics).unconnected {
+			allMetrics = append(allMetrics, metrics.(*nodeQosMetrics))
+		}
+	}
+	qosState.metricsRange = newNodeQosMetricsRange(allMetrics)
 
 	return framework.NewStatus(framework.Success)
 }
@@ -158,15 +290,15 @@ func (me *NetworkQosPlugin) Score(ctx context.Context, cycleState *framework.Cyc
 		return 100, noSvcGraphStatus
 	}
 
-	score, ok := qosState.k8sNodeScores.Load(nodeName)
+	metrics, ok := qosState.k8sNodeMetrics.Load(nodeName)
 	if !ok {
-		return 0, framework.AsStatus(fmt.Errorf("the node %s has no score in qosState.k8sNodeScores", nodeName))
+		return 0, framework.AsStatus(fmt.Errorf("the node %s has no metrics in qosState.k8sNodeMetrics", nodeName))
 	}
-	intScore, ok := score.(int64)
-	if !ok {
-		return 0, framework.AsStatus(fmt.Errorf("could not convert qosState.k8sNodeScores[%s] into an int64", nodeName))
+	if qosState.metricsRange == nil {
+		return 0, framework.AsStatus(fmt.Errorf("the metrics range has not been computed by PreScore()"))
 	}
-	return intScore, framework.NewStatus(framework.Success)
+	score := computeWeightedNodeQosScore(metrics.(*nodeQosMetrics), qosState.metricsRange, me.args)
+	return score, framework.NewStatus(framework.Success)
 }
 
 // NormalizeScore normalizes all scores to a range between 0 and 100.
@@ -176,24 +308,216 @@ func (me *NetworkQosPlugin) NormalizeScore(ctx context.Context, cycleState *fram
 		return noSvcGraphStatus
 	}
 
-	// The score method already returns normalized values.
-	// So, we only use NormalizeScore() for cleaning up the state.
-	// util.NormalizeNodeScores(scores)
+	// The qosState must not be purged from the CycleState here, because it is still needed by Reserve().
+	normalizeNodeQosScores(scores, me.args)
+
+	return framework.NewStatus(framework.Success)
+}
+
+// Reserve records the bandwidth that the incoming ServiceLinks and the outgoing ServiceLinks to UserNodes
+// consume on the network links of the paths to the selected node in the BandwidthLedger.
+//
+// The outgoing ServiceLinks to ServiceNodes are not charged, because every pod of their targets has already
+// reserved the same ServiceLinks as incoming links (see isChargedToPod()).
+func (me *NetworkQosPlugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) *framework.Status {
+	qosState, noSvcGraphStatus := getNetworkQosStateDataOrStatus(cycleState)
+	if noSvcGraphStatus != nil {
+		return noSvcGraphStatus
+	}
+
+	k8sNode := me.getConnectedK8sNode(qosState.regionGraph, nodeName)
+	if k8sNode == nil {
+		// The node has passed the Filter phase due to the UnconnectedNodePolicy, so there are no network links to reserve bandwidth on.
+		return framework.NewStatus(framework.Success)
+	}
+
+	reservations := make([]bandwidthledger.BandwidthReservation, 0)
+	for _, svcLink := range qosState.serviceLinks() {
+		requiredBandwidth := getRequiredBandwidthKbps(svcLink.qosRequirements)
+		if requiredBandwidth == 0 || !isChargedToPod(svcLink) {
+			continue
+		}
+		path := me.findShortestCompliantPathWithResidualBandwidth(svcLink, k8sNode, qosState.pathCache)
+		if path == nil {
+			return framework.NewStatus(
+				framework.Unschedulable,
+				fmt.Sprintf("The bandwidth for the %s on node %s is no longer available.", me.describeServiceLink(svcLink), nodeName),
+			)
+		}
+		reservations = append(reservations, bandwidthledger.NewBandwidthReservationsForPath(path.Path, requiredBandwidth)...)
+	}
+
+	me.bandwidthLedger.Reserve(pod, reservations)
+	qosState.reservations = reservations
+	return framework.NewStatus(framework.Success)
+}
+
+// Unreserve releases the bandwidth reserved for the pod.
+// This method must not fail
+func (me *NetworkQosPlugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) {
+	me.bandwidthLedger.Release(pod)
+}
 
-	// Purge qosState from CycleState to allow freeing memory.
-	cycleState.Delete(networkQosStateKey)
+// PreBind persists the bandwidth reservations of the pod in its kubeutil.AnnotationBandwidthReservations annotation,
+// such that rebuildBandwidthLedger() can restore the exact paths chosen by Reserve() after a restart of the scheduler.
+// Pods without any reservations are not annotated.
+//
+// Failing to persist the reservations does not prevent the pod from being bound, because they are still recorded in the
+// BandwidthLedger. Instead, they are recomputed for such a pod when the BandwidthLedger is rebuilt.
+func (me *NetworkQosPlugin) PreBind(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) *framework.Status {
+	qosState, noSvcGraphStatus := getNetworkQosStateDataOrStatus(cycleState)
+	if noSvcGraphStatus != nil {
+		return noSvcGraphStatus
+	}
+	if len(qosState.reservations) == 0 {
+		return framework.NewStatus(framework.Success)
+	}
 
+	if err := me.persistBandwidthReservations(ctx, pod, qosState.reservations); err != nil {
+		klog.Errorf("NetworkQoS: could not persist the bandwidth reservations of pod %s.%s: %s", pod.Namespace, pod.Name, err)
+	}
 	return framework.NewStatus(framework.Success)
 }
 
+// Stores the reservations in the kubeutil.AnnotationBandwidthReservations annotation of the pod.
+func (me *NetworkQosPlugin) persistBandwidthReservations(ctx context.Context, pod *core.Pod, reservations []bandwidthledger.BandwidthReservation) error {
+	value, err := bandwidthledger.EncodeReservationsAnnotation(reservations)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{kubeutil.AnnotationBandwidthReservations: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = me.clientSet.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, meta.PatchOptions{})
+	return err
+}
+
+func (me *NetworkQosPlugin) onPodDeleted(obj interface{}) {
+	var pod *core.Pod
+	switch t := obj.(type) {
+	case *core.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		if p, ok := t.Obj.(*core.Pod); ok {
+			pod = p
+		}
+	}
+	if pod != nil {
+		me.bandwidthLedger.Release(pod)
+	}
+}
+
+// Rebuilds the BandwidthLedger from all existing pods that have been placed and that belong to a ServiceGraph.
+//
+// The reservations of the pods that carry the kubeutil.AnnotationBandwidthReservations annotation are restored exactly as
+// Reserve() has made them. The reservations of the remaining pods, e.g., of pods that have been scheduled before their
+// reservations were persisted, are recomputed afterwards (see recomputeBandwidthReservations()).
+// This is an approximation: the recomputed paths may differ from the ones chosen by Reserve(), because the residual bandwidth
+// at the time of scheduling depended on the reservations of pods that may have been deleted since.
+// To approximate the original order, in which Reserve() has been called, these pods are processed in the order of their creation.
+func (me *NetworkQosPlugin) rebuildBandwidthLedger() {
+	svcGraphReq, err := labels.NewRequirement(kubeutil.LabelRefServiceGraph, selection.Exists, nil)
+	if err != nil {
+		klog.Errorf("NetworkQoS: could not rebuild the BandwidthLedger: %s", err)
+		return
+	}
+	pods, err := me.podLister.List(labels.NewSelector().Add(*svcGraphReq))
+	if err != nil {
+		klog.Errorf("NetworkQoS: could not rebuild the BandwidthLedger: %s", err)
+		return
+	}
+
+	unpersistedPods := make([]*core.Pod, 0)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == core.PodSucceeded || pod.Status.Phase == core.PodFailed {
+			continue
+		}
+		reservations, found, err := bandwidthledger.GetReservationsFromAnnotation(pod)
+		if err != nil {
+			klog.Errorf("NetworkQoS: could not decode the bandwidth reservations of pod %s.%s: %s", pod.Namespace, pod.Name, err)
+		}
+		if !found || err != nil {
+			unpersistedPods = append(unpersistedPods, pod)
+			continue
+		}
+		me.bandwidthLedger.Reserve(pod, reservations)
+	}
+
+	sort.SliceStable(unpersistedPods, func(i, j int) bool {
+		podI, podJ := unpersistedPods[i], unpersistedPods[j]
+		if !podI.CreationTimestamp.Equal(&podJ.CreationTimestamp) {
+			return podI.CreationTimestamp.Before(&podJ.CreationTimestamp)
+		}
+		if podI.Namespace != podJ.Namespace {
+			return podI.Namespace < podJ.Namespace
+		}
+		return podI.Name < podJ.Name
+	})
+
+	pathCache := me.regionManager.PathCache()
+	for _, pod := range unpersistedPods {
+		me.recomputeBandwidthReservations(pod, pathCache)
+	}
+}
+
+// Recomputes the bandwidth reservations of an existing pod, whose reservations have not been persisted, and records them in the BandwidthLedger.
+//
+// Like Reserve(), we use the shortest compliant paths with enough residual bandwidth and only consider the ServiceLinks
+// that are charged to the pod (see isChargedToPod()), so every ServiceLink of an existing pod is accounted for exactly once.
+func (me *NetworkQosPlugin) recomputeBandwidthReservations(pod *core.Pod, pathCache regionmanager.RegionPathCache) {
+	region := pathCache.RegionGraph()
+	k8sNode := region.NodeByLabel(pod.Spec.NodeName)
+	if k8sNode == nil {
+		return
+	}
+
+	svcGraphState, err := me.svcGraphManager.GetServiceGraphState(pod)
+	if err != nil || svcGraphState == nil {
+		return
+	}
+
+	podSvcNode, err := util.GetServiceGraphNode(svcGraphState.ServiceGraph(), pod)
+	if err != nil {
+		return
+	}
+	incomingLinks, err := me.getIncomingSvcLinks(svcGraphState, podSvcNode, region)
+	if err != nil {
+		klog.Errorf("NetworkQoS: could not compute the bandwidth reservations of pod %s.%s: %s", pod.Namespace, pod.Name, err)
+		return
+	}
+	outgoingLinks, err := me.getOutgoingSvcLinksToPlacedTargets(svcGraphState, podSvcNode, region)
+	if err != nil {
+		klog.Errorf("NetworkQoS: could not compute the bandwidth reservations of pod %s.%s: %s", pod.Namespace, pod.Name, err)
+		return
+	}
+
+	reservations := make([]bandwidthledger.BandwidthReservation, 0)
+	for _, svcLink := range append(incomingLinks, outgoingLinks...) {
+		requiredBandwidth := getRequiredBandwidthKbps(svcLink.qosRequirements)
+		if requiredBandwidth == 0 || !isChargedToPod(svcLink) {
+			continue
+		}
+		if path := me.findShortestCompliantPathWithResidualBandwidth(svcLink, k8sNode, pathCache); path != nil {
+			reservations = append(reservations, bandwidthledger.NewBandwidthReservationsForPath(path.Path, requiredBandwidth)...)
+		}
+	}
+	me.bandwidthLedger.Reserve(pod, reservations)
+}
+
 // Finds the incoming service links to the ServiceGraph node that corresponds to the pod to be scheduled.
-// All incoming service links are returned, regardless of whether they have QosRequirements set or not.
+// All incoming service links are returned, regardless of whether they have QosRequirements set or not,
+// except for the links from UserNodes without attachment points.
 func (me *NetworkQosPlugin) getIncomingSvcLinks(
 	svcGraphState servicegraphmanager.ServiceGraphState,
 	podSvcNode servicegraph.Node,
 	region regiongraph.RegionGraph,
-) ([]*incomingServiceLink, error) {
-	incomingLinks := make([]*incomingServiceLink, 0)
+) ([]*serviceLinkInfo, error) {
+	incomingLinks := make([]*serviceLinkInfo, 0)
 	destSvcNodeId := podSvcNode.ID()
 	svcGraph := svcGraphState.ServiceGraph()
 	placementMap, err := svcGraphState.PlacementMap()
@@ -205,12 +529,20 @@ func (me *NetworkQosPlugin) getIncomingSvcLinks(
 	for incomingLinkIterator.Next() {
 		srcSvcNode := incomingLinkIterator.Node().(servicegraph.Node)
 		srcSvcNodeId := srcSvcNode.ID()
+		if isUnattachedUserNode(srcSvcNode) {
+			// We do not know where the users come from, so we cannot check the link.
+			continue
+		}
 
 		incomingLink := svcGraph.Graph().Edge(srcSvcNodeId, destSvcNodeId).(servicegraph.Edge)
-		nodeAndLinkPair := incomingServiceLink{
+		qosRequirements := incomingLink.ServiceLink().QosRequirements
+		nodeAndLinkPair := serviceLinkInfo{
 			link:            incomingLink,
-			qosRequirements: incomingLink.ServiceLink().QosRequirements,
-			k8sSrcNodes:     me.buildK8sSourceNodeInfosForLink(incomingLink, placementMap, region),
+			outgoing:        false,
+			qosRequirements: qosRequirements,
+			linkFilter:      newQosLinkFilter(incomingLink.ServiceLink(), me.trustModel),
+			residualPaths:   &sync.Map{},
+			k8sPeerNodes:    me.getPlacedK8sNodes(srcSvcNode, placementMap, region),
 		}
 		incomingLinks = append(incomingLinks, &nodeAndLinkPair)
 	}
@@ -218,11 +550,50 @@ func (me *NetworkQosPlugin) getIncomingSvcLinks(
 	return incomingLinks, nil
 }
 
+// Finds the outgoing service links of the ServiceGraph node that corresponds to the pod to be scheduled,
+// whose targets have already been placed on at least one K8s node (e.g., on scale-out of the pod's service).
+// Outgoing links to targets that have not been placed yet are checked when their target pods are scheduled.
+func (me *NetworkQosPlugin) getOutgoingSvcLinksToPlacedTargets(
+	svcGraphState servicegraphmanager.ServiceGraphState,
+	podSvcNode servicegraph.Node,
+	region regiongraph.RegionGraph,
+) ([]*serviceLinkInfo, error) {
+	outgoingLinks := make([]*serviceLinkInfo, 0)
+	srcSvcNodeId := podSvcNode.ID()
+	svcGraph := svcGraphState.ServiceGraph()
+	placementMap, err := svcGraphState.PlacementMap()
+	if err != nil {
+		return nil, err
+	}
+
+	outgoingLinkIterator := svcGraph.Graph().From(srcSvcNodeId)
+	for outgoingLinkIterator.Next() {
+		destSvcNode := outgoingLinkIterator.Node().(servicegraph.Node)
+		k8sDestNodes := me.getPlacedK8sNodes(destSvcNode, placementMap, region)
+		if len(k8sDestNodes) == 0 {
+			continue
+		}
+
+		outgoingLink := svcGraph.Graph().Edge(srcSvcNodeId, destSvcNode.ID()).(servicegraph.Edge)
+		qosRequirements := outgoingLink.ServiceLink().QosRequirements
+		outgoingLinks = append(outgoingLinks, &serviceLinkInfo{
+			link:            outgoingLink,
+			outgoing:        true,
+			qosRequirements: qosRequirements,
+			linkFilter:      newQosLinkFilter(outgoingLink.ServiceLink(), me.trustModel),
+			residualPaths:   &sync.Map{},
+			k8sPeerNodes:    k8sDestNodes,
+		})
+	}
+
+	return outgoingLinks, nil
+}
+
 // Computes the minimum network QoS requirements, based on all outgoing and incoming ServiceLinks to the ServiceGraph node.
 func (me *NetworkQosPlugin) getMinNetworkRequirements(
 	svcGraphState servicegraphmanager.ServiceGraphState,
 	podSvcNode servicegraph.Node,
-	incomingLinks []*incomingServiceLink,
+	incomingLinks []*serviceLinkInfo,
 ) *fogappsCRDs.LinkQosRequirements {
 	svcGraph := svcGraphState.ServiceGraph()
 	srcNodeId := podSvcNode.ID()
@@ -261,49 +632,91 @@ func (me *NetworkQosPlugin) getMinNetworkRequirements(
 	return minReqs
 }
 
-func (me *NetworkQosPlugin) buildK8sSourceNodeInfosForLink(
-	link servicegraph.Edge,
+// Gets the K8s nodes from the region that host a pod of the specified ServiceGraph node.
+// For a UserNode, these are its attachment points.
+func (me *NetworkQosPlugin) getPlacedK8sNodes(
+	svcNode servicegraph.Node,
 	placementMap serviceplacement.ServiceGraphPlacementMap,
 	region regiongraph.RegionGraph,
-) []k8sSourceNode {
-	k8sSrcNodeNames := placementMap.GetKubernetesNodes(link.From().(servicegraph.Node).Label())
-	nodeInfos := make([]k8sSourceNode, len(k8sSrcNodeNames))
+) []regiongraph.Node {
+	if isUserNode(svcNode) {
+		return me.getUserNodeAttachmentPoints(svcNode, region)
+	}
+
+	k8sNodeNames := placementMap.GetKubernetesNodes(svcNode.Label())
+	k8sNodes := make([]regiongraph.Node, 0, len(k8sNodeNames))
 
-	for i, nodeName := range k8sSrcNodeNames {
+	for _, nodeName := range k8sNodeNames {
 		k8sNode := region.NodeByLabel(nodeName)
-		shortestPaths := graphpath.DijkstraFrom(k8sNode, region.Graph())
-		nodeInfos[i] = k8sSourceNode{
-			k8sNode:              k8sNode,
-			shortestNetworkPaths: &shortestPaths,
+		if k8sNode == nil {
+			// The node is not connected to any other node, so there are no paths from it.
+			continue
 		}
+		k8sNodes = append(k8sNodes, k8sNode)
 	}
 
-	return nodeInfos
+	return k8sNodes
 }
 
-func (me *NetworkQosPlugin) getK8sNodeFromRegion(region regiongraph.RegionGraph, nodeName string) (regiongraph.Node, error) {
+// Returns a description of the ServiceLink for status messages.
+func (me *NetworkQosPlugin) describeServiceLink(svcLink *serviceLinkInfo) string {
+	if svcLink.outgoing {
+		return fmt.Sprintf("ServiceLink to %s", svcLink.link.ServiceLink().Target)
+	}
+	return fmt.Sprintf("ServiceLink from %s", svcLink.link.ServiceLink().Source)
+}
+
+// Returns the region graph node of the K8s node with the specified name or nil, if the node is not part of the region graph
+// or is not connected to any other node by a network link.
+//
+// Since every NetworkLink is represented by edges in both directions, it suffices to check the outgoing edges.
+func (me *NetworkQosPlugin) getConnectedK8sNode(region regiongraph.RegionGraph, nodeName string) regiongraph.Node {
 	k8sNode := region.NodeByLabel(nodeName)
-	if k8sNode == nil {
-		return nil, fmt.Errorf("the node %s was not found in the region graph", nodeName)
+	if k8sNode == nil || region.Graph().From(k8sNode.ID()).Len() == 0 {
+		return nil
 	}
-	return k8sNode, nil
+	return k8sNode
 }
 
-// Returns the shortest path between for the incoming service link that meets the QoS requirements or nil if none can be found.
+// Applies the UnconnectedNodePolicy to a candidate node that is not connected to any other node by a network link.
+func (me *NetworkQosPlugin) filterUnconnectedNode(qosState *networkQosStateData, nodeName string) *framework.Status {
+	switch *me.args.UnconnectedNodePolicy {
+	case IgnoreUnconnectedNodes:
+		qosState.k8sNodeMetrics.Store(nodeName, &nodeQosMetrics{unconnected: true})
+		return framework.NewStatus(framework.Success)
+	default:
+		return framework.NewStatus(
+			framework.Unschedulable,
+			fmt.Sprintf("Node %s is not connected to any other node by a NetworkLink.", nodeName),
+		)
+	}
+}
+
+// Returns the shortest path between the peer nodes of the service link and the candidate node that meets the QoS requirements or nil if none can be found.
+//
+// The QoS requirements of a ServiceLink consist of bottleneck constraints (bandwidth, bandwidth variance, jitter, packet loss, and quality class),
+// which must be met by every network link on a path, and a single additive constraint (packet delay), which must be met by the sum of all network links on the path.
+// Since the pathCache computes the shortest paths using only network links that are accepted by the link's qosLinkFilter, i.e., that meet all bottleneck constraints,
+// a path that meets all requirements exists if and only if the shortest of these paths meets the packet delay constraint.
+// Thus, we do not miss a compliant path, even if the path with the lowest delay in the entire region violates one of the other requirements.
 func (me *NetworkQosPlugin) findShortestCompliantPath(
-	incomingSvcLink *incomingServiceLink,
+	svcLink *serviceLinkInfo,
 	candidateK8sNode regiongraph.Node,
-	region regiongraph.RegionGraph,
-) *networkPathInfo {
-	var shortestPath *networkPathInfo
-
-	for _, k8sSrcNode := range incomingSvcLink.k8sSrcNodes {
-		path, _ := k8sSrcNode.shortestNetworkPaths.To(candidateK8sNode.ID())
-		pathInfo := me.computeNetworkPathInfo(path, region)
+	pathCache regionmanager.RegionPathCache,
+) *regiongraph.PathQoS {
+	var shortestPath *regiongraph.PathQoS
+
+	for _, k8sPeerNode := range svcLink.k8sPeerNodes {
+		srcNode, destNode := me.getPathEndpoints(svcLink, k8sPeerNode, candidateK8sNode)
+		pathQos := pathCache.ShortestPath(srcNode, destNode, svcLink.linkFilter)
+		if pathQos == nil {
+			// There is no path between this peer node and the candidate node that consists only of compliant network links.
+			continue
+		}
 
-		if me.checkPathMeetsRequirements(&pathInfo, incomingSvcLink.qosRequirements) {
-			if shortestPath == nil || pathInfo.totalPacketDelayMsec < shortestPath.totalPacketDelayMsec {
-				shortestPath = &pathInfo
+		if me.checkPathMeetsRequirements(pathQos, svcLink.qosRequirements) {
+			if shortestPath == nil || pathQos.TotalPacketDelayMsec < shortestPath.TotalPacketDelayMsec {
+				shortestPath = pathQos
 			}
 		}
 	}
@@ -311,51 +724,88 @@ func (me *NetworkQosPlugin) findShortestCompliantPath(
 	return shortestPath
 }
 
-func (me *NetworkQosPlugin) computeNetworkPathInfo(path []graph.Node, region regiongraph.RegionGraph) networkPathInfo {
-	pathInfo := networkPathInfo{
-		lowestBandwithKbps:            math.MaxInt64,
-		lowestNetworkQualityClassKbps: math.MaxInt64,
-	}
-
-	pathLength := len(path)
-	for i := 0; i < pathLength-1; i++ {
-		startNode := path[i]
-		endNode := path[i+1]
-		link := region.Graph().Edge(startNode.ID(), endNode.ID()).(regiongraph.Edge)
-		linkQos := link.NetworkLinkQoS()
+// Returns the shortest path for the service link that meets the QoS requirements and whose network links
+// all have enough unreserved bandwidth left for the ServiceLink or nil if none can be found.
+//
+// If the shortest compliant path from the pathCache has enough residual bandwidth, it is the shortest path overall.
+// Otherwise, we need to search again on the network links that also have enough residual bandwidth.
+// Since the residual bandwidth changes with every reservation, these paths cannot be stored in the pathCache,
+// so they are cached in the serviceLinkInfo for the current scheduling cycle.
+func (me *NetworkQosPlugin) findShortestCompliantPathWithResidualBandwidth(
+	svcLink *serviceLinkInfo,
+	candidateK8sNode regiongraph.Node,
+	pathCache regionmanager.RegionPathCache,
+) *regiongraph.PathQoS {
+	shortestPath := me.findShortestCompliantPath(svcLink, candidateK8sNode, pathCache)
+	requiredBandwidth := getRequiredBandwidthKbps(svcLink.qosRequirements)
+	if shortestPath == nil || requiredBandwidth == 0 || me.checkPathHasResidualBandwidth(shortestPath, pathCache.RegionGraph(), requiredBandwidth) {
+		return shortestPath
+	}
+
+	region := pathCache.RegionGraph()
+	shortestPath = nil
+
+	for _, k8sPeerNode := range svcLink.k8sPeerNodes {
+		srcNode, destNode := me.getPathEndpoints(svcLink, k8sPeerNode, candidateK8sNode)
+		shortestPaths := me.getResidualShortestPaths(svcLink, srcNode, region, requiredBandwidth)
+		path, _ := shortestPaths.To(destNode.ID())
+		if len(path) == 0 {
+			continue
+		}
+		pathQos := regiongraph.NewPathQoS(path, region)
 
-		// Quality class
-		if linkQos.QualityClass != "" {
-			linkQualityClassKbps := clusterCRDs.NetworkQualitClassToKbps(linkQos.QualityClass)
-			if linkQualityClassKbps < pathInfo.lowestNetworkQualityClassKbps {
-				pathInfo.lowestNetworkQualityClassKbps = linkQualityClassKbps
+		if me.checkPathMeetsRequirements(pathQos, svcLink.qosRequirements) {
+			if shortestPath == nil || pathQos.TotalPacketDelayMsec < shortestPath.TotalPacketDelayMsec {
+				shortestPath = pathQos
 			}
 		}
+	}
 
-		// Throughput
-		if linkQos.Throughput.BandwidthKbps < pathInfo.lowestBandwithKbps {
-			pathInfo.lowestBandwithKbps = linkQos.Throughput.BandwidthKbps
-		}
-		if linkQos.Throughput.BandwidthVariance > pathInfo.highestBandwidthVariance {
-			pathInfo.highestBandwidthVariance = linkQos.Throughput.BandwidthVariance
-		}
+	return shortestPath
+}
 
-		// Latency
-		pathInfo.totalPacketDelayMsec += int64(linkQos.Latency.PacketDelayMsec)
-		if linkQos.Latency.PacketDelayVariance > pathInfo.highestPacketDelayVariance {
-			pathInfo.highestPacketDelayVariance = linkQos.Latency.PacketDelayVariance
-		}
+// Returns the source and the destination node of the network path for the svcLink between the k8sPeerNode and the candidateK8sNode,
+// such that the path follows the direction of the ServiceLink from its Source to its Target.
+func (me *NetworkQosPlugin) getPathEndpoints(svcLink *serviceLinkInfo, k8sPeerNode, candidateK8sNode regiongraph.Node) (regiongraph.Node, regiongraph.Node) {
+	if svcLink.outgoing {
+		return candidateK8sNode, k8sPeerNode
+	}
+	return k8sPeerNode, candidateK8sNode
+}
 
-		// Packet loss
-		if linkQos.PacketLoss.PacketLossBp > pathInfo.highestPacketLossBp {
-			pathInfo.highestPacketLossBp = linkQos.PacketLoss.PacketLossBp
+// Gets the shortest paths from the srcNode that only traverse network links that meet the QoS requirements of the
+// svcLink and that have at least requiredBandwidth unreserved. The paths are computed only once per scheduling cycle.
+func (me *NetworkQosPlugin) getResidualShortestPaths(
+	svcLink *serviceLinkInfo,
+	srcNode regiongraph.Node,
+	region regiongraph.RegionGraph,
+	requiredBandwidth int64,
+) *graphpath.Shortest {
+	residualPathsObj, _ := svcLink.residualPaths.LoadOrStore(srcNode.ID(), &residualShortestPaths{})
+	residualPaths := residualPathsObj.(*residualShortestPaths)
+
+	residualPaths.once.Do(func() {
+		compliantRegion := regiongraph.NewFilteredGraphView(region, func(link regiongraph.Edge) bool {
+			return svcLink.linkFilter.Accepts(link) && me.bandwidthLedger.ResidualBandwidthKbps(link) >= requiredBandwidth
+		})
+		residualPaths.paths = graphpath.DijkstraFrom(srcNode, compliantRegion)
+	})
+	return &residualPaths.paths
+}
+
+// Checks if all network links along the path have at least requiredBandwidth unreserved.
+func (me *NetworkQosPlugin) checkPathHasResidualBandwidth(pathQos *regiongraph.PathQoS, region regiongraph.RegionGraph, requiredBandwidth int64) bool {
+	pathLength := len(pathQos.Path)
+	for i := 0; i < pathLength-1; i++ {
+		link := region.Graph().Edge(pathQos.Path[i].ID(), pathQos.Path[i+1].ID()).(regiongraph.Edge)
+		if me.bandwidthLedger.ResidualBandwidthKbps(link) < requiredBandwidth {
+			return false
 		}
 	}
-
-	return pathInfo
+	return true
 }
 
-func (me *NetworkQosPlugin) checkPathMeetsRequirements(pathInfo *networkPathInfo, requirements *fogappsCRDs.LinkQosRequirements) bool {
+func (me *NetworkQosPlugin) checkPathMeetsRequirements(pathQos *regiongraph.PathQoS, requirements *fogappsCRDs.LinkQosRequirements) bool {
 	// If there are no requirements, the path definitely fulfills them.
 	if requirements == nil {
 		return true
@@ -366,28 +816,28 @@ func (me *NetworkQosPlugin) checkPathMeetsRequirements(pathInfo *networkPathInfo
 	// QualityClass
 	if req := requirements.LinkType; req != nil && req.MinQualityClass != nil {
 		requestedQualityClassKbps := clusterCRDs.NetworkQualitClassToKbps(*req.MinQualityClass)
-		ok = ok && pathInfo.lowestNetworkQualityClassKbps >= requestedQualityClassKbps
+		ok = ok && pathQos.LowestNetworkQualityClassKbps >= requestedQualityClassKbps
 	}
 
 	// Throughput
 	if req := requirements.Throughput; req != nil {
-		ok = ok && pathInfo.lowestBandwithKbps >= req.MinBandwidthKbps
+		ok = ok && pathQos.LowestBandwidthKbps >= req.MinBandwidthKbps
 		if req.MaxBandwidthVariance != nil {
-			ok = ok && pathInfo.highestBandwidthVariance <= *req.MaxBandwidthVariance
+			ok = ok && pathQos.HighestBandwidthVariance <= *req.MaxBandwidthVariance
 		}
 	}
 
 	// Latency
 	if req := requirements.Latency; req != nil {
-		ok = ok && pathInfo.totalPacketDelayMsec <= int64(req.MaxPacketDelayMsec)
+		ok = ok && pathQos.TotalPacketDelayMsec <= int64(req.MaxPacketDelayMsec)
 		if req.MaxPacketDelayVariance != nil {
-			ok = ok && pathInfo.highestPacketDelayVariance <= *req.MaxPacketDelayVariance
+			ok = ok && pathQos.HighestPacketDelayVariance <= *req.MaxPacketDelayVariance
 		}
 	}
 
 	// Packet loss
 	if req := requirements.PacketLoss; req != nil {
-		ok = ok && pathInfo.highestPacketLossBp <= req.MaxPacketLossBp
+		ok = ok && pathQos.HighestPacketLossBp <= req.MaxPacketLossBp
 	}
 
 	return ok
@@ -407,92 +857,97 @@ func (me *NetworkQosPlugin) checkNodeMeetsMinRequirements(
 		return true
 	}
 
-	compliantNodeFound := false
-
-	candidateNodeId := candidateK8sNode.ID()
-	networkLinksIterator := region.Graph().From(candidateNodeId)
-	for networkLinksIterator.Next() && !compliantNodeFound {
-		link := region.Graph().Edge(candidateNodeId, networkLinksIterator.Node().ID()).(regiongraph.Edge)
+	checkLink := func(edge graph.Edge) bool {
+		if edge == nil {
+			return false
+		}
+		link := edge.(regiongraph.Edge)
 		linkQos := link.NetworkLinkQoS()
 		if linkQos == nil {
-			continue
-		}
-		ok := true
-
-		// QualityClass
-		if req := requirements.LinkType; req != nil && req.MinQualityClass != nil {
-			requestedQualityClassKbps := clusterCRDs.NetworkQualitClassToKbps(*req.MinQualityClass)
-			linkQualitClassKbps := clusterCRDs.NetworkQualitClassToKbps(linkQos.QualityClass)
-			ok = ok && linkQualitClassKbps >= requestedQualityClassKbps
+			return false
 		}
 
-		// Throughput
-		if req := requirements.Throughput; req != nil {
-			ok = ok && linkQos.Throughput.BandwidthKbps >= req.MinBandwidthKbps
-			if req.MaxBandwidthVariance != nil {
-				ok = ok && linkQos.Throughput.BandwidthVariance <= *req.MaxBandwidthVariance
-			}
-		}
+		// Only the bandwidth that has not been reserved by other pods is available.
+		residualLinkQos := *linkQos
+		residualLinkQos.Throughput.BandwidthKbps = me.bandwidthLedger.ResidualBandwidthKbps(link)
+		return checkLinkMeetsRequirements(&residualLinkQos, requirements)
+	}
 
-		// Latency
-		if req := requirements.Latency; req != nil {
-			ok = ok && linkQos.Latency.PacketDelayMsec <= req.MaxPacketDelayMsec
-			if req.MaxPacketDelayVariance != nil {
-				ok = ok && linkQos.Latency.PacketDelayVariance <= *req.MaxPacketDelayVariance
-			}
-		}
+	compliantNodeFound := false
 
-		// Packet loss
-		if req := requirements.PacketLoss; req != nil {
-			ok = ok && linkQos.PacketLoss.PacketLossBp <= req.MaxPacketLossBp
+	// In a directed RegionGraph, we need to check both directions of the network links, because the minimum requirements
+	// stem from incoming and outgoing ServiceLinks.
+	candidateNodeId := candidateK8sNode.ID()
+	networkLinksIterator := region.Graph().From(candidateNodeId)
+	for networkLinksIterator.Next() && !compliantNodeFound {
+		neighborId := networkLinksIterator.Node().ID()
+		compliantNodeFound = checkLink(region.Graph().Edge(candidateNodeId, neighborId))
+		if !compliantNodeFound && region.IsDirected() {
+			compliantNodeFound = checkLink(region.Graph().Edge(neighborId, candidateNodeId))
 		}
-
-		compliantNodeFound = compliantNodeFound || ok
 	}
 
 	return compliantNodeFound
 }
 
-// Computes a node score, based on the highest bandwidth and latency variance value among the incoming the shortestPaths.
-func (me *NetworkQosPlugin) computeNodeScore(shortestPaths []*networkPathInfo) int64 {
-	if len(shortestPaths) == 0 {
-		// There are no incoming service links, so we return the best score.
-		return 100
-	}
-
-	var highestBandwidthVarPath *networkPathInfo
-	var highestPacketDelayVarPath *networkPathInfo
-	for _, path := range shortestPaths {
-		if highestBandwidthVarPath == nil || path.highestBandwidthVariance > highestBandwidthVarPath.highestBandwidthVariance {
-			highestBandwidthVarPath = path
-		}
-		if highestPacketDelayVarPath == nil || path.highestPacketDelayVariance > highestPacketDelayVarPath.highestPacketDelayVariance {
-			highestPacketDelayVarPath = path
+// Computes the QoS metrics of a candidate node from the shortestPaths of the incoming and outgoing service links
+// and the highest cumulative delay of the chains of the LatencyBudgets (0 if there are no LatencyBudgets for the pod).
+func (me *NetworkQosPlugin) computeNodeQosMetrics(
+	region regiongraph.RegionGraph,
+	svcLinks []*serviceLinkInfo,
+	shortestPaths []*regiongraph.PathQoS,
+	highestChainDelayMsec int64,
+) *nodeQosMetrics {
+	metrics := &nodeQosMetrics{
+		delayMsec: float64(highestChainDelayMsec),
+	}
+
+	for i, path := range shortestPaths {
+		metrics.delayMsec = math.Max(metrics.delayMsec, float64(path.TotalPacketDelayMsec))
+		metrics.jitterMsec = math.Max(metrics.jitterMsec, math.Sqrt(float64(path.HighestPacketDelayVariance)))
+		metrics.packetLossBp = math.Max(metrics.packetLossBp, float64(path.HighestPacketLossBp))
+
+		headroom := float64(me.getPathResidualBandwidthKbps(path, region) - getRequiredBandwidthKbps(svcLinks[i].qosRequirements))
+		if i == 0 || headroom < metrics.bandwidthHeadroomKbps {
+			metrics.bandwidthHeadroomKbps = headroom
 		}
 	}
 
-	// To calculate the score, we compute the standard deviation of the bandwidth and divide it by the lowest bandwidth found along the path to get a sort of fluctuation percentage value.
-	// Then we calculate the score as score = 100 (fluctuationPercentage * 100 * 10)
-	bandwidthStdDev := math.Sqrt(float64(highestBandwidthVarPath.highestBandwidthVariance))
-	bandwidthFluctuationPercentage := bandwidthStdDev / float64(highestBandwidthVarPath.lowestBandwithKbps)
-	bandwidthScore := 100.0 - bandwidthFluctuationPercentage*1000
-	bandwidthScore = math.Max(0, bandwidthScore)
-
-	// The same for the latency, except that we use only 100 as the multiplier for the fluctuationPercentage:
-	latencyStdDev := math.Sqrt(float64(highestPacketDelayVarPath.highestPacketDelayVariance))
-	latencyFluctuationPercentage := latencyStdDev / float64(highestPacketDelayVarPath.totalPacketDelayMsec)
-	latencyScore := 100.0 - latencyFluctuationPercentage*100
-	latencyScore = math.Max(0, latencyScore)
+	return metrics
+}
 
-	overallScore := int64(math.Round((bandwidthScore + latencyScore) / 2))
-	if overallScore < 0 {
-		overallScore = 0
+// Returns the lowest unreserved bandwidth of any network link along the path.
+func (me *NetworkQosPlugin) getPathResidualBandwidthKbps(pathQos *regiongraph.PathQoS, region regiongraph.RegionGraph) int64 {
+	var lowestResidualBandwidth int64 = math.MaxInt64
+	pathLength := len(pathQos.Path)
+	for i := 0; i < pathLength-1; i++ {
+		link := region.Graph().Edge(pathQos.Path[i].ID(), pathQos.Path[i+1].ID()).(regiongraph.Edge)
+		if residual := me.bandwidthLedger.ResidualBandwidthKbps(link); residual < lowestResidualBandwidth {
+			lowestResidualBandwidth = residual
+		}
 	}
-	if overallScore > 100 {
-		overallScore = 100
+	if lowestResidualBandwidth == math.MaxInt64 {
+		// The peer is located on the candidate node, so the bandwidth is not limited by any network link.
+		return pathQos.LowestBandwidthKbps
 	}
+	return lowestResidualBandwidth
+}
+
+// Returns true if the bandwidth of the svcLink must be reserved for the pod, for which the svcLink has been collected.
+//
+// Every pod of a ServiceNode reserves the bandwidth of its incoming ServiceLinks, so an outgoing ServiceLink is
+// only charged to its source pod if its target is a UserNode, which has no pods. Otherwise, restarting or scaling out
+// a source pod would charge the ServiceLinks to its already placed targets a second time.
+func isChargedToPod(svcLink *serviceLinkInfo) bool {
+	return !svcLink.outgoing || isUserNode(svcLink.link.To().(servicegraph.Node))
+}
 
-	return overallScore
+// Returns the bandwidth that needs to be reserved for a ServiceLink with the specified requirements.
+func getRequiredBandwidthKbps(requirements *fogappsCRDs.LinkQosRequirements) int64 {
+	if requirements == nil || requirements.Throughput == nil {
+		return 0
+	}
+	return requirements.Throughput.MinBandwidthKbps
 }
 
 // Creates a new LinkQosRequirements object filled with the most lenient values.
@@ -0,0 +1,458 @@
+package networkqos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/bandwidthledger"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+// edge-1 and edge-2 are connected by a direct, but slow link and by a fast detour over fog.
+// The link between fog and cloud has a slow uplink (fog -> cloud) and a fast downlink (cloud -> fog).
+const filterTestTopology = `
+links:
+  - nodeA: edge-1
+    nodeB: edge-2
+    qos:
+      qualityClass: QC1Mbps
+      throughput:
+        bandwidthKbps: 1000
+      latency:
+        packetDelayMsec: 5
+  - nodeA: edge-1
+    nodeB: fog
+    qos:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+      latency:
+        packetDelayMsec: 10
+  - nodeA: fog
+    nodeB: edge-2
+    qos:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+      latency:
+        packetDelayMsec: 10
+  - nodeA: fog
+    nodeB: cloud
+    qos:
+      qualityClass: QC1Mbps
+      throughput:
+        bandwidthKbps: 2000
+      latency:
+        packetDelayMsec: 30
+    qosBtoA:
+      qualityClass: QC100Mbps
+      throughput:
+        bandwidthKbps: 100000
+      latency:
+        packetDelayMsec: 30
+`
+
+type filterTestCase struct {
+	name string
+
+	// The ServiceLinks between the ServiceGraphNodes user, a, b, and c.
+	links []fogappsCRDs.ServiceLink
+
+	latencyBudgets []fogappsCRDs.LatencyBudget
+
+	// The attachment points of the UserNode.
+	attachmentPoints *fogappsCRDs.UserNodeAttachmentPoints
+
+	// Maps ServiceGraphNodes to the K8s nodes, on which one of their pods has been placed.
+	placedPods map[string]string
+
+	// The ServiceGraphNode of the pod to be scheduled.
+	podSvcNode string
+
+	candidateNode string
+	expected      framework.Code
+}
+
+func newQosRequirements(minBandwidthKbps int64, maxPacketDelayMsec int32) *fogappsCRDs.LinkQosRequirements {
+	reqs := &fogappsCRDs.LinkQosRequirements{
+		Latency: &fogappsCRDs.NetworkLatencyRequirements{MaxPacketDelayMsec: maxPacketDelayMsec},
+	}
+	if minBandwidthKbps > 0 {
+		reqs.Throughput = &fogappsCRDs.NetworkThroughputRequirements{MinBandwidthKbps: minBandwidthKbps}
+	}
+	return reqs
+}
+
+func newFilterTestServiceGraph(tc *filterTestCase) *fogappsCRDs.ServiceGraph {
+	svcGraph := &fogappsCRDs.ServiceGraph{}
+	svcGraph.Namespace = "test"
+	svcGraph.Name = "app"
+	svcGraph.Spec.Nodes = []fogappsCRDs.ServiceGraphNode{
+		{Name: "user", NodeType: fogappsCRDs.UserNode, AttachmentPoints: tc.attachmentPoints},
+		{Name: "a", NodeType: fogappsCRDs.ServiceNode},
+		{Name: "b", NodeType: fogappsCRDs.ServiceNode},
+		{Name: "c", NodeType: fogappsCRDs.ServiceNode},
+	}
+	svcGraph.Spec.Links = tc.links
+	svcGraph.Spec.LatencyBudgets = tc.latencyBudgets
+	return svcGraph
+}
+
+func newFilterTestPod(name string, svcNode string, nodeName string) *core.Pod {
+	pod := &core.Pod{}
+	pod.Namespace = "test"
+	pod.Name = name
+	pod.Labels = map[string]string{
+		kubeutil.LabelRefServiceGraph:     "app",
+		kubeutil.LabelRefServiceGraphNode: svcNode,
+	}
+	pod.Spec.NodeName = nodeName
+	return pod
+}
+
+func newFilterTestPlugin(t *testing.T) *NetworkQosPlugin {
+	topology, err := regionmanager.ParseNetworkTopology([]byte(filterTestTopology))
+	if err != nil {
+		t.Fatalf("could not parse the network topology: %v", err)
+	}
+	region := topology.ToRegionGraph()
+	args := &NetworkQosArgs{}
+	setDefaultsNetworkQosArgs(args)
+
+	plugin := &NetworkQosPlugin{
+		args:            args,
+		regionManager:   regionmanager.NewFakeRegionManager(region),
+		bandwidthLedger: bandwidthledger.NewBandwidthLedger(region.IsDirected()),
+		trustModel:      NewDefaultLinkTrustModel(),
+	}
+	// There are no existing pods, whose bandwidth reservations would need to be restored.
+	plugin.rebuildLedgerOnce.Do(func() {})
+	return plugin
+}
+
+func TestFilter(t *testing.T) {
+	testCases := []filterTestCase{
+		{
+			name:          "longer compliant path, because the shortest path lacks bandwidth",
+			links:         []fogappsCRDs.ServiceLink{{Source: "a", Target: "b", QosRequirements: newQosRequirements(50000, 25)}},
+			placedPods:    map[string]string{"a": "edge-1"},
+			podSvcNode:    "b",
+			candidateNode: "edge-2",
+			expected:      framework.Success,
+		},
+		{
+			name:          "compliant path exceeds the packet delay",
+			links:         []fogappsCRDs.ServiceLink{{Source: "a", Target: "b", QosRequirements: newQosRequirements(50000, 15)}},
+			placedPods:    map[string]string{"a": "edge-1"},
+			podSvcNode:    "b",
+			candidateNode: "edge-2",
+			expected:      framework.Unschedulable,
+		},
+		{
+			name:          "shortest path without bandwidth requirements",
+			links:         []fogappsCRDs.ServiceLink{{Source: "a", Target: "b", QosRequirements: newQosRequirements(0, 5)}},
+			placedPods:    map[string]string{"a": "edge-1"},
+			podSvcNode:    "b",
+			candidateNode: "edge-2",
+			expected:      framework.Success,
+		},
+		{
+			name:          "incoming link uses the direction from the placed source to the candidate",
+			links:         []fogappsCRDs.ServiceLink{{Source: "a", Target: "b", QosRequirements: newQosRequirements(50000, 100)}},
+			placedPods:    map[string]string{"a": "cloud"},
+			podSvcNode:    "b",
+			candidateNode: "fog",
+			expected:      framework.Success,
+		},
+		{
+			name:          "outgoing link uses the direction from the candidate to the placed target",
+			links:         []fogappsCRDs.ServiceLink{{Source: "b", Target: "a", QosRequirements: newQosRequirements(50000, 100)}},
+			placedPods:    map[string]string{"a": "cloud"},
+			podSvcNode:    "b",
+			candidateNode: "fog",
+			expected:      framework.Unschedulable,
+		},
+		{
+			name:          "outgoing link to a placed target with a compliant path",
+			links:         []fogappsCRDs.ServiceLink{{Source: "b", Target: "a", QosRequirements: newQosRequirements(1000, 40)}},
+			placedPods:    map[string]string{"a": "cloud"},
+			podSvcNode:    "b",
+			candidateNode: "fog",
+			expected:      framework.Success,
+		},
+		{
+			name:          "outgoing link to a target that has not been placed yet",
+			links:         []fogappsCRDs.ServiceLink{{Source: "b", Target: "a", QosRequirements: newQosRequirements(50000, 40)}},
+			placedPods:    map[string]string{},
+			podSvcNode:    "b",
+			candidateNode: "fog",
+			expected:      framework.Success,
+		},
+		{
+			name: "latency budget met by the placed hop and the candidate hop",
+			links: []fogappsCRDs.ServiceLink{
+				{Source: "a", Target: "b", QosRequirements: newQosRequirements(0, 100)},
+				{Source: "b", Target: "c", QosRequirements: newQosRequirements(0, 100)},
+			},
+			latencyBudgets: []fogappsCRDs.LatencyBudget{{Name: "a-to-c", Chain: []string{"a", "b", "c"}, MaxPacketDelayMsec: 20}},
+			placedPods:     map[string]string{"a": "edge-1", "b": "fog"},
+			podSvcNode:     "c",
+			candidateNode:  "edge-2",
+			expected:       framework.Success,
+		},
+		{
+			name: "latency budget exceeded by the placed hop and the candidate hop",
+			links: []fogappsCRDs.ServiceLink{
+				{Source: "a", Target: "b", QosRequirements: newQosRequirements(0, 100)},
+				{Source: "b", Target: "c", QosRequirements: newQosRequirements(0, 100)},
+			},
+			latencyBudgets: []fogappsCRDs.LatencyBudget{{Name: "a-to-c", Chain: []string{"a", "b", "c"}, MaxPacketDelayMsec: 35}},
+			placedPods:     map[string]string{"a": "edge-1", "b": "fog"},
+			podSvcNode:     "c",
+			candidateNode:  "cloud",
+			expected:       framework.Unschedulable,
+		},
+		{
+			name: "latency budget with a placed hop without a compliant path",
+			links: []fogappsCRDs.ServiceLink{
+				{Source: "a", Target: "b", QosRequirements: newQosRequirements(50000, 100)},
+				{Source: "b", Target: "c", QosRequirements: newQosRequirements(0, 100)},
+			},
+			latencyBudgets: []fogappsCRDs.LatencyBudget{{Name: "a-to-c", Chain: []string{"a", "b", "c"}, MaxPacketDelayMsec: 1000}},
+			placedPods:     map[string]string{"a": "fog", "b": "cloud"},
+			podSvcNode:     "c",
+			candidateNode:  "fog",
+			expected:       framework.Unschedulable,
+		},
+		{
+			name: "latency budget over hops in both directions of the pod",
+			links: []fogappsCRDs.ServiceLink{
+				{Source: "a", Target: "b", QosRequirements: newQosRequirements(0, 100)},
+				{Source: "b", Target: "c", QosRequirements: newQosRequirements(0, 100)},
+			},
+			latencyBudgets: []fogappsCRDs.LatencyBudget{{Name: "a-to-c", Chain: []string{"a", "b", "c"}, MaxPacketDelayMsec: 15}},
+			placedPods:     map[string]string{"a": "edge-1", "c": "edge-1"},
+			podSvcNode:     "b",
+			candidateNode:  "edge-2",
+			expected:       framework.Success,
+		},
+		{
+			name:             "link from a UserNode checked from its attachment points",
+			links:            []fogappsCRDs.ServiceLink{{Source: "user", Target: "a", QosRequirements: newQosRequirements(0, 8)}},
+			attachmentPoints: &fogappsCRDs.UserNodeAttachmentPoints{NodeNames: []string{"edge-1"}},
+			podSvcNode:       "a",
+			candidateNode:    "edge-2",
+			expected:         framework.Success,
+		},
+		{
+			name:             "link from a UserNode that is too far from its attachment points",
+			links:            []fogappsCRDs.ServiceLink{{Source: "user", Target: "a", QosRequirements: newQosRequirements(0, 8)}},
+			attachmentPoints: &fogappsCRDs.UserNodeAttachmentPoints{NodeNames: []string{"edge-1"}},
+			podSvcNode:       "a",
+			candidateNode:    "fog",
+			expected:         framework.Unschedulable,
+		},
+		{
+			name:          "link from a UserNode without attachment points is not checked",
+			links:         []fogappsCRDs.ServiceLink{{Source: "user", Target: "a", QosRequirements: newQosRequirements(0, 8)}},
+			podSvcNode:    "a",
+			candidateNode: "cloud",
+			expected:      framework.Success,
+		},
+	}
+
+	for i := range testCases {
+		tc := &testCases[i]
+		plugin := newFilterTestPlugin(t)
+		svcGraphMgr := servicegraphmanager.NewFakeServiceGraphManager()
+		placedPods := make([]core.Pod, 0, len(tc.placedPods))
+		for svcNode, nodeName := range tc.placedPods {
+			placedPods = append(placedPods, *newFilterTestPod(svcNode+"-0", svcNode, nodeName))
+		}
+		svcGraphState := svcGraphMgr.AddServiceGraph(newFilterTestServiceGraph(tc), placedPods...)
+		plugin.svcGraphManager = svcGraphMgr
+
+		pod := newFilterTestPod(tc.podSvcNode+"-1", tc.podSvcNode, "")
+		cycleState := framework.NewCycleState()
+		util.WriteServiceGraphToCycleState(cycleState, svcGraphState)
+		if status := plugin.PreFilter(context.Background(), cycleState, pod); !status.IsSuccess() {
+			t.Errorf("%s: PreFilter failed: %v", tc.name, status.Message())
+			continue
+		}
+
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(&core.Node{})
+		nodeInfo.Node().Name = tc.candidateNode
+		status := plugin.Filter(context.Background(), cycleState, pod, nodeInfo)
+		if status.Code() != tc.expected {
+			t.Errorf("%s: expected %v, but got %v (%s)", tc.name, tc.expected, status.Code(), status.Message())
+		}
+	}
+}
+
+// Runs the PreFilter, Filter, and Reserve phases for scheduling the pod on the K8s node and returns the CycleState.
+func reserveTestPod(t *testing.T, plugin *NetworkQosPlugin, svcGraphState servicegraphmanager.ServiceGraphState, pod *core.Pod, nodeName string) *framework.CycleState {
+	cycleState := framework.NewCycleState()
+	util.WriteServiceGraphToCycleState(cycleState, svcGraphState)
+	if status := plugin.PreFilter(context.Background(), cycleState, pod); !status.IsSuccess() {
+		t.Fatalf("PreFilter failed for pod %s: %v", pod.Name, status.Message())
+	}
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&core.Node{})
+	nodeInfo.Node().Name = nodeName
+	if status := plugin.Filter(context.Background(), cycleState, pod, nodeInfo); !status.IsSuccess() {
+		t.Fatalf("Filter failed for pod %s: %v", pod.Name, status.Message())
+	}
+	if status := plugin.Reserve(context.Background(), cycleState, pod, nodeName); !status.IsSuccess() {
+		t.Fatalf("Reserve failed for pod %s: %v", pod.Name, status.Message())
+	}
+	return cycleState
+}
+
+func newTestPodLister(t *testing.T, pods ...*core.Pod) corelisters.PodLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range pods {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatalf("could not add pod %s to the indexer: %v", pod.Name, err)
+		}
+	}
+	return corelisters.NewPodLister(indexer)
+}
+
+func checkReservedBandwidth(t *testing.T, msg string, ledger bandwidthledger.BandwidthLedger, expected map[[2]string]int64) {
+	for link, expectedKbps := range expected {
+		if actual := ledger.ReservedBandwidthKbps(link[0], link[1]); actual != expectedKbps {
+			t.Errorf("%s: expected %d kbps to be reserved from %s to %s, but got %d kbps", msg, expectedKbps, link[0], link[1], actual)
+		}
+	}
+}
+
+func TestReserveChargesEachServiceLinkOnce(t *testing.T) {
+	svcGraph := newFilterTestServiceGraph(&filterTestCase{
+		links: []fogappsCRDs.ServiceLink{
+			{Source: "a", Target: "b", QosRequirements: newQosRequirements(50000, 100)},
+			{Source: "b", Target: "user", QosRequirements: newQosRequirements(20000, 100)},
+		},
+		attachmentPoints: &fogappsCRDs.UserNodeAttachmentPoints{NodeNames: []string{"fog"}},
+	})
+	// The ServiceLink a -> b is charged to b-0 on the detour over fog, because the direct link lacks bandwidth.
+	// The ServiceLink b -> user is charged to b-0 as well, because the UserNode has no pods.
+	expected := map[[2]string]int64{
+		{"edge-1", "fog"}:    50000,
+		{"fog", "edge-2"}:    50000,
+		{"edge-2", "fog"}:    20000,
+		{"edge-1", "edge-2"}: 0,
+	}
+
+	plugin := newFilterTestPlugin(t)
+	svcGraphMgr := servicegraphmanager.NewFakeServiceGraphManager()
+	plugin.svcGraphManager = svcGraphMgr
+
+	sourcePod := newFilterTestPod("a-0", "a", "edge-1")
+	targetPod := newFilterTestPod("b-0", "b", "")
+	reserveTestPod(t, plugin, svcGraphMgr.AddServiceGraph(svcGraph, *sourcePod), targetPod, "edge-2")
+	targetPod.Spec.NodeName = "edge-2"
+	checkReservedBandwidth(t, "after placing the target", plugin.bandwidthLedger, expected)
+
+	// Restart the source pod on the same node.
+	plugin.onPodDeleted(sourcePod)
+	restartedSourcePod := newFilterTestPod("a-1", "a", "")
+	reserveTestPod(t, plugin, svcGraphMgr.AddServiceGraph(svcGraph, *targetPod), restartedSourcePod, "edge-1")
+	restartedSourcePod.Spec.NodeName = "edge-1"
+	checkReservedBandwidth(t, "after restarting the source", plugin.bandwidthLedger, expected)
+
+	// A scheduler restart must rebuild the same reservations.
+	svcGraphMgr.AddServiceGraph(svcGraph, *restartedSourcePod, *targetPod)
+	restartedPlugin := newFilterTestPlugin(t)
+	restartedPlugin.svcGraphManager = svcGraphMgr
+	restartedPlugin.podLister = newTestPodLister(t, restartedSourcePod, targetPod)
+	restartedPlugin.rebuildBandwidthLedger()
+	checkReservedBandwidth(t, "after rebuilding the ledger", restartedPlugin.bandwidthLedger, expected)
+}
+
+func TestRebuildReplaysPersistedReservations(t *testing.T) {
+	svcGraph := newFilterTestServiceGraph(&filterTestCase{
+		links: []fogappsCRDs.ServiceLink{
+			{Source: "a", Target: "b", QosRequirements: newQosRequirements(600, 100)},
+			{Source: "a", Target: "c", QosRequirements: newQosRequirements(500, 100)},
+		},
+	})
+	baseTime := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	sourcePod := newFilterTestPod("a-0", "a", "edge-1")
+	targetPodB := newFilterTestPod("b-0", "b", "")
+	targetPodC := newFilterTestPod("c-0", "c", "")
+	// c-0 has been created before b-0, but b-0 is scheduled first.
+	targetPodB.CreationTimestamp = meta.NewTime(baseTime.Add(time.Minute))
+	targetPodC.CreationTimestamp = meta.NewTime(baseTime)
+
+	plugin := newFilterTestPlugin(t)
+	clientSet := clientsetfake.NewSimpleClientset(sourcePod, targetPodB, targetPodC)
+	plugin.clientSet = clientSet
+	svcGraphMgr := servicegraphmanager.NewFakeServiceGraphManager()
+	plugin.svcGraphManager = svcGraphMgr
+	svcGraphState := svcGraphMgr.AddServiceGraph(svcGraph, *sourcePod)
+
+	// b-0 takes the direct link, so c-0 must take the detour over fog, because the direct link lacks bandwidth.
+	for _, pod := range []*core.Pod{targetPodB, targetPodC} {
+		cycleState := reserveTestPod(t, plugin, svcGraphState, pod, "edge-2")
+		if status := plugin.PreBind(context.Background(), cycleState, pod, "edge-2"); !status.IsSuccess() {
+			t.Fatalf("PreBind failed for pod %s: %v", pod.Name, status.Message())
+		}
+	}
+	expected := map[[2]string]int64{
+		{"edge-1", "edge-2"}: 600,
+		{"edge-1", "fog"}:    500,
+		{"fog", "edge-2"}:    500,
+	}
+	checkReservedBandwidth(t, "after scheduling", plugin.bandwidthLedger, expected)
+
+	boundPods := make([]*core.Pod, 0, 3)
+	for _, name := range []string{"a-0", "b-0", "c-0"} {
+		pod, err := clientSet.CoreV1().Pods("test").Get(context.Background(), name, meta.GetOptions{})
+		if err != nil {
+			t.Fatalf("could not get pod %s: %v", name, err)
+		}
+		pod.Spec.NodeName = "edge-2"
+		boundPods = append(boundPods, pod)
+	}
+	boundPods[0].Spec.NodeName = "edge-1"
+	if _, ok := kubeutil.GetAnnotation(boundPods[0], kubeutil.AnnotationBandwidthReservations); ok {
+		t.Errorf("expected the pod without reservations not to be annotated")
+	}
+	svcGraphMgr.AddServiceGraph(svcGraph, *boundPods[0], *boundPods[1], *boundPods[2])
+
+	rebuildLedger := func(pods ...*core.Pod) bandwidthledger.BandwidthLedger {
+		restartedPlugin := newFilterTestPlugin(t)
+		restartedPlugin.svcGraphManager = svcGraphMgr
+		restartedPlugin.podLister = newTestPodLister(t, pods...)
+		restartedPlugin.rebuildBandwidthLedger()
+		return restartedPlugin.bandwidthLedger
+	}
+
+	// The persisted paths are restored, even though c-0 is older than b-0.
+	checkReservedBandwidth(t, "after rebuilding the ledger from the annotations", rebuildLedger(boundPods...), expected)
+
+	// Without the annotations, the paths are recomputed in the order of the pods' creation.
+	for _, pod := range boundPods {
+		delete(pod.Annotations, kubeutil.AnnotationBandwidthReservations)
+	}
+	checkReservedBandwidth(t, "after recomputing the reservations", rebuildLedger(boundPods...), map[[2]string]int64{
+		{"edge-1", "edge-2"}: 500,
+		{"edge-1", "fog"}:    600,
+		{"fog", "edge-2"}:    600,
+	})
+}
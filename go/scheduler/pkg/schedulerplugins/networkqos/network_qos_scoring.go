@@ -0,0 +1,112 @@
+package networkqos
+
+import (
+	"math"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// The network QoS metrics of a candidate node, aggregated over the paths for all service links.
+type nodeQosMetrics struct {
+	// The highest packet delay of a path or, if there are latency budgets, of a chain.
+	// Lower is better.
+	delayMsec float64
+
+	// The highest standard deviation of the packet delay of a path.
+	// Lower is better.
+	jitterMsec float64
+
+	// The lowest bandwidth that remains available on a path after reserving the bandwidth required by its service link.
+	// Higher is better.
+	bandwidthHeadroomKbps float64
+
+	// The highest packet loss on a path.
+	// Lower is better.
+	packetLossBp float64
+
+	// True if the node is not connected to any other node and has passed the Filter phase due to the UnconnectedNodePolicy.
+	// In this case the other metrics are not set and the node receives the lowest score.
+	unconnected bool
+}
+
+// The lowest and highest values of each metric across all candidate nodes.
+type nodeQosMetricsRange struct {
+	min nodeQosMetrics
+	max nodeQosMetrics
+}
+
+// Computes the range of the metrics across all nodes.
+// The metrics of unconnected nodes must not be included.
+func newNodeQosMetricsRange(allMetrics []*nodeQosMetrics) *nodeQosMetricsRange {
+	if len(allMetrics) == 0 {
+		return &nodeQosMetricsRange{}
+	}
+
+	metricsRange := &nodeQosMetricsRange{
+		min: *allMetrics[0],
+		max: *allMetrics[0],
+	}
+	for _, metrics := range allMetrics[1:] {
+		metricsRange.min.delayMsec = math.Min(metricsRange.min.delayMsec, metrics.delayMsec)
+		metricsRange.max.delayMsec = math.Max(metricsRange.max.delayMsec, metrics.delayMsec)
+		metricsRange.min.jitterMsec = math.Min(metricsRange.min.jitterMsec, metrics.jitterMsec)
+		metricsRange.max.jitterMsec = math.Max(metricsRange.max.jitterMsec, metrics.jitterMsec)
+		metricsRange.min.bandwidthHeadroomKbps = math.Min(metricsRange.min.bandwidthHeadroomKbps, metrics.bandwidthHeadroomKbps)
+		metricsRange.max.bandwidthHeadroomKbps = math.Max(metricsRange.max.bandwidthHeadroomKbps, metrics.bandwidthHeadroomKbps)
+		metricsRange.min.packetLossBp = math.Min(metricsRange.min.packetLossBp, metrics.packetLossBp)
+		metricsRange.max.packetLossBp = math.Max(metricsRange.max.packetLossBp, metrics.packetLossBp)
+	}
+	return metricsRange
+}
+
+// Computes the weighted sum of the node's metric scores, each of which is normalized to [0, framework.MaxNodeScore]
+// using the range of the metric across all candidate nodes.
+//
+// The result lies in [0, framework.MaxNodeScore * args.totalWeight()], so it needs to be normalized using normalizeNodeQosScores().
+func computeWeightedNodeQosScore(metrics *nodeQosMetrics, metricsRange *nodeQosMetricsRange, args *NetworkQosArgs) int64 {
+	if metrics.unconnected {
+		return framework.MinNodeScore
+	}
+	score := float64(*args.LatencyWeight) * scoreLowerIsBetter(metrics.delayMsec, metricsRange.min.delayMsec, metricsRange.max.delayMsec)
+	score += float64(*args.JitterWeight) * scoreLowerIsBetter(metrics.jitterMsec, metricsRange.min.jitterMsec, metricsRange.max.jitterMsec)
+	score += float64(*args.BandwidthHeadroomWeight) * scoreHigherIsBetter(
+		metrics.bandwidthHeadroomKbps,
+		metricsRange.min.bandwidthHeadroomKbps,
+		metricsRange.max.bandwidthHeadroomKbps,
+	)
+	score += float64(*args.PacketLossWeight) * scoreLowerIsBetter(metrics.packetLossBp, metricsRange.min.packetLossBp, metricsRange.max.packetLossBp)
+	return int64(math.Round(score))
+}
+
+// Divides the weighted scores by the total weight to bring them into the range [0, framework.MaxNodeScore].
+func normalizeNodeQosScores(scores framework.NodeScoreList, args *NetworkQosArgs) {
+	totalWeight := float64(args.totalWeight())
+	for i := range scores {
+		normalized := int64(math.Round(float64(scores[i].Score) / totalWeight))
+		if normalized < framework.MinNodeScore {
+			normalized = framework.MinNodeScore
+		}
+		if normalized > framework.MaxNodeScore {
+			normalized = framework.MaxNodeScore
+		}
+		scores[i].Score = normalized
+	}
+}
+
+// Maps value from [min, max] to [framework.MaxNodeScore, 0].
+// If all nodes have the same value, all of them get framework.MaxNodeScore.
+func scoreLowerIsBetter(value, min, max float64) float64 {
+	if max <= min {
+		return float64(framework.MaxNodeScore)
+	}
+	return float64(framework.MaxNodeScore) * (max - value) / (max - min)
+}
+
+// Maps value from [min, max] to [0, framework.MaxNodeScore].
+// If all nodes have the same value, all of them get framework.MaxNodeScore.
+func scoreHigherIsBetter(value, min, max float64) float64 {
+	if max <= min {
+		return float64(framework.MaxNodeScore)
+	}
+	return float64(framework.MaxNodeScore) * (value - min) / (max - min)
+}
@@ -0,0 +1,134 @@
+package networkqos
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+type scoringTestNode struct {
+	name    string
+	metrics nodeQosMetrics
+}
+
+type scoringTestCase struct {
+	name  string
+	args  NetworkQosArgs
+	nodes []scoringTestNode
+}
+
+func newTestArgs(latency, jitter, bandwidthHeadroom, packetLoss int64) NetworkQosArgs {
+	return NetworkQosArgs{
+		LatencyWeight:           &latency,
+		JitterWeight:            &jitter,
+		BandwidthHeadroomWeight: &bandwidthHeadroom,
+		PacketLossWeight:        &packetLoss,
+	}
+}
+
+func getScoringTestCases() []scoringTestCase {
+	mixedNodes := []scoringTestNode{
+		{name: "edge-1", metrics: nodeQosMetrics{delayMsec: 5, jitterMsec: 2, bandwidthHeadroomKbps: 1000, packetLossBp: 10}},
+		{name: "edge-2", metrics: nodeQosMetrics{delayMsec: 12, jitterMsec: 1, bandwidthHeadroomKbps: 50000, packetLossBp: 0}},
+		{name: "fog-1", metrics: nodeQosMetrics{delayMsec: 20, jitterMsec: 4.5, bandwidthHeadroomKbps: 20000, packetLossBp: 25}},
+		{name: "cloud-1", metrics: nodeQosMetrics{delayMsec: 80, jitterMsec: 10, bandwidthHeadroomKbps: 1000000, packetLossBp: 5}},
+	}
+
+	return []scoringTestCase{
+		{name: "default-weights", args: newTestArgs(1, 1, 1, 1), nodes: mixedNodes},
+		{name: "latency-only", args: newTestArgs(1, 0, 0, 0), nodes: mixedNodes},
+		{name: "jitter-only", args: newTestArgs(0, 1, 0, 0), nodes: mixedNodes},
+		{name: "bandwidth-headroom-only", args: newTestArgs(0, 0, 1, 0), nodes: mixedNodes},
+		{name: "packet-loss-only", args: newTestArgs(0, 0, 0, 1), nodes: mixedNodes},
+		{name: "latency-sensitive", args: newTestArgs(5, 3, 1, 1), nodes: mixedNodes},
+		{name: "throughput-sensitive", args: newTestArgs(1, 1, 8, 2), nodes: mixedNodes},
+		{
+			name: "identical-metrics",
+			args: newTestArgs(2, 1, 1, 1),
+			nodes: []scoringTestNode{
+				{name: "node-a", metrics: nodeQosMetrics{delayMsec: 10, jitterMsec: 1, bandwidthHeadroomKbps: 500, packetLossBp: 3}},
+				{name: "node-b", metrics: nodeQosMetrics{delayMsec: 10, jitterMsec: 1, bandwidthHeadroomKbps: 500, packetLossBp: 3}},
+			},
+		},
+		{
+			name: "no-service-links",
+			args: newTestArgs(1, 1, 1, 1),
+			nodes: []scoringTestNode{
+				{name: "node-a"},
+				{name: "node-b"},
+			},
+		},
+		{
+			name: "unconnected-node",
+			args: newTestArgs(1, 1, 1, 1),
+			nodes: []scoringTestNode{
+				{name: "node-a", metrics: nodeQosMetrics{delayMsec: 10, jitterMsec: 1, bandwidthHeadroomKbps: 500, packetLossBp: 3}},
+				{name: "node-b", metrics: nodeQosMetrics{delayMsec: 40, jitterMsec: 2, bandwidthHeadroomKbps: 100, packetLossBp: 8}},
+				{name: "node-c", metrics: nodeQosMetrics{unconnected: true}},
+			},
+		},
+		{
+			name: "single-node",
+			args: newTestArgs(1, 1, 1, 1),
+			nodes: []scoringTestNode{
+				{name: "node-a", metrics: nodeQosMetrics{delayMsec: 35, jitterMsec: 3, bandwidthHeadroomKbps: 0, packetLossBp: 100}},
+			},
+		},
+	}
+}
+
+// Computes the scores of all nodes in the same way as the PreScore, Score, and NormalizeScore phases.
+func computeTestScores(tc *scoringTestCase) framework.NodeScoreList {
+	connectedMetrics := make([]*nodeQosMetrics, 0, len(tc.nodes))
+	for i := range tc.nodes {
+		if !tc.nodes[i].metrics.unconnected {
+			connectedMetrics = append(connectedMetrics, &tc.nodes[i].metrics)
+		}
+	}
+	metricsRange := newNodeQosMetricsRange(connectedMetrics)
+
+	scores := make(framework.NodeScoreList, len(tc.nodes))
+	for i := range tc.nodes {
+		scores[i] = framework.NodeScore{
+			Name:  tc.nodes[i].name,
+			Score: computeWeightedNodeQosScore(&tc.nodes[i].metrics, metricsRange, &tc.args),
+		}
+	}
+	normalizeNodeQosScores(scores, &tc.args)
+	return scores
+}
+
+func TestNodeQosScoresGolden(t *testing.T) {
+	var output strings.Builder
+	for _, tc := range getScoringTestCases() {
+		fmt.Fprintf(&output, "# %s\n", tc.name)
+		for _, score := range computeTestScores(&tc) {
+			if score.Score < framework.MinNodeScore || score.Score > framework.MaxNodeScore {
+				t.Errorf("%s: score %d of node %s is out of range", tc.name, score.Score, score.Name)
+			}
+			fmt.Fprintf(&output, "%s %d\n", score.Name, score.Score)
+		}
+	}
+
+	goldenFile := filepath.Join("testdata", "scores.golden")
+	if *updateGolden {
+		if err := os.WriteFile(goldenFile, []byte(output.String()), 0644); err != nil {
+			t.Fatalf("could not update %s: %s", goldenFile, err)
+		}
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("could not read %s: %s", goldenFile, err)
+	}
+	if output.String() != string(expected) {
+		t.Errorf("the scores do not match %s (run the test with -update to update it):\n%s", goldenFile, output.String())
+	}
+}
@@ -9,6 +9,8 @@ import (
 	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/bandwidthledger"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
 )
 
@@ -20,80 +22,96 @@ var (
 	_ framework.StateData = (*networkQosStateData)(nil)
 )
 
-// Stores information about a K8s node from the RegionGraph that hosts an incomingServiceLink's source pod.
-type k8sSourceNode struct {
-	// The K8s node in the RegionGraph.
-	k8sNode regiongraph.Node
-
-	// The shortest paths starting from the k8sNode.
-	shortestNetworkPaths *graphpath.Shortest
-}
-
-// Stores info about a ServiceGraph link that enters the current pod's ServiceGraph node.
-type incomingServiceLink struct {
-	// The ServiceGraphLink that comes from the srcNode to the ServiceGraphNode of the current pod.
+// Stores info about a ServiceGraph link that enters or leaves the current pod's ServiceGraph node.
+type serviceLinkInfo struct {
+	// The ServiceGraphLink that connects the ServiceGraphNode of the current pod with the peer ServiceGraphNode.
 	link servicegraph.Edge
 
+	// True if the link leaves the ServiceGraphNode of the current pod, i.e., the peer is the link's target,
+	// false if the link enters the ServiceGraphNode of the current pod, i.e., the peer is the link's source.
+	outgoing bool
+
 	// The QoS requirements of the link.
 	qosRequirements *fogappsCRDs.LinkQosRequirements
 
-	// The K8s nodes, to which the pods, corresponding to the source Service Node of `link`, have been deployed.
-	k8sSrcNodes []k8sSourceNode
+	// Accepts only the network links that meet the QoS requirements of the link.
+	linkFilter regionmanager.NetworkLinkFilter
+
+	// The K8s nodes, to which the pods, corresponding to the peer Service Node of `link`, have been deployed.
+	k8sPeerNodes []regiongraph.Node
+
+	// Maps the IDs of the k8sPeerNodes to *residualShortestPaths objects, which are computed
+	// when the shortest compliant path from the pathCache does not have enough residual bandwidth.
+	residualPaths *sync.Map
+}
+
+// The shortest paths from a K8s peer node that only traverse network links with enough residual bandwidth.
+type residualShortestPaths struct {
+	once  sync.Once
+	paths graphpath.Shortest
 }
 
-// Used to cache information about the incoming links to a pod's ServiceGraphNode.
+// Used to cache information about the incoming and outgoing links of a pod's ServiceGraphNode.
 type networkQosStateData struct {
 	svcGraphState servicegraphmanager.ServiceGraphState
 
 	// The region graph at the time of PreFilter() - all Filter() invocations must use the same version of this graph.
 	regionGraph regiongraph.RegionGraph
 
+	// The path cache for the regionGraph, which is used to look up the shortest paths from the K8s source nodes.
+	pathCache regionmanager.RegionPathCache
+
 	// The ServiceGraph node that corresponds to the pod to be scheduled.
 	podSvcNode servicegraph.Node
 
 	// The incoming ServiceGraph links to the podSvcNode.
-	incomingLinks []*incomingServiceLink
+	incomingLinks []*serviceLinkInfo
+
+	// The outgoing ServiceGraph links of the podSvcNode, whose targets have already been placed.
+	outgoingLinks []*serviceLinkInfo
 
 	// The minimum network QoS requirements that the region graph node must fulfill, based on the
 	// ServiceGraph node's outgoing links.
 	minNetworkRequirements *fogappsCRDs.LinkQosRequirements
 
-	// Stores the score (int64) for each K8s node that passes the Filter phase.
-	// The node name is used as the key.
-	k8sNodeScores sync.Map
-}
-
-// Stores Information about a path between two nodes in the RegionGraph.
-type networkPathInfo struct {
-	// The lowest bandwidth of any link along the path.
-	lowestBandwithKbps int64
-
-	// The highest bandwidth variance of any link along the path.
-	highestBandwidthVariance int64
+	// The LatencyBudgets whose chains contain the podSvcNode.
+	latencyBudgets []*latencyBudgetInfo
 
-	// The sum of the packet delays over the entire path.
-	totalPacketDelayMsec int64
-
-	// The highest packet delay variance of any link along the path.
-	highestPacketDelayVariance int32
+	// Stores the *nodeQosMetrics for each K8s node that passes the Filter phase.
+	// The node name is used as the key.
+	k8sNodeMetrics *sync.Map
 
-	// The highest packet loss in basis points of any link along the path.
-	highestPacketLossBp int32
+	// The range of the QoS metrics across all K8s nodes that have passed the Filter phase.
+	// This is computed by PreScore().
+	metricsRange *nodeQosMetricsRange
 
-	// The lowest QualityClass (in Kbps) of any network link in the path.
-	lowestNetworkQualityClassKbps int64
+	// The bandwidth reservations made by Reserve(), which are persisted by PreBind().
+	reservations []bandwidthledger.BandwidthReservation
 }
 
 func (me *networkQosStateData) Clone() framework.StateData {
 	return &networkQosStateData{
-		svcGraphState: me.svcGraphState,
-		regionGraph:   me.regionGraph,
-		podSvcNode:    me.podSvcNode,
-		incomingLinks: me.incomingLinks,
-		k8sNodeScores: me.k8sNodeScores,
+		svcGraphState:          me.svcGraphState,
+		regionGraph:            me.regionGraph,
+		pathCache:              me.pathCache,
+		podSvcNode:             me.podSvcNode,
+		incomingLinks:          me.incomingLinks,
+		outgoingLinks:          me.outgoingLinks,
+		minNetworkRequirements: me.minNetworkRequirements,
+		latencyBudgets:         me.latencyBudgets,
+		k8sNodeMetrics:         me.k8sNodeMetrics,
+		metricsRange:           me.metricsRange,
+		reservations:           me.reservations,
 	}
 }
 
+// Returns the incoming and outgoing links, whose network paths need to be checked.
+func (me *networkQosStateData) serviceLinks() []*serviceLinkInfo {
+	svcLinks := make([]*serviceLinkInfo, 0, len(me.incomingLinks)+len(me.outgoingLinks))
+	svcLinks = append(svcLinks, me.incomingLinks...)
+	return append(svcLinks, me.outgoingLinks...)
+}
+
 // Gets the networkQosStateData from the CycleState or returns a framework.Success state if the current pod is not associated with a ServiceGraph
 // and, thus, does not have any networkQosStateData.
 func getNetworkQosStateDataOrStatus(cycleState *framework.CycleState) (*networkQosStateData, *framework.Status) {
@@ -0,0 +1,127 @@
+package networkqos
+
+import (
+	"fmt"
+
+	clusterCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+)
+
+var (
+	_qosLinkFilter *qosLinkFilter
+
+	_ regionmanager.NetworkLinkFilter = _qosLinkFilter
+)
+
+// A NetworkLinkFilter that accepts only network links that individually meet the QoS requirements of a ServiceLink
+// and that are trusted w.r.t. the ServiceLink's trust requirements.
+//
+// Since the key is derived from the requirements, all ServiceLinks with the same QoS and trust requirements
+// share the same cached shortest paths in the RegionPathCache.
+type qosLinkFilter struct {
+	requirements      *fogappsCRDs.LinkQosRequirements
+	trustRequirements *fogappsCRDs.LinkTrustRequirements
+	trustModel        LinkTrustModel
+	key               string
+}
+
+// Creates a new qosLinkFilter for the requirements of the svcLink, which may be nil, if there are no requirements.
+func newQosLinkFilter(svcLink *fogappsCRDs.ServiceLink, trustModel LinkTrustModel) *qosLinkFilter {
+	var requirements *fogappsCRDs.LinkQosRequirements
+	var trustRequirements *fogappsCRDs.LinkTrustRequirements
+	if svcLink != nil {
+		requirements = svcLink.QosRequirements
+		trustRequirements = svcLink.TrustRequirements
+	}
+
+	return &qosLinkFilter{
+		requirements:      requirements,
+		trustRequirements: trustRequirements,
+		trustModel:        trustModel,
+		key:               buildQosLinkFilterKey(requirements) + "|" + trustModel.RequirementsKey(trustRequirements),
+	}
+}
+
+func (me *qosLinkFilter) Key() string {
+	return me.key
+}
+
+func (me *qosLinkFilter) Accepts(link regiongraph.Edge) bool {
+	return checkLinkMeetsRequirements(link.NetworkLinkQoS(), me.requirements) && me.trustModel.IsTrusted(link, me.trustRequirements)
+}
+
+// Builds a key that uniquely identifies the bottleneck constraints of the requirements.
+// Unset requirements are represented by -1.
+func buildQosLinkFilterKey(requirements *fogappsCRDs.LinkQosRequirements) string {
+	if requirements == nil {
+		return "networkqos:none"
+	}
+
+	qualityClass := ""
+	var minBandwidth, maxBandwidthVar, maxDelay, maxDelayVar, maxLoss int64 = -1, -1, -1, -1, -1
+
+	if req := requirements.LinkType; req != nil && req.MinQualityClass != nil {
+		qualityClass = string(*req.MinQualityClass)
+	}
+	if req := requirements.Throughput; req != nil {
+		minBandwidth = req.MinBandwidthKbps
+		if req.MaxBandwidthVariance != nil {
+			maxBandwidthVar = *req.MaxBandwidthVariance
+		}
+	}
+	if req := requirements.Latency; req != nil {
+		maxDelay = int64(req.MaxPacketDelayMsec)
+		if req.MaxPacketDelayVariance != nil {
+			maxDelayVar = int64(*req.MaxPacketDelayVariance)
+		}
+	}
+	if req := requirements.PacketLoss; req != nil {
+		maxLoss = int64(req.MaxPacketLossBp)
+	}
+
+	return fmt.Sprintf("networkqos:%s/%d/%d/%d/%d/%d", qualityClass, minBandwidth, maxBandwidthVar, maxDelay, maxDelayVar, maxLoss)
+}
+
+// Checks if a single network link meets the requirements.
+//
+// For the packet delay only the delay of this network link is checked, so a path that consists of multiple
+// compliant network links may still exceed the maximum packet delay.
+func checkLinkMeetsRequirements(linkQos *clusterCRDs.NetworkLinkQoS, requirements *fogappsCRDs.LinkQosRequirements) bool {
+	if requirements == nil {
+		return true
+	}
+
+	ok := true
+
+	// QualityClass
+	if req := requirements.LinkType; req != nil && req.MinQualityClass != nil {
+		requestedQualityClassKbps := clusterCRDs.NetworkQualitClassToKbps(*req.MinQualityClass)
+		linkQualitClassKbps := clusterCRDs.NetworkQualitClassToKbps(linkQos.QualityClass)
+		ok = ok && linkQualitClassKbps >= requestedQualityClassKbps
+	}
+
+	// Throughput
+	if req := requirements.Throughput; req != nil {
+		ok = ok && linkQos.Throughput.BandwidthKbps >= req.MinBandwidthKbps
+		if req.MaxBandwidthVariance != nil {
+			ok = ok && linkQos.Throughput.BandwidthVariance <= *req.MaxBandwidthVariance
+		}
+	}
+
+	// Latency
+	if req := requirements.Latency; req != nil {
+		ok = ok && linkQos.Latency.PacketDelayMsec <= req.MaxPacketDelayMsec
+		if req.MaxPacketDelayVariance != nil {
+			ok = ok && linkQos.Latency.PacketDelayVariance <= *req.MaxPacketDelayVariance
+		}
+	}
+
+	// Packet loss
+	if req := requirements.PacketLoss; req != nil {
+		ok = ok && linkQos.PacketLoss.PacketLossBp <= req.MaxPacketLossBp
+	}
+
+	return ok
+}
@@ -0,0 +1,63 @@
+package networkqos
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/regiongraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/model/graph/servicegraph"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/util"
+)
+
+// Returns true if the ServiceGraph node is a UserNode.
+func isUserNode(svcNode servicegraph.Node) bool {
+	return svcNode.ServiceGraphNode().NodeType == fogappsCRDs.UserNode
+}
+
+// Returns true if the ServiceGraph node is a UserNode without any configured attachment points.
+// The ServiceLinks from and to such a UserNode cannot be QoS-checked.
+func isUnattachedUserNode(svcNode servicegraph.Node) bool {
+	if !isUserNode(svcNode) {
+		return false
+	}
+	attachmentPoints := svcNode.ServiceGraphNode().AttachmentPoints
+	return attachmentPoints == nil || (len(attachmentPoints.NodeNames) == 0 && attachmentPoints.NodeSelector == nil)
+}
+
+// Resolves the attachment points of the UserNode to nodes in the region.
+// K8s nodes that are not part of the region are ignored.
+func (me *NetworkQosPlugin) getUserNodeAttachmentPoints(userNode servicegraph.Node, region regiongraph.RegionGraph) []regiongraph.Node {
+	attachmentPoints := userNode.ServiceGraphNode().AttachmentPoints
+	if attachmentPoints == nil {
+		return nil
+	}
+
+	k8sNodeNames := util.NewStringSet()
+	for _, nodeName := range attachmentPoints.NodeNames {
+		k8sNodeNames.Add(nodeName)
+	}
+
+	if attachmentPoints.NodeSelector != nil {
+		selector, err := meta.LabelSelectorAsSelector(attachmentPoints.NodeSelector)
+		if err != nil {
+			klog.Errorf("NetworkQoS: invalid nodeSelector for the attachment points of UserNode %s: %s", userNode.Label(), err)
+		} else {
+			if nodes, err := me.nodeLister.List(selector); err == nil {
+				for _, node := range nodes {
+					k8sNodeNames.Add(node.Name)
+				}
+			} else {
+				klog.Errorf("NetworkQoS: could not list the attachment points of UserNode %s: %s", userNode.Label(), err)
+			}
+		}
+	}
+
+	k8sNodes := make([]regiongraph.Node, 0)
+	for _, nodeName := range k8sNodeNames.Entries() {
+		if k8sNode := region.NodeByLabel(nodeName); k8sNode != nil {
+			k8sNodes = append(k8sNodes, k8sNode)
+		}
+	}
+	return k8sNodes
+}
@@ -0,0 +1,159 @@
+package nodecost
+
+import (
+	"math"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	// The number of bytes in a GiB.
+	bytesPerGiB = 1024 * 1024 * 1024
+)
+
+// The data egress of a ServiceLink between the pod's ServiceGraphNode and an already placed peer ServiceGraphNode.
+type serviceLinkEgress struct {
+	// The estimated data volume transferred over the ServiceLink per hour.
+	gibPerHour float64
+
+	// The number of pods of the peer ServiceGraphNode in each region.
+	peerPodsPerRegion map[string]int
+
+	// The total number of pods of the peer ServiceGraphNode, including those on nodes without a region.
+	totalPeerPods int
+}
+
+// Returns the hourly cost of the node.
+// For spot nodes, the spot price is increased by the SpotInterruptionPenaltyPercent.
+func getHourlyNodeCost(nodeInfo *framework.NodeInfo, args *NodeCostArgs) float64 {
+	if spotCost, ok := util.GetNodeSpotCost(nodeInfo); ok {
+		return spotCost * (1.0 + float64(*args.SpotInterruptionPenaltyPercent)/100.0)
+	}
+	return util.GetNodeCost(nodeInfo)
+}
+
+// Calculates the resources requested by the pod.
+// If the pod does not request CPU or memory, the kube-scheduler's default requests are used, such that
+// placing the pod on a node always has a cost.
+func calcPodRequests(pod *core.Pod) *framework.Resource {
+	requests := util.CalcContainersRequests(pod.Spec.Containers, pod.Spec.InitContainers)
+	if requests.MilliCPU == 0 {
+		requests.MilliCPU = schedutil.DefaultMilliCPURequest
+	}
+	if requests.Memory == 0 {
+		requests.Memory = schedutil.DefaultMemoryRequest
+	}
+	return requests
+}
+
+// Calculates the share of the node's hourly cost that corresponds to the weighted share of the node's allocatable
+// CPU and memory requested by the pod.
+func calcProRatedNodeCost(nodeInfo *framework.NodeInfo, podRequests *framework.Resource, args *NodeCostArgs) float64 {
+	share := func(requested int64, allocatable int64) float64 {
+		if allocatable <= 0 {
+			return 1.0
+		}
+		return math.Min(float64(requested)/float64(allocatable), 1.0)
+	}
+
+	cpuWeight := float64(*args.CpuWeight)
+	memWeight := float64(*args.MemoryWeight)
+	podShare := (cpuWeight*share(podRequests.MilliCPU, nodeInfo.Allocatable.MilliCPU) +
+		memWeight*share(podRequests.Memory, nodeInfo.Allocatable.Memory)) / (cpuWeight + memWeight)
+
+	return getHourlyNodeCost(nodeInfo, args) * podShare
+}
+
+// Returns the data egress of all ServiceLinks between the pod's ServiceGraphNode and ServiceGraphNodes
+// that already have pods placed on nodes. ServiceLinks without a minimum bandwidth requirement are ignored.
+//
+// getRegion must return the region of the specified node or an empty string if the region is not known.
+func getServiceLinksEgress(
+	svcGraph *fogappsCRDs.ServiceGraph,
+	podSvcNode string,
+	placementMap serviceplacement.ServiceGraphPlacementMap,
+	getRegion func(nodeName string) string,
+) []*serviceLinkEgress {
+	egress := make([]*serviceLinkEgress, 0)
+	for i := range svcGraph.Spec.Links {
+		svcLink := &svcGraph.Spec.Links[i]
+		var peerSvcNode string
+		switch podSvcNode {
+		case svcLink.Source:
+			peerSvcNode = svcLink.Target
+		case svcLink.Target:
+			peerSvcNode = svcLink.Source
+		default:
+			continue
+		}
+
+		gibPerHour := calcGiBPerHour(svcLink)
+		if gibPerHour == 0 {
+			continue
+		}
+
+		linkEgress := &serviceLinkEgress{
+			gibPerHour:        gibPerHour,
+			peerPodsPerRegion: make(map[string]int),
+		}
+		for nodeName, podCounts := range placementMap.GetAllPodCounts(peerSvcNode) {
+			count := podCounts.Total()
+			linkEgress.totalPeerPods += count
+			if region := getRegion(nodeName); region != "" {
+				linkEgress.peerPodsPerRegion[region] += count
+			}
+		}
+		if linkEgress.totalPeerPods > 0 {
+			egress = append(egress, linkEgress)
+		}
+	}
+	return egress
+}
+
+// Estimates the data volume transferred over the ServiceLink per hour from its minimum bandwidth requirement.
+func calcGiBPerHour(svcLink *fogappsCRDs.ServiceLink) float64 {
+	if svcLink.QosRequirements == nil || svcLink.QosRequirements.Throughput == nil {
+		return 0
+	}
+	bytesPerSecond := float64(svcLink.QosRequirements.Throughput.MinBandwidthKbps) * 1000.0 / 8.0
+	return bytesPerSecond * 3600.0 / bytesPerGiB
+}
+
+// Calculates the hourly egress cost of placing the pod in the specified region.
+//
+// The traffic of a ServiceLink is assumed to be evenly distributed among the pods of the peer ServiceGraphNode,
+// so the share of the traffic that crosses a region boundary is the share of the peer pods in other regions.
+// If the region is not known, no egress cost is assumed.
+func calcEgressCost(region string, egress []*serviceLinkEgress, args *NodeCostArgs) float64 {
+	if region == "" {
+		return 0
+	}
+	cost := 0.0
+	for _, linkEgress := range egress {
+		crossRegionPods := 0
+		for peerRegion, count := range linkEgress.peerPodsPerRegion {
+			if peerRegion != region {
+				crossRegionPods += count
+			}
+		}
+		crossRegionShare := float64(crossRegionPods) / float64(linkEgress.totalPeerPods)
+		cost += linkEgress.gibPerHour * crossRegionShare * *args.EgressCostPerGiB
+	}
+	return cost
+}
+
+// Returns the region of the node or an empty string if the node does not have a region label.
+func getNodeRegion(node *core.Node, regionLabel string) string {
+	if node == nil {
+		return ""
+	}
+	region, _ := kubeutil.GetLabel(node, regionLabel)
+	return region
+}
@@ -0,0 +1,178 @@
+package nodecost
+
+import (
+	"math"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/serviceplacement"
+)
+
+func newTestArgs(t *testing.T) *NodeCostArgs {
+	args, err := decodeNodeCostArgs(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return args
+}
+
+func newTestNodeInfo(labels map[string]string, cpu string, memory string) *framework.NodeInfo {
+	node := &core.Node{}
+	node.Name = "node"
+	node.Labels = labels
+	node.Status.Allocatable = core.ResourceList{
+		core.ResourceCPU:    resource.MustParse(cpu),
+		core.ResourceMemory: resource.MustParse(memory),
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	return nodeInfo
+}
+
+func checkCost(t *testing.T, name string, expected float64, actual float64) {
+	if math.Abs(expected-actual) > 1e-9 {
+		t.Errorf("%s: expected a cost of %v, but got %v", name, expected, actual)
+	}
+}
+
+func TestCalcProRatedNodeCost(t *testing.T) {
+	args := newTestArgs(t)
+	penalty := int64(50)
+	spotArgs := newTestArgs(t)
+	spotArgs.SpotInterruptionPenaltyPercent = &penalty
+
+	podRequests := &framework.Resource{MilliCPU: 1000, Memory: 1024 * 1024 * 1024}
+
+	testCases := []struct {
+		name     string
+		nodeInfo *framework.NodeInfo
+		args     *NodeCostArgs
+		expected float64
+	}{
+		{
+			name:     "on-demand node",
+			nodeInfo: newTestNodeInfo(map[string]string{kubeutil.LabelNodeCost: "1.0"}, "4", "4Gi"),
+			args:     args,
+			expected: 0.25,
+		},
+		{
+			name:     "different CPU and memory shares",
+			nodeInfo: newTestNodeInfo(map[string]string{kubeutil.LabelNodeCost: "1.0"}, "2", "8Gi"),
+			args:     args,
+			expected: (0.5 + 0.125) / 2,
+		},
+		{
+			name:     "spot node",
+			nodeInfo: newTestNodeInfo(map[string]string{kubeutil.LabelNodeCost: "1.0", kubeutil.LabelNodeSpotCost: "0.4"}, "4", "4Gi"),
+			args:     args,
+			expected: 0.1,
+		},
+		{
+			name:     "spot node with interruption penalty",
+			nodeInfo: newTestNodeInfo(map[string]string{kubeutil.LabelNodeSpotCost: "0.4"}, "4", "4Gi"),
+			args:     spotArgs,
+			expected: 0.15,
+		},
+		{
+			name:     "pod larger than node",
+			nodeInfo: newTestNodeInfo(map[string]string{kubeutil.LabelNodeCost: "1.0"}, "500m", "512Mi"),
+			args:     args,
+			expected: 1.0,
+		},
+		{
+			name:     "free node",
+			nodeInfo: newTestNodeInfo(nil, "4", "4Gi"),
+			args:     args,
+			expected: 0,
+		},
+		{
+			name:     "invalid cost label",
+			nodeInfo: newTestNodeInfo(map[string]string{kubeutil.LabelNodeCost: "expensive"}, "4", "4Gi"),
+			args:     args,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		checkCost(t, tc.name, tc.expected, calcProRatedNodeCost(tc.nodeInfo, podRequests, tc.args))
+	}
+}
+
+func TestCalcEgressCost(t *testing.T) {
+	args := newTestArgs(t)
+	egressCost := 1.0
+	args.EgressCostPerGiB = &egressCost
+
+	// 8 Mbps * 3600s = 3600 MB per hour
+	kbps := int64(8 * 1024 * 1024 / 1000)
+	svcGraph := &fogappsCRDs.ServiceGraph{}
+	svcGraph.Spec.Links = []fogappsCRDs.ServiceLink{
+		{Source: "frontend", Target: "backend", QosRequirements: &fogappsCRDs.LinkQosRequirements{
+			Throughput: &fogappsCRDs.NetworkThroughputRequirements{MinBandwidthKbps: kbps},
+		}},
+		{Source: "backend", Target: "db", QosRequirements: &fogappsCRDs.LinkQosRequirements{
+			Throughput: &fogappsCRDs.NetworkThroughputRequirements{MinBandwidthKbps: kbps},
+		}},
+		{Source: "backend", Target: "cache"},
+	}
+
+	placementMap := serviceplacement.NewServicePlacementMap(false)
+	placementMap.SetPodCounts("frontend", map[string]serviceplacement.PodCounts{
+		"eu-node": {Bound: 1},
+		"us-node": {Bound: 2, Assumed: 1},
+	})
+	placementMap.SetPodCounts("cache", map[string]serviceplacement.PodCounts{"us-node": {Bound: 1}})
+	regions := map[string]string{"eu-node": "eu", "us-node": "us"}
+
+	egress := getServiceLinksEgress(svcGraph, "backend", placementMap, func(nodeName string) string { return regions[nodeName] })
+	if len(egress) != 1 {
+		t.Fatalf("expected only the egress of the link to the placed frontend, but got %d links", len(egress))
+	}
+	gibPerHour := float64(kbps) * 1000 / 8 * 3600 / (1024 * 1024 * 1024)
+
+	testCases := []struct {
+		name     string
+		region   string
+		expected float64
+	}{
+		{name: "region with most peers", region: "us", expected: gibPerHour * 0.25},
+		{name: "region with fewer peers", region: "eu", expected: gibPerHour * 0.75},
+		{name: "region without peers", region: "asia", expected: gibPerHour},
+		{name: "unknown region", region: "", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		checkCost(t, tc.name, tc.expected, calcEgressCost(tc.region, egress, args))
+	}
+}
+
+func TestNormalizeCostScores(t *testing.T) {
+	testCases := []struct {
+		name     string
+		costs    []int64
+		expected []int64
+	}{
+		{name: "different costs", costs: []int64{100, 300, 200}, expected: []int64{100, 0, 50}},
+		{name: "free node", costs: []int64{0, 400}, expected: []int64{100, 0}},
+		{name: "equal costs", costs: []int64{0, 0}, expected: []int64{100, 100}},
+	}
+
+	for _, tc := range testCases {
+		scores := make(framework.NodeScoreList, len(tc.costs))
+		for i, cost := range tc.costs {
+			scores[i] = framework.NodeScore{Score: cost}
+		}
+		normalizeCostScores(scores)
+		for i, score := range scores {
+			if score.Score != tc.expected[i] {
+				t.Errorf("%s: expected the scores %v, but got %v", tc.name, tc.expected, scores)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package nodecost
+
+import (
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	defaultCpuWeight                      int64 = 1
+	defaultMemoryWeight                   int64 = 1
+	defaultSpotInterruptionPenaltyPercent int64 = 0
+	defaultEgressCostPerGiB                     = 0.02
+	defaultRegionLabel                          = core.LabelTopologyRegion
+)
+
+// NodeCostArgs configures the NodeCostPlugin.
+//
+// The cost of placing a pod on a node is the share of the node's hourly cost that corresponds to the share of the node's
+// allocatable CPU and memory requested by the pod, plus the hourly cost of the data egress of the pod's ServiceLinks
+// that cross region boundaries. All costs are in the currency of the node cost labels.
+type NodeCostArgs struct {
+	meta.TypeMeta `json:",inline"`
+
+	// The weight of the pod's share of the node's CPU when pro-rating the node's cost.
+	//
+	// Default: 1
+	CpuWeight *int64 `json:"cpuWeight,omitempty"`
+
+	// The weight of the pod's share of the node's memory when pro-rating the node's cost.
+	//
+	// Default: 1
+	MemoryWeight *int64 `json:"memoryWeight,omitempty"`
+
+	// The percentage that is added to the price of spot nodes to account for the risk of the pod being interrupted.
+	// For example, a value of 50 makes a spot node with a price of 0.40 cost 0.60.
+	//
+	// Default: 0
+	SpotInterruptionPenaltyPercent *int64 `json:"spotInterruptionPenaltyPercent,omitempty"`
+
+	// The cost of transferring one GiB of data between two regions.
+	// The data volume of a ServiceLink is estimated from its minimum bandwidth requirement.
+	//
+	// Default: 0.02
+	EgressCostPerGiB *float64 `json:"egressCostPerGiB,omitempty"`
+
+	// The label of a node that contains the node's region.
+	// Nodes without this label are assumed to be in the same region as all other nodes.
+	//
+	// Default: "topology.kubernetes.io/region"
+	RegionLabel *string `json:"regionLabel,omitempty"`
+}
+
+// Decodes the NodeCostArgs from the runtime.Object passed to New(), sets the defaults, and validates them.
+func decodeNodeCostArgs(obj runtime.Object) (*NodeCostArgs, error) {
+	args := &NodeCostArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	setDefaultsNodeCostArgs(args)
+	if err := validateNodeCostArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// AddArgsToScheme registers the NodeCostArgs and their defaults with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &NodeCostArgs{}, func(obj interface{}) {
+		setDefaultsNodeCostArgs(obj.(*NodeCostArgs))
+	})
+}
+
+func setDefaultsNodeCostArgs(args *NodeCostArgs) {
+	setDefaultInt64 := func(value **int64, defaultValue int64) {
+		if *value == nil {
+			v := defaultValue
+			*value = &v
+		}
+	}
+	setDefaultInt64(&args.CpuWeight, defaultCpuWeight)
+	setDefaultInt64(&args.MemoryWeight, defaultMemoryWeight)
+	setDefaultInt64(&args.SpotInterruptionPenaltyPercent, defaultSpotInterruptionPenaltyPercent)
+
+	if args.EgressCostPerGiB == nil {
+		egressCost := defaultEgressCostPerGiB
+		args.EgressCostPerGiB = &egressCost
+	}
+	if args.RegionLabel == nil {
+		regionLabel := defaultRegionLabel
+		args.RegionLabel = &regionLabel
+	}
+}
+
+func validateNodeCostArgs(args *NodeCostArgs) error {
+	if *args.CpuWeight < 0 || *args.MemoryWeight < 0 {
+		return fmt.Errorf("invalid %s args: cpuWeight and memoryWeight must not be negative", PluginName)
+	}
+	if *args.CpuWeight+*args.MemoryWeight == 0 {
+		return fmt.Errorf("invalid %s args: at least one of cpuWeight and memoryWeight must be greater than zero", PluginName)
+	}
+	if *args.SpotInterruptionPenaltyPercent < 0 {
+		return fmt.Errorf("invalid %s args: spotInterruptionPenaltyPercent must not be negative, but is %d", PluginName, *args.SpotInterruptionPenaltyPercent)
+	}
+	if *args.EgressCostPerGiB < 0 {
+		return fmt.Errorf("invalid %s args: egressCostPerGiB must not be negative, but is %v", PluginName, *args.EgressCostPerGiB)
+	}
+	if *args.RegionLabel == "" {
+		return fmt.Errorf("invalid %s args: regionLabel must not be empty", PluginName)
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *NodeCostArgs) DeepCopyInto(out *NodeCostArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+	copyInt64 := func(value *int64) *int64 {
+		if value == nil {
+			return nil
+		}
+		v := *value
+		return &v
+	}
+	out.CpuWeight = copyInt64(me.CpuWeight)
+	out.MemoryWeight = copyInt64(me.MemoryWeight)
+	out.SpotInterruptionPenaltyPercent = copyInt64(me.SpotInterruptionPenaltyPercent)
+	if me.EgressCostPerGiB != nil {
+		egressCost := *me.EgressCostPerGiB
+		out.EgressCostPerGiB = &egressCost
+	}
+	if me.RegionLabel != nil {
+		regionLabel := *me.RegionLabel
+		out.RegionLabel = &regionLabel
+	}
+}
+
+// DeepCopy creates a deep copy of the NodeCostArgs.
+func (me *NodeCostArgs) DeepCopy() *NodeCostArgs {
+	if me == nil {
+		return nil
+	}
+	out := &NodeCostArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the NodeCostArgs as a runtime.Object.
+func (me *NodeCostArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
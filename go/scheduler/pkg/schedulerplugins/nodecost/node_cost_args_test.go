@@ -0,0 +1,44 @@
+package nodecost
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDecodeNodeCostArgs(t *testing.T) {
+	testCases := []struct {
+		name              string
+		obj               runtime.Object
+		expectedCpuWeight int64
+		expectedEgress    float64
+		wantErr           bool
+	}{
+		{name: "nil args", obj: nil, expectedCpuWeight: 1, expectedEgress: 0.02},
+		{name: "unknown args", obj: &runtime.Unknown{Raw: []byte(`{"cpuWeight": 3, "egressCostPerGiB": 0.09}`)}, expectedCpuWeight: 3, expectedEgress: 0.09},
+		{name: "only memory weight", obj: &runtime.Unknown{Raw: []byte(`{"cpuWeight": 0}`)}, expectedCpuWeight: 0, expectedEgress: 0.02},
+		{name: "zero weights", obj: &runtime.Unknown{Raw: []byte(`{"cpuWeight": 0, "memoryWeight": 0}`)}, wantErr: true},
+		{name: "negative weight", obj: &runtime.Unknown{Raw: []byte(`{"memoryWeight": -1}`)}, wantErr: true},
+		{name: "negative spot penalty", obj: &runtime.Unknown{Raw: []byte(`{"spotInterruptionPenaltyPercent": -10}`)}, wantErr: true},
+		{name: "negative egress cost", obj: &runtime.Unknown{Raw: []byte(`{"egressCostPerGiB": -0.01}`)}, wantErr: true},
+		{name: "empty region label", obj: &runtime.Unknown{Raw: []byte(`{"regionLabel": ""}`)}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		args, err := decodeNodeCostArgs(tc.obj)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if *args.CpuWeight != tc.expectedCpuWeight || *args.EgressCostPerGiB != tc.expectedEgress {
+			t.Errorf("%s: expected cpuWeight %d and egressCostPerGiB %v, but got %d and %v",
+				tc.name, tc.expectedCpuWeight, tc.expectedEgress, *args.CpuWeight, *args.EgressCostPerGiB)
+		}
+	}
+}
@@ -15,25 +15,44 @@ import (
 const (
 	// PluginName is the name of this scheduler plugin.
 	PluginName = "NodeCost"
+
+	// The factor, by which the hourly costs are multiplied to obtain the integer scores, which are later normalized.
+	costToScoreFactor = 1000000
 )
 
 var (
 	_nodeCost *NodeCostPlugin
 
 	_ framework.Plugin          = _nodeCost
+	_ framework.PreScorePlugin  = _nodeCost
 	_ framework.ScorePlugin     = _nodeCost
 	_ framework.ScoreExtensions = _nodeCost
 )
 
-// NodeCostPlugin is a Score plugin that provides a higher score for cheaper nodes.
+// NodeCostPlugin is a Score plugin that provides a higher score for nodes, on which the pod is cheaper to run.
+//
+// The cost of a pod on a node consists of:
+//   - the share of the node's hourly cost that corresponds to the pod's share of the node's allocatable CPU and memory.
+//     The hourly cost is read from the node's spot price label (kubeutil.LabelNodeSpotCost), if present, and from its
+//     on-demand price label (kubeutil.LabelNodeCost) otherwise. Nodes without a price are free.
+//   - the hourly cost of the data egress of the pod's ServiceLinks to pods of its ServiceGraph in other regions.
+//
+// The cheapest node receives a score of 100 and the most expensive node a score of 0.
 type NodeCostPlugin struct {
 	handle framework.Handle
+
+	args *NodeCostArgs
 }
 
 // New creates a new NodeCostPlugin instance.
 func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, err := decodeNodeCostArgs(obj)
+	if err != nil {
+		return nil, err
+	}
 	return &NodeCostPlugin{
 		handle: handle,
+		args:   args,
 	}, nil
 }
 
@@ -42,32 +61,88 @@ func (me *NodeCostPlugin) Name() string {
 	return PluginName
 }
 
+// PreScore computes the pod's resource requests and the data egress of its ServiceLinks and stores them in the state.
+func (me *NodeCostPlugin) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodes []*core.Node) *framework.Status {
+	stateData := &nodeCostStateData{
+		podRequests: calcPodRequests(pod),
+	}
+
+	if svcGraphState, err := util.GetServiceGraphFromCycleState(cycleState); err == nil {
+		placementMap, err := svcGraphState.PlacementMap()
+		if err != nil {
+			return framework.AsStatus(err)
+		}
+		podSvcNode, _ := util.GetPodServiceGraphNodeName(pod)
+		stateData.egress = getServiceLinksEgress(svcGraphState.ServiceGraphCRD(), podSvcNode, placementMap, me.getNodeRegion)
+	}
+
+	cycleState.Write(nodeCostStateKey, stateData)
+	return framework.NewStatus(framework.Success)
+}
+
 // ScoreExtensions returns a ScoreExtensions interface if the plugin implements one, or nil if does not.
 func (me *NodeCostPlugin) ScoreExtensions() framework.ScoreExtensions {
 	return me
 }
 
-// Returns higher scores for cheaper nodes.
-func (me *NodeCostPlugin) Score(ctx context.Context, state *framework.CycleState, pod *core.Pod, nodeName string) (int64, *framework.Status) {
+// Score computes the hourly cost of the pod on the node.
+// The costs are converted into scores, where the cheapest node has the highest score, in NormalizeScore.
+func (me *NodeCostPlugin) Score(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) (int64, *framework.Status) {
+	stateData, err := getNodeCostStateData(cycleState)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
 	nodeInfo, err := util.GetNodeByName(me.handle, nodeName)
 	if err != nil {
 		return 0, framework.AsStatus(fmt.Errorf("%s", err))
 	}
 
-	nodeCost := util.GetNodeCost(nodeInfo)
-
-	// When calculating the inverse of the cost we add 1 to the nodeCost to account for nodes with cost = 0
-	inverseCost := 1.0/nodeCost + 1.0
-	score := int64(math.Round(inverseCost * 100))
-
-	return score, framework.NewStatus(framework.Success)
+	cost := calcProRatedNodeCost(nodeInfo, stateData.podRequests, me.args) +
+		calcEgressCost(getNodeRegion(nodeInfo.Node(), *me.args.RegionLabel), stateData.egress, me.args)
+	return int64(math.Round(cost * costToScoreFactor)), framework.NewStatus(framework.Success)
 }
 
-// NormalizeScore normalizes all scores to a range between 0 and 100.
-func (me *NodeCostPlugin) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *core.Pod, scores framework.NodeScoreList) *framework.Status {
-	util.NormalizeNodeScores(scores)
+// NormalizeScore converts the costs computed by Score into scores between 0 (most expensive node) and 100 (cheapest node).
+func (me *NodeCostPlugin) NormalizeScore(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, scores framework.NodeScoreList) *framework.Status {
+	normalizeCostScores(scores)
 	// for _, score := range scores {
 	// 	klog.Infof("Pod %s, node: %s, finalScore: %d", pod.Name, score.Name, score.Score)
 	// }
 	return framework.NewStatus(framework.Success)
 }
+
+// Returns the region of the specified node or an empty string if it is not known.
+func (me *NodeCostPlugin) getNodeRegion(nodeName string) string {
+	nodeInfo, err := me.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return ""
+	}
+	return getNodeRegion(nodeInfo.Node(), *me.args.RegionLabel)
+}
+
+// Converts the costs in the scores linearly into scores between 0 (highest cost) and 100 (lowest cost).
+// If all costs are equal, all nodes receive a score of 100.
+func normalizeCostScores(scores framework.NodeScoreList) {
+	if len(scores) == 0 {
+		return
+	}
+	minCost := scores[0].Score
+	maxCost := scores[0].Score
+	for _, score := range scores {
+		if score.Score < minCost {
+			minCost = score.Score
+		}
+		if score.Score > maxCost {
+			maxCost = score.Score
+		}
+	}
+
+	costRange := float64(maxCost - minCost)
+	for i := range scores {
+		if costRange == 0 {
+			scores[i].Score = framework.MaxNodeScore
+			continue
+		}
+		scores[i].Score = int64(math.Round(float64(maxCost-scores[i].Score) / costRange * float64(framework.MaxNodeScore)))
+	}
+}
@@ -0,0 +1,35 @@
+package nodecost
+
+import (
+	framework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	nodeCostStateKey = "NodeCostPlugin.nodeCostStateData"
+)
+
+var (
+	_ framework.StateData = (*nodeCostStateData)(nil)
+)
+
+type nodeCostStateData struct {
+	// The resources requested by the pod.
+	podRequests *framework.Resource
+
+	// The data egress of the pod's ServiceLinks to already placed ServiceGraphNodes.
+	egress []*serviceLinkEgress
+}
+
+func (me *nodeCostStateData) Clone() framework.StateData {
+	// The state data is never modified after PreScore, so it does not need to be copied.
+	return me
+}
+
+// Gets the nodeCostStateData from the CycleState.
+func getNodeCostStateData(cycleState *framework.CycleState) (*nodeCostStateData, error) {
+	stateData, err := cycleState.Read(nodeCostStateKey)
+	if err != nil {
+		return nil, err
+	}
+	return stateData.(*nodeCostStateData), nil
+}
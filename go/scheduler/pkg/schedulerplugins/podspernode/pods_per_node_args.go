@@ -0,0 +1,76 @@
+package podspernode
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	defaultMoreReplicasPreferred = true
+)
+
+// PodsPerNodeArgs configures the PodsPerNodePlugin.
+type PodsPerNodeArgs struct {
+	meta.TypeMeta `json:",inline"`
+
+	// If true, nodes that can host more replicas of the pod receive higher scores, which increases the colocation
+	// of an application's components. If false, nodes that can host fewer replicas receive higher scores,
+	// which spreads the replicas across more nodes.
+	//
+	// Default: true
+	MoreReplicasPreferred *bool `json:"moreReplicasPreferred,omitempty"`
+}
+
+// Decodes the PodsPerNodeArgs from the runtime.Object passed to New() and sets the defaults.
+func decodePodsPerNodeArgs(obj runtime.Object) (*PodsPerNodeArgs, error) {
+	args := &PodsPerNodeArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	setDefaultsPodsPerNodeArgs(args)
+	return args, nil
+}
+
+// AddArgsToScheme registers the PodsPerNodeArgs and their defaults with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &PodsPerNodeArgs{}, func(obj interface{}) {
+		setDefaultsPodsPerNodeArgs(obj.(*PodsPerNodeArgs))
+	})
+}
+
+func setDefaultsPodsPerNodeArgs(args *PodsPerNodeArgs) {
+	if args.MoreReplicasPreferred == nil {
+		moreReplicasPreferred := defaultMoreReplicasPreferred
+		args.MoreReplicasPreferred = &moreReplicasPreferred
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *PodsPerNodeArgs) DeepCopyInto(out *PodsPerNodeArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+	if me.MoreReplicasPreferred != nil {
+		moreReplicasPreferred := *me.MoreReplicasPreferred
+		out.MoreReplicasPreferred = &moreReplicasPreferred
+	}
+}
+
+// DeepCopy creates a deep copy of the PodsPerNodeArgs.
+func (me *PodsPerNodeArgs) DeepCopy() *PodsPerNodeArgs {
+	if me == nil {
+		return nil
+	}
+	out := &PodsPerNodeArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the PodsPerNodeArgs as a runtime.Object.
+func (me *PodsPerNodeArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package podspernode
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDecodePodsPerNodeArgs(t *testing.T) {
+	fewerReplicasPreferred := false
+	testCases := []struct {
+		name     string
+		obj      runtime.Object
+		expected bool
+	}{
+		{name: "nil args", obj: nil, expected: true},
+		{name: "unknown args", obj: &runtime.Unknown{Raw: []byte(`{"moreReplicasPreferred": false}`)}, expected: false},
+		{name: "typed args", obj: &PodsPerNodeArgs{MoreReplicasPreferred: &fewerReplicasPreferred}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		args, err := decodePodsPerNodeArgs(tc.obj)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if *args.MoreReplicasPreferred != tc.expected {
+			t.Errorf("%s: expected moreReplicasPreferred %v, but got %v", tc.name, tc.expected, *args.MoreReplicasPreferred)
+		}
+	}
+
+	// The typed args must be copied, such that the plugin does not share them with the scheduler configuration.
+	obj := &PodsPerNodeArgs{MoreReplicasPreferred: &fewerReplicasPreferred}
+	args, _ := decodePodsPerNodeArgs(obj)
+	if args.MoreReplicasPreferred == obj.MoreReplicasPreferred {
+		t.Errorf("expected the args to be a deep copy")
+	}
+}
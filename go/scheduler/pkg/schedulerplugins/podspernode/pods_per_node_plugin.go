@@ -33,6 +33,7 @@ var (
 // PodsPerNodePlugin is a Score plugin that increases colocation of an application's components on a node.
 type PodsPerNodePlugin struct {
 	handle framework.Handle
+	args   *PodsPerNodeArgs
 }
 
 type preScoreState struct {
@@ -42,8 +43,13 @@ type preScoreState struct {
 
 // New creates a new PodsPerNodePlugin instance.
 func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, err := decodePodsPerNodeArgs(obj)
+	if err != nil {
+		return nil, err
+	}
 	return &PodsPerNodePlugin{
 		handle: handle,
+		args:   args,
 	}, nil
 }
 
@@ -109,13 +115,12 @@ func (me *PodsPerNodePlugin) Score(ctx context.Context, cycleState *framework.Cy
 	}
 
 	var score int64
-	// ToDo:  Implement configuration of this plugin to allow both modes described in the presentation form 2021-04-20
-	// if me.config.moreReplicasPreferred {
-	score = maxReplicasPerNode
-	// } else {
-	// 	var inverse float64 = 1.0 / float64(maxReplicasPerNode)
-	// 	score = int64(math.Round(inverse * 100))
-	// }
+	if *me.args.MoreReplicasPreferred {
+		score = maxReplicasPerNode
+	} else if maxReplicasPerNode > 0 {
+		var inverse float64 = 1.0 / float64(maxReplicasPerNode)
+		score = int64(math.Round(inverse * 100))
+	}
 
 	return score, framework.NewStatus(framework.Success, fmt.Sprintf("Pod %s, node: %s, maxReplicasPerNode: %d, score: %d", pod.Name, nodeName, maxReplicasPerNode, score))
 }
@@ -0,0 +1,28 @@
+package registry
+
+import (
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/atomicdeployment"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/networkqos"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/nodecost"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/podspernode"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraph"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraphpreemption"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/workloadtype"
+)
+
+// NewPolarisRegistry creates a registry with the factories of all Polaris scheduler plugins.
+// The plugins that need access to the RegionGraph, the ServiceGraphs, or the BandwidthLedger obtain them from the specified services.
+func NewPolarisRegistry(services *pluginservices.PluginServices) frameworkruntime.Registry {
+	return frameworkruntime.Registry{
+		servicegraph.PluginName:           servicegraph.NewFactory(services),
+		networkqos.PluginName:             networkqos.NewFactory(services),
+		podspernode.PluginName:            podspernode.New,
+		nodecost.PluginName:               nodecost.New,
+		workloadtype.PluginName:           workloadtype.New,
+		atomicdeployment.PluginName:       atomicdeployment.New,
+		servicegraphpreemption.PluginName: servicegraphpreemption.NewFactory(services),
+	}
+}
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeschedulerscheme "k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+	kubeschedulerconfigv1beta2 "k8s.io/kubernetes/pkg/scheduler/apis/config/v1beta2"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/atomicdeployment"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/networkqos"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/nodecost"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/podspernode"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraph"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraphpreemption"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/workloadtype"
+)
+
+func init() {
+	// The kube-scheduler decodes the KubeSchedulerConfiguration using its scheme and
+	// defaults and converts the plugin args of a v1beta2 configuration using the plugin arg conversion scheme.
+	AddPluginArgsToScheme(kubeschedulerscheme.Scheme)
+	AddPluginArgsToScheme(kubeschedulerconfigv1beta2.GetPluginArgConversionScheme())
+}
+
+// AddPluginArgsToScheme registers the args types of all Polaris scheduler plugins with the specified scheme.
+//
+// The args are registered for the v1beta2 version of the KubeSchedulerConfiguration API.
+// The args of a v1beta1 KubeSchedulerConfiguration are passed to the plugins undecoded, which then decode, default, and validate them.
+func AddPluginArgsToScheme(scheme *runtime.Scheme) {
+	servicegraph.AddArgsToScheme(scheme)
+	networkqos.AddArgsToScheme(scheme)
+	podspernode.AddArgsToScheme(scheme)
+	nodecost.AddArgsToScheme(scheme)
+	workloadtype.AddArgsToScheme(scheme)
+	atomicdeployment.AddArgsToScheme(scheme)
+	servicegraphpreemption.AddArgsToScheme(scheme)
+}
@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	kubeschedulerscheme "k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/atomicdeployment"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/networkqos"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/podspernode"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraph"
+)
+
+const testSchedulerConfig = `
+apiVersion: kubescheduler.config.k8s.io/v1beta2
+kind: KubeSchedulerConfiguration
+profiles:
+  - schedulerName: polaris-scheduler
+    plugins:
+      preScore:
+        enabled:
+          - name: PodsPerNode
+      permit:
+        enabled:
+          - name: AtomicDeployment
+          - name: ServiceGraph
+    pluginConfig:
+      - name: NetworkQoS
+        args:
+          latencyWeight: 5
+      - name: AtomicDeployment
+        args:
+          waitTimeout: 30s
+`
+
+func decodeSchedulerConfig(t *testing.T, data string) (*kubeschedulerconfig.KubeSchedulerConfiguration, error) {
+	obj, _, err := kubeschedulerscheme.Codecs.UniversalDecoder().Decode([]byte(data), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	schedulerConfig, ok := obj.(*kubeschedulerconfig.KubeSchedulerConfiguration)
+	if !ok {
+		t.Fatalf("expected a KubeSchedulerConfiguration, but got %T", obj)
+	}
+	return schedulerConfig, nil
+}
+
+func TestPluginArgsAreDecodedAndDefaulted(t *testing.T) {
+	schedulerConfig, err := decodeSchedulerConfig(t, testSchedulerConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	args := make(map[string]interface{})
+	for _, pluginConfig := range schedulerConfig.Profiles[0].PluginConfig {
+		args[pluginConfig.Name] = pluginConfig.Args
+	}
+
+	if networkQosArgs, ok := args[networkqos.PluginName].(*networkqos.NetworkQosArgs); !ok {
+		t.Errorf("expected NetworkQosArgs, but got %T", args[networkqos.PluginName])
+	} else if *networkQosArgs.LatencyWeight != 5 || *networkQosArgs.JitterWeight != 1 || *networkQosArgs.UnconnectedNodePolicy != networkqos.RejectUnconnectedNodes {
+		t.Errorf("expected the NetworkQosArgs to be decoded and defaulted, but got %+v", networkQosArgs)
+	}
+
+	if atomicDeploymentArgs, ok := args[atomicdeployment.PluginName].(*atomicdeployment.AtomicDeploymentArgs); !ok {
+		t.Errorf("expected AtomicDeploymentArgs, but got %T", args[atomicdeployment.PluginName])
+	} else if atomicDeploymentArgs.WaitTimeout.Duration != 30*time.Second {
+		t.Errorf("expected a waitTimeout of 30s, but got %v", atomicDeploymentArgs.WaitTimeout.Duration)
+	}
+
+	// The args of enabled plugins without a pluginConfig are added by the kube-scheduler with their defaults.
+	if podsPerNodeArgs, ok := args[podspernode.PluginName].(*podspernode.PodsPerNodeArgs); !ok {
+		t.Errorf("expected PodsPerNodeArgs, but got %T", args[podspernode.PluginName])
+	} else if !*podsPerNodeArgs.MoreReplicasPreferred {
+		t.Errorf("expected moreReplicasPreferred to default to true")
+	}
+	if svcGraphArgs, ok := args[servicegraph.PluginName].(*servicegraph.ServiceGraphArgs); !ok {
+		t.Errorf("expected ServiceGraphArgs, but got %T", args[servicegraph.PluginName])
+	} else if !*svcGraphArgs.LogSchedulingDuration {
+		t.Errorf("expected logSchedulingDuration to default to true")
+	}
+}
+
+func TestUnknownPluginArgsAreRejected(t *testing.T) {
+	config := `
+apiVersion: kubescheduler.config.k8s.io/v1beta2
+kind: KubeSchedulerConfiguration
+profiles:
+  - schedulerName: polaris-scheduler
+    pluginConfig:
+      - name: NodeCost
+        args:
+          unknownParameter: 1
+`
+	if _, err := decodeSchedulerConfig(t, config); err == nil {
+		t.Errorf("expected an error for an unknown NodeCost parameter")
+	}
+}
+
+func TestDefaultSchedulerConfigIsValid(t *testing.T) {
+	data, err := os.ReadFile("../../../manifests/polaris-scheduler/default-polaris-scheduler-config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schedulerConfig, err := decodeSchedulerConfig(t, string(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := validation.ValidateKubeSchedulerConfiguration(schedulerConfig); err != nil {
+		t.Errorf("expected the default scheduler config to be valid, but got %s", err)
+	}
+}
@@ -0,0 +1,74 @@
+package servicegraph
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	defaultLogSchedulingDuration = true
+)
+
+// ServiceGraphArgs configures the ServiceGraphPlugin.
+type ServiceGraphArgs struct {
+	meta.TypeMeta `json:",inline"`
+
+	// If true, the time from the PreFilter phase until the end of the scheduling cycle is logged for every pod of a ServiceGraph.
+	//
+	// Default: true
+	LogSchedulingDuration *bool `json:"logSchedulingDuration,omitempty"`
+}
+
+// Decodes the ServiceGraphArgs from the runtime.Object passed to New() and sets the defaults.
+func decodeServiceGraphArgs(obj runtime.Object) (*ServiceGraphArgs, error) {
+	args := &ServiceGraphArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	setDefaultsServiceGraphArgs(args)
+	return args, nil
+}
+
+// AddArgsToScheme registers the ServiceGraphArgs and their defaults with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &ServiceGraphArgs{}, func(obj interface{}) {
+		setDefaultsServiceGraphArgs(obj.(*ServiceGraphArgs))
+	})
+}
+
+func setDefaultsServiceGraphArgs(args *ServiceGraphArgs) {
+	if args.LogSchedulingDuration == nil {
+		logSchedulingDuration := defaultLogSchedulingDuration
+		args.LogSchedulingDuration = &logSchedulingDuration
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *ServiceGraphArgs) DeepCopyInto(out *ServiceGraphArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+	if me.LogSchedulingDuration != nil {
+		logSchedulingDuration := *me.LogSchedulingDuration
+		out.LogSchedulingDuration = &logSchedulingDuration
+	}
+}
+
+// DeepCopy creates a deep copy of the ServiceGraphArgs.
+func (me *ServiceGraphArgs) DeepCopy() *ServiceGraphArgs {
+	if me == nil {
+		return nil
+	}
+	out := &ServiceGraphArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the ServiceGraphArgs as a runtime.Object.
+func (me *ServiceGraphArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
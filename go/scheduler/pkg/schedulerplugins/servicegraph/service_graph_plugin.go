@@ -10,18 +10,17 @@ import (
 	klog "k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	kubequeuesort "k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
 	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
 	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
 )
 
 const (
 	// PluginName is the name of this scheduler plugin.
 	PluginName = "ServiceGraph"
-
-	// The timeout before releasing a ServiceGraphState.
-	releaseTimeoutStr = "10s"
 )
 
 var (
@@ -40,38 +39,53 @@ var (
 // Specifically, its tasks are:
 //   - QueueSort: Loads the ServiceGraph CRD and sort pods according to their position in the service graph.
 //   - PreFilter: Store the ServiceGraphState in the CycleState.
-//   - PostFilter: Release the ServiceGraphState if no suitable K8s node was found.
+//   - PostFilter: Remove the ServiceGraphState from the CycleState if no suitable K8s node was found.
 //   - Reserve: Record the selected K8s node in the ServiceGraphState.
-//   - Permit: Release the ServiceGraphState.
+//   - Permit: Remove the ServiceGraphState from the CycleState.
 //     Important: Note that the ServiceGraphPlugin should be configured as the last Permit plugin in the scheduler configuration,
-//     because it will release the ServiceGraphState.
+//     because it will remove the ServiceGraphState from the CycleState.
+//
+// The ServiceGraphPlugin also passes the scheduler's shared pod informer to the ServiceGraphManager, which uses it to
+// keep the ServiceGraphStates and their placement maps up to date.
 type ServiceGraphPlugin struct {
 	// The original kube-scheduler sorting plugin, which we use after our ServiceGraph node sorting.
 	origQueueSort *kubequeuesort.PrioritySort
 
-	// The ServiceGraphManager used for creating the ServiceGraphState.
+	// The ServiceGraphManager used for obtaining the ServiceGraphState.
 	svcGraphManager servicegraphmanager.ServiceGraphManager
 
-	// The timeout before releasing a ServiceGraphState.
-	releaseTimeout time.Duration
+	args *ServiceGraphArgs
+}
+
+// NewFactory returns a PluginFactory that creates ServiceGraphPlugin instances, which use the ServiceGraphManager of the specified services.
+func NewFactory(services *pluginservices.PluginServices) frameworkruntime.PluginFactory {
+	return func(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		plugin, err := newServiceGraphPlugin(obj, handle, services.ServiceGraphManager)
+		if err != nil {
+			return nil, err
+		}
+		podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+		if err := services.ServiceGraphManager.WatchPods(podInformer); err != nil {
+			return nil, err
+		}
+		return plugin, nil
+	}
 }
 
-// New creates a new ServiceGraphPlugin instance.
-func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	origQueueSort, err := kubequeuesort.New(obj, handle)
+func newServiceGraphPlugin(obj runtime.Object, handle framework.Handle, svcGraphManager servicegraphmanager.ServiceGraphManager) (*ServiceGraphPlugin, error) {
+	args, err := decodeServiceGraphArgs(obj)
 	if err != nil {
 		return nil, err
 	}
-
-	releaseTimeout, err := time.ParseDuration(releaseTimeoutStr)
+	origQueueSort, err := kubequeuesort.New(nil, handle)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ServiceGraphPlugin{
 		origQueueSort:   origQueueSort.(*kubequeuesort.PrioritySort),
-		svcGraphManager: servicegraphmanager.GetServiceGraphManager(),
-		releaseTimeout:  releaseTimeout,
+		svcGraphManager: svcGraphManager,
+		args:            args,
 	}, nil
 }
 
@@ -86,7 +100,7 @@ func (me *ServiceGraphPlugin) Name() string {
 //
 // Returns true if podA should be scheduled before podB.
 func (me *ServiceGraphPlugin) Less(podA *framework.QueuedPodInfo, podB *framework.QueuedPodInfo) bool {
-	// ToDo: Add an AcquireServiceGraphStateAsync() method to the ServiceGraphManager.
+	// ToDo: Add an GetServiceGraphStateAsync() method to the ServiceGraphManager.
 	// This will allow the fetching to take place in the background if podA and podB are not part of the same serviceGraph.
 	svcGraphState := me.getCommonServiceGraphState(podA, podB)
 	if svcGraphState == nil {
@@ -132,7 +146,7 @@ func (me *ServiceGraphPlugin) PreFilter(ctx context.Context, cycleState *framewo
 	stopwatch := util.NewStopwatch()
 	stopwatch.Start()
 
-	svcGraphState, err := me.svcGraphManager.AcquireServiceGraphState(pod)
+	svcGraphState, err := me.svcGraphManager.GetServiceGraphState(pod)
 	if err != nil {
 		return framework.AsStatus(err)
 	}
@@ -144,7 +158,6 @@ func (me *ServiceGraphPlugin) PreFilter(ctx context.Context, cycleState *framewo
 	svcGraphNodeName, ok := util.GetPodServiceGraphNodeName(pod)
 	if !ok || svcGraphState.ServiceGraph().NodeByLabel(svcGraphNodeName) == nil {
 		// If there is a ServiceGraph, but no valid reference to a node within that ServiceGraph, we signal an error about this pod.
-		svcGraphState.Release(pod)
 		return framework.AsStatus(fmt.Errorf("the pod %s is not associated with a node within its ServiceGraph", pod.Name))
 	}
 
@@ -154,7 +167,8 @@ func (me *ServiceGraphPlugin) PreFilter(ctx context.Context, cycleState *framewo
 }
 
 // PostFilter is called when no suitable K8s node was found during the Filter phase, so we
-// release the ServiceGraphState here.
+// remove the ServiceGraphState from the CycleState here.
+// The ServiceGraphManager keeps the ServiceGraphState alive, because the pod is still pending.
 //
 // A PostFilter plugin should return one of the following statuses:
 // - Unschedulable: the plugin gets executed successfully but the pod cannot be made schedulable.
@@ -166,9 +180,7 @@ func (me *ServiceGraphPlugin) PreFilter(ctx context.Context, cycleState *framewo
 // a preemption plugin may choose to return nominatedNodeName, so that framework can reuse that to update the
 // preemptor pod's .spec.status.nominatedNodeName field.
 func (me *ServiceGraphPlugin) PostFilter(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
-	if svcGraphState, err := util.GetServiceGraphFromCycleState(cycleState); err == nil {
-		me.releaseServiceGraphState(ctx, cycleState, pod, svcGraphState)
-	}
+	util.DeleteServiceGraphFromCycleState(cycleState)
 	return nil, framework.NewStatus(framework.Unschedulable)
 }
 
@@ -179,69 +191,35 @@ func (me *ServiceGraphPlugin) Reserve(ctx context.Context, cycleState *framework
 		return noSvcGraphStatus
 	}
 
-	placementMap, err := svcGraphState.PlacementMap()
-	if err != nil {
-		return framework.NewStatus(framework.Error, err.Error())
-	}
-
-	svcNodeLabel, _ := util.GetPodServiceGraphNodeName(pod)
-	placementMap.SetKubernetesNodes(svcNodeLabel, func(curr []string) []string {
-		// We append the K8s node to a new list, if it is not already present (i.e., another instance of the service has already been scheduled there).
-		newList := make([]string, len(curr), len(curr)+1)
-		k8sNodeAlreadyPresent := false
-		for i, existingNode := range curr {
-			newList[i] = existingNode
-			k8sNodeAlreadyPresent = k8sNodeAlreadyPresent || existingNode == nodeName
-		}
-		if !k8sNodeAlreadyPresent {
-			newList = append(newList, nodeName)
-		}
-		return newList
-	})
+	svcGraphState.ReservePod(pod, nodeName)
 
 	klog.Infof("Reserve success: pod %s on node %s", pod.Name, nodeName)
 
 	return framework.NewStatus(framework.Success)
 }
 
-// Unreserve removes the Kubernetes node from the node in the ServiceGraph and releases its ServiceGraphState.
+// Unreserve removes the reservation of the pod from the placement map of its ServiceGraph and removes the ServiceGraphState from the CycleState.
+// Since Permit() has already removed the ServiceGraphState from the CycleState if a later stage fails,
+// the ServiceGraphState is looked up from the ServiceGraphManager without tracking the pod, because the pod may already have been deleted.
 // This method must not fail
 func (me *ServiceGraphPlugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) {
-	svcGraphState, err := util.GetServiceGraphFromCycleState(cycleState)
-	if err != nil {
+	defer util.DeleteServiceGraphFromCycleState(cycleState)
+
+	svcGraphState, err := me.svcGraphManager.LookupServiceGraphState(pod)
+	if err != nil || svcGraphState == nil {
 		return
 	}
-
-	defer me.releaseServiceGraphState(ctx, cycleState, pod, svcGraphState)
-
-	// ToDo: Change PlacementMap to track number of pods placed on a K8s node.
-	// Otherwise, we might remove the node from the list, even though an existing pod is running on it.
-	//
-	// klog.Infof("ServiceGraph.Unreserve(%s) on  node %s", pod.Name, nodeName)
-	// placementMap, err := svcGraphState.PlacementMap()
-	// if err != nil {
-	// 	return
-	// }
-	// svcNodeLabel, _ := util.GetPodServiceGraphNodeName(pod)
-	// placementMap.SetKubernetesNodes(svcNodeLabel, func(curr []string) []string {
-	// 	newList := make([]string, len(curr) - 1)
-	// 	indexNew := 0
-	// 	for _, k8sNode := range curr {
-	// 		if k8sNode != nodeName {
-	// 			newList[indexNew] = k8sNode
-	// 			indexNew++
-	// 		}
-	// 	}
-	// 	return newList
-	// })
+	svcGraphState.UnreservePod(pod)
+	klog.Infof("Unreserve: pod %s on node %s", pod.Name, nodeName)
 }
 
-// Permit releases the ServiceGraphState. It is important that the ServiceGraph plugin
-// is the last Permit plugin, because it will release the ServiceGraphState.
+// Permit removes the ServiceGraphState from the CycleState. It is important that the ServiceGraph plugin
+// is the last Permit plugin, because the ServiceGraphState is not available to the subsequent Permit plugins.
 //
 // If the AtomicDeployment plugin decides that the pod needs to wait, we still need
 // to remove the ServiceGraphState, because Permit() will not be called again. Instead the
 // waiting pod must be approved by the Permit() stage of another pod.
+// The ServiceGraphManager keeps the ServiceGraphState alive while the pod is waiting and until its binding has been observed.
 //
 // Permit is called before binding a pod (and before prebind plugins). Permit
 // plugins are used to prevent or delay the binding of a Pod. A permit plugin
@@ -250,35 +228,12 @@ func (me *ServiceGraphPlugin) Unreserve(ctx context.Context, cycleState *framewo
 // waiting. Note that if the plugin returns "wait", the framework will wait only
 // after running the remaining plugins given that no other plugin rejects the pod.
 func (me *ServiceGraphPlugin) Permit(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) (*framework.Status, time.Duration) {
-	if svcGraphState, err := util.GetServiceGraphFromCycleState(cycleState); err == nil {
-		me.releaseServiceGraphState(ctx, cycleState, pod, svcGraphState)
-	}
-
+	util.DeleteServiceGraphFromCycleState(cycleState)
 	me.readStopwatch(cycleState, pod)
 
 	return framework.NewStatus(framework.Success), 0
 }
 
-func (me *ServiceGraphPlugin) releaseServiceGraphState(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, svcGraphState servicegraphmanager.ServiceGraphState) {
-	util.DeleteServiceGraphFromCycleState(cycleState)
-
-	// If this is the initial placement of of this service graph, we need to wait a while
-	// before releasing the ServiceGraphState to help avoid a race condition with other pods of the same service graph.
-	// In our tests we often saw that the first pod of a new service graph went through the entire scheduling cycle
-	// and released its ServiceGraphState s1, before the second pod was able to acquire the ServiceGraphState, resulting in the deletion of s1.
-	// Thus, the second pod created a new ServiceGraphState s2, which did not have the placement info about the first pod, resulting in
-	// none of the pods being admitted by the AtomicDeployment plugin.
-	// I know that sleeping is not a synchronization primitive, but it is an acceptable solution for now.
-	placementMap, _ := svcGraphState.PlacementMap()
-	if placementMap.IsInitialPlacement() {
-		time.AfterFunc(me.releaseTimeout, func() {
-			svcGraphState.Release(pod)
-		})
-	} else {
-		svcGraphState.Release(pod)
-	}
-}
-
 // Gets the ServiceGraphState for the two pods, if both are associated with the same ServiceGraph and both have a ServiceGraphNode reference, otherwise it returns nil.
 func (me *ServiceGraphPlugin) getCommonServiceGraphState(podA *framework.QueuedPodInfo, podB *framework.QueuedPodInfo) servicegraphmanager.ServiceGraphState {
 	if podA.Pod.Namespace != podB.Pod.Namespace {
@@ -299,17 +254,20 @@ func (me *ServiceGraphPlugin) getCommonServiceGraphState(podA *framework.QueuedP
 		return nil
 	}
 
-	// It does not matter which pod we use to acquire the ServiceGraphState, because they will share the same one
-	// and the second pod will be added to the reference count in the PreFilter stage.
-	svcGraphState, err := me.svcGraphManager.AcquireServiceGraphState(podA.Pod)
+	// It does not matter which pod we use to get the ServiceGraphState, because they share the same one.
+	// The pods in the scheduling queue may be outdated, so they must not keep the ServiceGraphState alive.
+	svcGraphState, err := me.svcGraphManager.LookupServiceGraphState(podA.Pod)
 	if err != nil {
-		klog.Errorf("Could not acquire ServiceGraph for pod %s.%s Error: %s", podA.Pod.Namespace, podA.Pod.Name, err)
+		klog.Errorf("Could not get ServiceGraph for pod %s.%s Error: %s", podA.Pod.Namespace, podA.Pod.Name, err)
 		return nil
 	}
 	return svcGraphState
 }
 
 func (me *ServiceGraphPlugin) readStopwatch(cycleState *framework.CycleState, pod *core.Pod) {
+	if !*me.args.LogSchedulingDuration {
+		return
+	}
 	if stateData, err := cycleState.Read(util.StopwatchStateKey); err == nil {
 		stopwatch := stateData.(*util.Stopwatch)
 		if !stopwatch.IsStopped() {
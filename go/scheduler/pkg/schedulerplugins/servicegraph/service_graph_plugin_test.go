@@ -0,0 +1,211 @@
+package servicegraph
+
+import (
+	"context"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	testNamespace    = "test"
+	testSvcGraphName = "test-app"
+)
+
+func newTestServiceGraph() *fogappsCRDs.ServiceGraph {
+	return &fogappsCRDs.ServiceGraph{
+		ObjectMeta: meta.ObjectMeta{Name: testSvcGraphName, Namespace: testNamespace},
+		Spec: fogappsCRDs.ServiceGraphSpec{
+			Nodes: []fogappsCRDs.ServiceGraphNode{
+				{Name: "frontend", NodeType: fogappsCRDs.ServiceNode},
+				{Name: "backend", NodeType: fogappsCRDs.ServiceNode},
+			},
+			Links: []fogappsCRDs.ServiceLink{
+				{Source: "frontend", Target: "backend"},
+			},
+		},
+	}
+}
+
+func newTestPod(name string, labels map[string]string, nodeName string) *core.Pod {
+	return &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+		Spec:       core.PodSpec{NodeName: nodeName},
+	}
+}
+
+func newServiceGraphPodLabels(svcGraphNode string) map[string]string {
+	return map[string]string{
+		kubeutil.LabelRefServiceGraph:     testSvcGraphName,
+		kubeutil.LabelRefServiceGraphNode: svcGraphNode,
+	}
+}
+
+func newTestPlugin(t *testing.T, svcGraphManager servicegraphmanager.ServiceGraphManager) *ServiceGraphPlugin {
+	plugin, err := newServiceGraphPlugin(nil, nil, svcGraphManager)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return plugin
+}
+
+func TestPreFilter(t *testing.T) {
+	svcGraphManager := servicegraphmanager.NewFakeServiceGraphManager()
+	svcGraphManager.AddServiceGraph(newTestServiceGraph())
+	plugin := newTestPlugin(t, svcGraphManager)
+
+	testCases := []struct {
+		name             string
+		pod              *core.Pod
+		expectedCode     framework.Code
+		expectedSvcGraph bool
+	}{
+		{name: "no ServiceGraph", pod: newTestPod("pod-a", nil, ""), expectedCode: framework.Success},
+		{name: "valid ServiceGraphNode", pod: newTestPod("pod-b", newServiceGraphPodLabels("frontend"), ""), expectedCode: framework.Success, expectedSvcGraph: true},
+		{name: "unknown ServiceGraphNode", pod: newTestPod("pod-c", newServiceGraphPodLabels("database"), ""), expectedCode: framework.Error},
+		{
+			name:         "unknown ServiceGraph",
+			pod:          newTestPod("pod-d", map[string]string{kubeutil.LabelRefServiceGraph: "unknown-app"}, ""),
+			expectedCode: framework.Error,
+		},
+	}
+
+	for _, tc := range testCases {
+		cycleState := framework.NewCycleState()
+		status := plugin.PreFilter(context.Background(), cycleState, tc.pod)
+		if status.Code() != tc.expectedCode {
+			t.Errorf("%s: expected status code %v, but got %v (%s)", tc.name, tc.expectedCode, status.Code(), status.Message())
+		}
+		if _, err := util.GetServiceGraphFromCycleState(cycleState); (err == nil) != tc.expectedSvcGraph {
+			t.Errorf("%s: expected the ServiceGraphState to be in the CycleState: %v", tc.name, tc.expectedSvcGraph)
+		}
+	}
+}
+
+func TestReserveRecordsNodeInPlacementMap(t *testing.T) {
+	svcGraphManager := servicegraphmanager.NewFakeServiceGraphManager()
+	svcGraphState := svcGraphManager.AddServiceGraph(
+		newTestServiceGraph(),
+		*newTestPod("frontend-0", newServiceGraphPodLabels("frontend"), "node-1"),
+	)
+	plugin := newTestPlugin(t, svcGraphManager)
+
+	reservations := []struct {
+		pod      *core.Pod
+		nodeName string
+	}{
+		{pod: newTestPod("backend-0", newServiceGraphPodLabels("backend"), ""), nodeName: "node-2"},
+		{pod: newTestPod("backend-1", newServiceGraphPodLabels("backend"), ""), nodeName: "node-2"},
+		{pod: newTestPod("frontend-1", newServiceGraphPodLabels("frontend"), ""), nodeName: "node-3"},
+	}
+	for _, r := range reservations {
+		cycleState := framework.NewCycleState()
+		if status := plugin.PreFilter(context.Background(), cycleState, r.pod); !status.IsSuccess() {
+			t.Fatalf("%s: PreFilter failed: %s", r.pod.Name, status.Message())
+		}
+		if status := plugin.Reserve(context.Background(), cycleState, r.pod, r.nodeName); !status.IsSuccess() {
+			t.Fatalf("%s: Reserve failed: %s", r.pod.Name, status.Message())
+		}
+		plugin.Permit(context.Background(), cycleState, r.pod, r.nodeName)
+		if _, err := util.GetServiceGraphFromCycleState(cycleState); err == nil {
+			t.Errorf("%s: expected Permit to remove the ServiceGraphState from the CycleState", r.pod.Name)
+		}
+	}
+
+	placementMap, err := svcGraphState.PlacementMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if placementMap.IsInitialPlacement() {
+		t.Errorf("expected the placement not to be the initial one, because a pod had already been placed")
+	}
+	expectedPlacements := map[string][]string{
+		"frontend": {"node-1", "node-3"},
+		"backend":  {"node-2"},
+	}
+	for svcGraphNode, expected := range expectedPlacements {
+		actual := placementMap.GetKubernetesNodes(svcGraphNode)
+		if len(actual) != len(expected) {
+			t.Errorf("%s: expected the K8s nodes %v, but got %v", svcGraphNode, expected, actual)
+			continue
+		}
+		for i := range expected {
+			if actual[i] != expected[i] {
+				t.Errorf("%s: expected the K8s nodes %v, but got %v", svcGraphNode, expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestPostFilterReleasesServiceGraphState(t *testing.T) {
+	svcGraphManager := servicegraphmanager.NewFakeServiceGraphManager()
+	svcGraphManager.AddServiceGraph(newTestServiceGraph(), *newTestPod("frontend-0", newServiceGraphPodLabels("frontend"), "node-1"))
+	plugin := newTestPlugin(t, svcGraphManager)
+
+	pod := newTestPod("backend-0", newServiceGraphPodLabels("backend"), "")
+	cycleState := framework.NewCycleState()
+	if status := plugin.PreFilter(context.Background(), cycleState, pod); !status.IsSuccess() {
+		t.Fatalf("PreFilter failed: %s", status.Message())
+	}
+
+	_, status := plugin.PostFilter(context.Background(), cycleState, pod, framework.NodeToStatusMap{})
+	if status.Code() != framework.Unschedulable {
+		t.Errorf("expected status code %v, but got %v", framework.Unschedulable, status.Code())
+	}
+	if _, err := util.GetServiceGraphFromCycleState(cycleState); err == nil {
+		t.Errorf("expected PostFilter to remove the ServiceGraphState from the CycleState")
+	}
+}
+
+func TestUnreserveRemovesReservationFromPlacementMap(t *testing.T) {
+	svcGraphManager := servicegraphmanager.NewFakeServiceGraphManager()
+	svcGraphState := svcGraphManager.AddServiceGraph(
+		newTestServiceGraph(),
+		*newTestPod("backend-0", newServiceGraphPodLabels("backend"), "node-1"),
+	)
+	plugin := newTestPlugin(t, svcGraphManager)
+
+	pods := []*core.Pod{
+		newTestPod("backend-1", newServiceGraphPodLabels("backend"), ""),
+		newTestPod("backend-2", newServiceGraphPodLabels("backend"), ""),
+	}
+	for _, pod := range pods {
+		cycleState := framework.NewCycleState()
+		if status := plugin.PreFilter(context.Background(), cycleState, pod); !status.IsSuccess() {
+			t.Fatalf("%s: PreFilter failed: %s", pod.Name, status.Message())
+		}
+		if status := plugin.Reserve(context.Background(), cycleState, pod, "node-1"); !status.IsSuccess() {
+			t.Fatalf("%s: Reserve failed: %s", pod.Name, status.Message())
+		}
+		plugin.Permit(context.Background(), cycleState, pod, "node-1")
+	}
+
+	placementMap, _ := svcGraphState.PlacementMap()
+	if counts := placementMap.GetPodCounts("backend", "node-1"); counts.Bound != 1 || counts.Assumed != 2 {
+		t.Errorf("expected 1 bound and 2 assumed pods, but got %+v", counts)
+	}
+
+	// Unreserve is called with a CycleState that does not contain the ServiceGraphState anymore.
+	plugin.Unreserve(context.Background(), framework.NewCycleState(), pods[0], "node-1")
+	plugin.Unreserve(context.Background(), framework.NewCycleState(), pods[1], "node-1")
+	if counts := placementMap.GetPodCounts("backend", "node-1"); counts.Bound != 1 || counts.Assumed != 0 {
+		t.Errorf("expected 1 bound and 0 assumed pods, but got %+v", counts)
+	}
+	if nodes := placementMap.GetKubernetesNodes("backend"); len(nodes) != 1 || nodes[0] != "node-1" {
+		t.Errorf("expected the bound pod to keep node-1 in the placement map, but got %v", nodes)
+	}
+
+	// Unreserving a pod that has not been reserved has no effect.
+	plugin.Unreserve(context.Background(), framework.NewCycleState(), newTestPod("frontend-0", newServiceGraphPodLabels("frontend"), ""), "node-2")
+	if nodes := placementMap.GetKubernetesNodes("frontend"); len(nodes) != 0 {
+		t.Errorf("expected no K8s nodes for frontend, but got %v", nodes)
+	}
+}
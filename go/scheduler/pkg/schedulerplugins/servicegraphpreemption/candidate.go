@@ -0,0 +1,117 @@
+package servicegraphpreemption
+
+import (
+	"sort"
+
+	core "k8s.io/api/core/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+)
+
+var (
+	_candidate *candidate
+
+	_ defaultpreemption.Candidate = _candidate
+)
+
+// A node, on which the preemptor pod can be scheduled if the victims are evicted.
+type candidate struct {
+	nodeName string
+
+	// The pods that need to be evicted from the node.
+	victims []*core.Pod
+
+	// The number of ServiceGraphs, whose Replicas.Min would be violated by evicting the victims.
+	violatedSvcGraphs int
+
+	// The highest ServiceGraph priority among the victims.
+	maxVictimPriority int32
+
+	// The sum of the pod priorities of the victims.
+	victimPrioritiesSum int64
+}
+
+func newCandidate(nodeName string, victims []*core.Pod, replicas *serviceGraphReplicas) *candidate {
+	c := &candidate{
+		nodeName:          nodeName,
+		victims:           victims,
+		violatedSvcGraphs: replicas.countViolatedServiceGraphs(victims),
+	}
+	for i, victim := range victims {
+		if priority := replicas.getPriority(victim); i == 0 || priority > c.maxVictimPriority {
+			c.maxVictimPriority = priority
+		}
+		c.victimPrioritiesSum += int64(getPodPriority(victim))
+	}
+	return c
+}
+
+// Victims returns the pods that need to be evicted.
+// Since PodDisruptionBudgets are not considered by the ServiceGraphPreemptionPlugin, NumPDBViolations is always 0.
+func (me *candidate) Victims() *extenderv1.Victims {
+	return &extenderv1.Victims{
+		Pods: me.victims,
+	}
+}
+
+// Name returns the name of the candidate node.
+func (me *candidate) Name() string {
+	return me.nodeName
+}
+
+// Returns true if the candidate me is better suited for preemption than other.
+//
+// Candidates are compared by the following criteria, in this order:
+//  1. fewer ServiceGraphs, whose Replicas.Min would be violated
+//  2. lower highest ServiceGraph priority among the victims
+//  3. fewer victims
+//  4. lower sum of the victims' pod priorities
+//  5. node name (to make the selection deterministic)
+func (me *candidate) isBetterThan(other *candidate) bool {
+	if me.violatedSvcGraphs != other.violatedSvcGraphs {
+		return me.violatedSvcGraphs < other.violatedSvcGraphs
+	}
+	if me.maxVictimPriority != other.maxVictimPriority {
+		return me.maxVictimPriority < other.maxVictimPriority
+	}
+	if len(me.victims) != len(other.victims) {
+		return len(me.victims) < len(other.victims)
+	}
+	if me.victimPrioritiesSum != other.victimPrioritiesSum {
+		return me.victimPrioritiesSum < other.victimPrioritiesSum
+	}
+	return me.nodeName < other.nodeName
+}
+
+// Returns the best candidate or nil, if there are no candidates.
+func selectBestCandidate(candidates []*candidate) *candidate {
+	var best *candidate
+	for _, c := range candidates {
+		if best == nil || c.isBetterThan(best) {
+			best = c
+		}
+	}
+	return best
+}
+
+// Sorts the potential victims, such that the pods that should be evicted last come first.
+//
+// The pods, whose eviction would violate the Replicas.Min of their ServiceGraphNode, come first, followed
+// by the pods of ServiceGraphs with higher priorities. Pods with equal ServiceGraph priorities are ordered
+// by their own importance.
+func sortPotentialVictims(victims []*core.Pod, replicas *serviceGraphReplicas) {
+	sort.SliceStable(victims, func(i, j int) bool {
+		violatesI := replicas.wouldViolateMinReplicas(victims[i])
+		violatesJ := replicas.wouldViolateMinReplicas(victims[j])
+		if violatesI != violatesJ {
+			return violatesI
+		}
+		priorityI := replicas.getPriority(victims[i])
+		priorityJ := replicas.getPriority(victims[j])
+		if priorityI != priorityJ {
+			return priorityI > priorityJ
+		}
+		return schedutil.MoreImportantPod(victims[i], victims[j])
+	})
+}
@@ -0,0 +1,179 @@
+package servicegraphpreemption
+
+import (
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+)
+
+var testStartTime = meta.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestPod(name string, svcGraphName string, svcGraphNode string, priority int32) *core.Pod {
+	pod := &core.Pod{}
+	pod.Namespace = "test"
+	pod.Name = name
+	pod.Spec.Priority = &priority
+	pod.Status.StartTime = &testStartTime
+	if svcGraphName != "" {
+		pod.Labels = map[string]string{
+			kubeutil.LabelRefServiceGraph:     svcGraphName,
+			kubeutil.LabelRefServiceGraphNode: svcGraphNode,
+		}
+	}
+	return pod
+}
+
+func newTestServiceGraph(name string, minReplicas map[string]int32) *fogappsCRDs.ServiceGraph {
+	svcGraph := &fogappsCRDs.ServiceGraph{}
+	svcGraph.Namespace = "test"
+	svcGraph.Name = name
+	for svcNode, min := range minReplicas {
+		svcGraph.Spec.Nodes = append(svcGraph.Spec.Nodes, fogappsCRDs.ServiceGraphNode{
+			Name:     svcNode,
+			NodeType: fogappsCRDs.ServiceNode,
+			Replicas: fogappsCRDs.ReplicasConfig{Min: min},
+		})
+	}
+	return svcGraph
+}
+
+// Creates a serviceGraphReplicas with the following pods:
+//   - critical: 2 replicas of "backend" (Replicas.Min = 2) with priorities 100 and 10
+//   - relaxed: 2 replicas of "backend" (Replicas.Min = 1) with priority 50
+//   - a standalone pod with priority 20
+func newTestReplicas() (*serviceGraphReplicas, map[string]*core.Pod) {
+	pods := map[string]*core.Pod{
+		"critical-0": newTestPod("critical-0", "critical", "backend", 100),
+		"critical-1": newTestPod("critical-1", "critical", "backend", 10),
+		"relaxed-0":  newTestPod("relaxed-0", "relaxed", "backend", 50),
+		"relaxed-1":  newTestPod("relaxed-1", "relaxed", "backend", 50),
+		"standalone": newTestPod("standalone", "", "", 20),
+	}
+	svcGraphs := map[string]*fogappsCRDs.ServiceGraph{
+		"critical": newTestServiceGraph("critical", map[string]int32{"backend": 2}),
+		"relaxed":  newTestServiceGraph("relaxed", map[string]int32{"backend": 1}),
+	}
+
+	nodeInfo := framework.NewNodeInfo()
+	for _, pod := range pods {
+		nodeInfo.AddPod(pod)
+	}
+	replicas := newServiceGraphReplicas([]*framework.NodeInfo{nodeInfo}, func(namespace, name string) *fogappsCRDs.ServiceGraph {
+		return svcGraphs[name]
+	})
+	return replicas, pods
+}
+
+func TestCountViolatedServiceGraphs(t *testing.T) {
+	replicas, pods := newTestReplicas()
+
+	testCases := []struct {
+		name     string
+		victims  []string
+		expected int
+	}{
+		{name: "no victims", expected: 0},
+		{name: "standalone pod", victims: []string{"standalone"}, expected: 0},
+		{name: "replica above the minimum", victims: []string{"relaxed-0"}, expected: 0},
+		{name: "all replicas", victims: []string{"relaxed-0", "relaxed-1"}, expected: 1},
+		{name: "replica at the minimum", victims: []string{"critical-1"}, expected: 1},
+		{name: "both ServiceGraphs", victims: []string{"critical-0", "critical-1", "relaxed-0", "relaxed-1"}, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		victims := make([]*core.Pod, len(tc.victims))
+		for i, name := range tc.victims {
+			victims[i] = pods[name]
+		}
+		if actual := replicas.countViolatedServiceGraphs(victims); actual != tc.expected {
+			t.Errorf("%s: expected %d, but got %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestServiceGraphPriority(t *testing.T) {
+	replicas, pods := newTestReplicas()
+
+	testCases := []struct {
+		pod      string
+		expected int32
+	}{
+		{pod: "critical-1", expected: 100},
+		{pod: "relaxed-0", expected: 50},
+		{pod: "standalone", expected: 20},
+	}
+
+	for _, tc := range testCases {
+		if actual := replicas.getPriority(pods[tc.pod]); actual != tc.expected {
+			t.Errorf("%s: expected priority %d, but got %d", tc.pod, tc.expected, actual)
+		}
+	}
+}
+
+func TestSortPotentialVictims(t *testing.T) {
+	replicas, pods := newTestReplicas()
+	victims := []*core.Pod{pods["standalone"], pods["relaxed-0"], pods["critical-1"], pods["relaxed-1"]}
+	expected := []string{"critical-1", "relaxed-0", "relaxed-1", "standalone"}
+
+	sortPotentialVictims(victims, replicas)
+	for i, victim := range victims {
+		if victim.Name != expected[i] {
+			t.Errorf("expected the order %v, but %s is at index %d", expected, victim.Name, i)
+		}
+	}
+}
+
+func TestSelectBestCandidate(t *testing.T) {
+	replicas, pods := newTestReplicas()
+
+	testCases := []struct {
+		name       string
+		candidates map[string][]string
+		expected   string
+	}{
+		{
+			name:       "fewer violated ServiceGraphs",
+			candidates: map[string][]string{"node-a": {"critical-1"}, "node-b": {"relaxed-0", "standalone"}},
+			expected:   "node-b",
+		},
+		{
+			name:       "lower ServiceGraph priority",
+			candidates: map[string][]string{"node-a": {"relaxed-0"}, "node-b": {"standalone"}},
+			expected:   "node-b",
+		},
+		{
+			name:       "fewer victims",
+			candidates: map[string][]string{"node-a": {"relaxed-0", "standalone"}, "node-b": {"relaxed-1"}},
+			expected:   "node-b",
+		},
+		{
+			name:       "node name",
+			candidates: map[string][]string{"node-b": {"relaxed-0"}, "node-a": {"relaxed-1"}},
+			expected:   "node-a",
+		},
+	}
+
+	for _, tc := range testCases {
+		candidates := make([]*candidate, 0, len(tc.candidates))
+		for nodeName, victimNames := range tc.candidates {
+			victims := make([]*core.Pod, len(victimNames))
+			for i, name := range victimNames {
+				victims[i] = pods[name]
+			}
+			candidates = append(candidates, newCandidate(nodeName, victims, replicas))
+		}
+		if best := selectBestCandidate(candidates); best == nil || best.Name() != tc.expected {
+			t.Errorf("%s: expected %s to be selected, but got %+v", tc.name, tc.expected, best)
+		}
+	}
+
+	if best := selectBestCandidate(nil); best != nil {
+		t.Errorf("expected no candidate to be selected, but got %+v", best)
+	}
+}
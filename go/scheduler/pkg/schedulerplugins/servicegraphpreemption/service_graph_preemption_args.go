@@ -0,0 +1,54 @@
+package servicegraphpreemption
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+// ServiceGraphPreemptionArgs configures the ServiceGraphPreemptionPlugin.
+//
+// The ServiceGraphPreemptionPlugin does not have any parameters yet. The ServiceGraphPreemptionArgs are registered nevertheless,
+// such that unknown parameters in a v1beta2 KubeSchedulerConfiguration are rejected when the scheduler starts.
+type ServiceGraphPreemptionArgs struct {
+	meta.TypeMeta `json:",inline"`
+}
+
+// Decodes the ServiceGraphPreemptionArgs from the runtime.Object passed to New().
+func decodeServiceGraphPreemptionArgs(obj runtime.Object) (*ServiceGraphPreemptionArgs, error) {
+	args := &ServiceGraphPreemptionArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// AddArgsToScheme registers the ServiceGraphPreemptionArgs with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &ServiceGraphPreemptionArgs{}, func(obj interface{}) {})
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *ServiceGraphPreemptionArgs) DeepCopyInto(out *ServiceGraphPreemptionArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+}
+
+// DeepCopy creates a deep copy of the ServiceGraphPreemptionArgs.
+func (me *ServiceGraphPreemptionArgs) DeepCopy() *ServiceGraphPreemptionArgs {
+	if me == nil {
+		return nil
+	}
+	out := &ServiceGraphPreemptionArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the ServiceGraphPreemptionArgs as a runtime.Object.
+func (me *ServiceGraphPreemptionArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
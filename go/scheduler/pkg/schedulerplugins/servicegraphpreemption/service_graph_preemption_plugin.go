@@ -0,0 +1,183 @@
+package servicegraphpreemption
+
+import (
+	"context"
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	klog "k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
+)
+
+const (
+	// PluginName is the name of this scheduler plugin.
+	PluginName = "ServiceGraphPreemption"
+)
+
+var (
+	_svcGraphPreemptionPlugin *ServiceGraphPreemptionPlugin
+
+	_ framework.Plugin           = _svcGraphPreemptionPlugin
+	_ framework.PostFilterPlugin = _svcGraphPreemptionPlugin
+)
+
+// ServiceGraphPreemptionPlugin is a PostFilter plugin that preempts lower priority pods to make room for a pod that
+// could not be scheduled, while respecting the structure of the ServiceGraphs, to which the victims belong.
+//
+// Like the kube-scheduler's DefaultPreemption plugin, it only considers pods with a lower priority than the preemptor
+// as victims and tries to reprieve as many of them as possible. However, the victims are chosen, such that:
+//   - the number of ServiceGraphs, whose Replicas.Min would be violated by the preemption, is minimized and
+//   - pods of ServiceGraphs with lower priorities are preferred. The priority of a ServiceGraph is the
+//     highest priority of its pods.
+//
+// The node with the best set of victims is nominated for the preemptor pod.
+// PodDisruptionBudgets and scheduler extenders are not considered, so this plugin should replace the DefaultPreemption plugin.
+// Since the ServiceGraphPlugin removes the ServiceGraphState from the CycleState in its PostFilter phase, the
+// ServiceGraphPreemptionPlugin should be configured before it.
+type ServiceGraphPreemptionPlugin struct {
+	args *ServiceGraphPreemptionArgs
+
+	frameworkHandle framework.Handle
+
+	// The ServiceGraphManager used for obtaining the ServiceGraph CRDs of the victims.
+	svcGraphManager servicegraphmanager.ServiceGraphManager
+}
+
+// NewFactory returns a PluginFactory that creates ServiceGraphPreemptionPlugin instances, which use the ServiceGraphManager of the specified services.
+func NewFactory(services *pluginservices.PluginServices) frameworkruntime.PluginFactory {
+	return func(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		args, err := decodeServiceGraphPreemptionArgs(obj)
+		if err != nil {
+			return nil, err
+		}
+		plugin := &ServiceGraphPreemptionPlugin{
+			args:            args,
+			frameworkHandle: handle,
+			svcGraphManager: services.ServiceGraphManager,
+		}
+		return plugin, nil
+	}
+}
+
+// Name returns the name of this scheduler plugin.
+func (me *ServiceGraphPreemptionPlugin) Name() string {
+	return PluginName
+}
+
+// PostFilter is called when no suitable K8s node was found during the Filter phase.
+// It selects the node, on which the preemption of lower priority pods is least harmful to their ServiceGraphs,
+// evicts the victims, and nominates the node for the pod.
+func (me *ServiceGraphPreemptionPlugin) PostFilter(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	nodeInfoLister := me.frameworkHandle.SnapshotSharedLister().NodeInfos()
+	if !defaultpreemption.PodEligibleToPreemptOthers(pod, nodeInfoLister, filteredNodeStatusMap[pod.Status.NominatedNodeName]) {
+		return nil, framework.NewStatus(framework.Unschedulable, "the pod is not eligible for preemption")
+	}
+
+	nodeInfos, err := nodeInfoLister.List()
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	replicas := newServiceGraphReplicas(nodeInfos, me.svcGraphManager.GetServiceGraphCRD)
+
+	candidates := make([]*candidate, 0)
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Node() == nil {
+			continue
+		}
+		if status, ok := filteredNodeStatusMap[nodeInfo.Node().Name]; ok && status.Code() == framework.UnschedulableAndUnresolvable {
+			// Preemption cannot help on nodes that have been rejected due to reasons that cannot be resolved by evicting pods.
+			continue
+		}
+
+		victims, status := me.selectVictimsOnNode(ctx, cycleState.Clone(), pod, nodeInfo.Clone(), replicas)
+		if status.Code() == framework.Error {
+			return nil, status
+		}
+		if status.IsSuccess() && len(victims) > 0 {
+			candidates = append(candidates, newCandidate(nodeInfo.Node().Name, victims, replicas))
+		}
+	}
+
+	best := selectBestCandidate(candidates)
+	if best == nil {
+		return nil, framework.NewStatus(framework.Unschedulable, "preemption cannot make the pod schedulable on any node")
+	}
+	if status := defaultpreemption.PrepareCandidate(best, me.frameworkHandle, me.frameworkHandle.ClientSet(), pod, PluginName); !status.IsSuccess() {
+		return nil, status
+	}
+
+	klog.Infof("ServiceGraphPreemption: nominated node %s for pod %s.%s, evicting %d pods and violating the minimum replicas of %d ServiceGraphs",
+		best.nodeName, pod.Namespace, pod.Name, len(best.victims), best.violatedSvcGraphs)
+	return &framework.PostFilterResult{NominatedNodeName: best.nodeName}, framework.NewStatus(framework.Success)
+}
+
+// Selects the pods that need to be evicted from the node, such that the preemptor pod can be scheduled on it.
+//
+// First all pods with a lower priority than the preemptor are removed from the node. If the preemptor fits,
+// the removed pods are added back in the order determined by sortPotentialVictims, as long as the preemptor still fits.
+// The pods that cannot be added back are the victims.
+//
+// The cycleState and the nodeInfo are modified, so the caller must pass clones.
+func (me *ServiceGraphPreemptionPlugin) selectVictimsOnNode(
+	ctx context.Context,
+	cycleState *framework.CycleState,
+	pod *core.Pod,
+	nodeInfo *framework.NodeInfo,
+	replicas *serviceGraphReplicas,
+) ([]*core.Pod, *framework.Status) {
+	removePod := func(podInfo *framework.PodInfo) *framework.Status {
+		if err := nodeInfo.RemovePod(podInfo.Pod); err != nil {
+			return framework.AsStatus(err)
+		}
+		return me.frameworkHandle.RunPreFilterExtensionRemovePod(ctx, cycleState, pod, podInfo, nodeInfo)
+	}
+	addPod := func(podInfo *framework.PodInfo) *framework.Status {
+		nodeInfo.AddPodInfo(podInfo)
+		return me.frameworkHandle.RunPreFilterExtensionAddPod(ctx, cycleState, pod, podInfo, nodeInfo)
+	}
+
+	podPriority := getPodPriority(pod)
+	potentialVictims := make([]*core.Pod, 0)
+	podInfos := make(map[*core.Pod]*framework.PodInfo)
+	for _, podInfo := range nodeInfo.Pods {
+		if getPodPriority(podInfo.Pod) < podPriority {
+			potentialVictims = append(potentialVictims, podInfo.Pod)
+			podInfos[podInfo.Pod] = podInfo
+		}
+	}
+	if len(potentialVictims) == 0 {
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("no victims found on node %s", nodeInfo.Node().Name))
+	}
+	for _, victim := range potentialVictims {
+		if status := removePod(podInfos[victim]); !status.IsSuccess() {
+			return nil, framework.AsStatus(status.AsError())
+		}
+	}
+
+	if status := me.frameworkHandle.RunFilterPluginsWithNominatedPods(ctx, cycleState, pod, nodeInfo); !status.IsSuccess() {
+		return nil, status
+	}
+
+	sortPotentialVictims(potentialVictims, replicas)
+	victims := make([]*core.Pod, 0)
+	for _, potentialVictim := range potentialVictims {
+		podInfo := podInfos[potentialVictim]
+		if status := addPod(podInfo); !status.IsSuccess() {
+			return nil, framework.AsStatus(status.AsError())
+		}
+		if status := me.frameworkHandle.RunFilterPluginsWithNominatedPods(ctx, cycleState, pod, nodeInfo); status.IsSuccess() {
+			continue
+		}
+		if status := removePod(podInfo); !status.IsSuccess() {
+			return nil, framework.AsStatus(status.AsError())
+		}
+		victims = append(victims, potentialVictim)
+	}
+	return victims, framework.NewStatus(framework.Success)
+}
@@ -0,0 +1,147 @@
+package servicegraphpreemption
+
+import (
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+)
+
+// Identifies a ServiceGraphNode of a ServiceGraph.
+type svcGraphNodeKey struct {
+	// <namespace>.<ServiceGraph name>
+	svcGraphKey string
+
+	// The name of the ServiceGraphNode.
+	svcGraphNode string
+}
+
+// Tracks the replicas of the ServiceGraphNodes that are running in the cluster, along with their minimum
+// replica counts and the priorities of the ServiceGraphs.
+//
+// This is used to determine the ServiceGraphs, whose Replicas.Min would be violated by evicting a set of victims.
+type serviceGraphReplicas struct {
+	// The number of pods of each ServiceGraphNode that are assigned to a node and are not being deleted.
+	replicas map[svcGraphNodeKey]int
+
+	// The Replicas.Min of each ServiceGraphNode, for which replicas have been found.
+	minReplicas map[svcGraphNodeKey]int
+
+	// The priority of each ServiceGraph, which is the highest priority of its pods.
+	priorities map[string]int32
+}
+
+// Creates a new serviceGraphReplicas from the pods on the specified nodes.
+// getSvcGraph is used to look up the ServiceGraph CRD instances to obtain the minimum replica counts.
+// If a ServiceGraph cannot be found, its ServiceGraphNodes are assumed not to have any minimum replica count.
+func newServiceGraphReplicas(nodeInfos []*framework.NodeInfo, getSvcGraph func(namespace, name string) *fogappsCRDs.ServiceGraph) *serviceGraphReplicas {
+	me := &serviceGraphReplicas{
+		replicas:    make(map[svcGraphNodeKey]int),
+		minReplicas: make(map[svcGraphNodeKey]int),
+		priorities:  make(map[string]int32),
+	}
+
+	svcGraphs := make(map[string]*fogappsCRDs.ServiceGraph)
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			pod := podInfo.Pod
+			key, ok := getSvcGraphNodeKey(pod)
+			if !ok || pod.DeletionTimestamp != nil {
+				continue
+			}
+			me.replicas[key]++
+
+			if priority, ok := me.priorities[key.svcGraphKey]; !ok || getPodPriority(pod) > priority {
+				me.priorities[key.svcGraphKey] = getPodPriority(pod)
+			}
+
+			if _, ok := me.minReplicas[key]; ok {
+				continue
+			}
+			svcGraph, ok := svcGraphs[key.svcGraphKey]
+			if !ok {
+				svcGraphName, _ := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph)
+				svcGraph = getSvcGraph(kubeutil.GetNamespace(pod), svcGraphName)
+				svcGraphs[key.svcGraphKey] = svcGraph
+			}
+			me.minReplicas[key] = getMinReplicas(svcGraph, key.svcGraphNode)
+		}
+	}
+	return me
+}
+
+// Returns the priority of the ServiceGraph of the pod or, if the pod is not part of a ServiceGraph, the priority of the pod.
+func (me *serviceGraphReplicas) getPriority(pod *core.Pod) int32 {
+	if key, ok := getSvcGraphNodeKey(pod); ok {
+		if priority, ok := me.priorities[key.svcGraphKey]; ok {
+			return priority
+		}
+	}
+	return getPodPriority(pod)
+}
+
+// Returns true if evicting the pod would leave its ServiceGraphNode with fewer than Replicas.Min pods.
+func (me *serviceGraphReplicas) wouldViolateMinReplicas(pod *core.Pod) bool {
+	key, ok := getSvcGraphNodeKey(pod)
+	if !ok {
+		return false
+	}
+	return me.replicas[key]-1 < me.minReplicas[key]
+}
+
+// Returns the number of ServiceGraphs, for which at least one ServiceGraphNode would be left with fewer than
+// Replicas.Min pods if the victims were evicted.
+func (me *serviceGraphReplicas) countViolatedServiceGraphs(victims []*core.Pod) int {
+	evicted := make(map[svcGraphNodeKey]int)
+	for _, victim := range victims {
+		if key, ok := getSvcGraphNodeKey(victim); ok {
+			evicted[key]++
+		}
+	}
+
+	violatedSvcGraphs := make(map[string]bool)
+	for key, count := range evicted {
+		if me.replicas[key]-count < me.minReplicas[key] {
+			violatedSvcGraphs[key.svcGraphKey] = true
+		}
+	}
+	return len(violatedSvcGraphs)
+}
+
+// Returns the key of the pod's ServiceGraphNode or false, if the pod is not part of a ServiceGraph.
+func getSvcGraphNodeKey(pod *core.Pod) (svcGraphNodeKey, bool) {
+	svcGraphName, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph)
+	if !ok {
+		return svcGraphNodeKey{}, false
+	}
+	svcGraphNode, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode)
+	if !ok {
+		return svcGraphNodeKey{}, false
+	}
+	return svcGraphNodeKey{
+		svcGraphKey:  kubeutil.GetNamespace(pod) + "." + svcGraphName,
+		svcGraphNode: svcGraphNode,
+	}, true
+}
+
+// Returns the Replicas.Min of the specified ServiceGraphNode or 0 if the ServiceGraph or the node do not exist.
+func getMinReplicas(svcGraph *fogappsCRDs.ServiceGraph, svcGraphNode string) int {
+	if svcGraph == nil {
+		return 0
+	}
+	for i := range svcGraph.Spec.Nodes {
+		if svcGraph.Spec.Nodes[i].Name == svcGraphNode {
+			return int(svcGraph.Spec.Nodes[i].Replicas.Min)
+		}
+	}
+	return 0
+}
+
+// Returns the priority of the pod or 0 if the pod does not have a priority.
+func getPodPriority(pod *core.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package workloadtype
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	_configMapWorkloadProfileProvider *ConfigMapWorkloadProfileProvider
+
+	_ WorkloadProfileProvider = _configMapWorkloadProfileProvider
+)
+
+// ConfigMapWorkloadProfileProvider is a WorkloadProfileProvider that obtains the workload profiles from the benchmark results
+// stored in a ConfigMap.
+//
+// Every key of the ConfigMap's data is a workload type and its value is a YAML or JSON object that maps node types
+// to the expected performance of the workload type.
+type ConfigMapWorkloadProfileProvider struct {
+	namespace string
+	name      string
+
+	configMapLister corelisters.ConfigMapLister
+}
+
+// NewConfigMapWorkloadProfileProvider creates a new ConfigMapWorkloadProfileProvider for the specified ConfigMap.
+//
+// The ConfigMap is watched by an informer that is started immediately and runs until stopCh is closed.
+// The informer is restricted to the specified ConfigMap, such that the scheduler only needs permission to read
+// ConfigMaps in its namespace.
+func NewConfigMapWorkloadProfileProvider(clientSet kubernetes.Interface, namespace string, name string, stopCh <-chan struct{}) *ConfigMapWorkloadProfileProvider {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(
+		clientSet,
+		0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *meta.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+	configMapLister := informerFactory.Core().V1().ConfigMaps().Lister()
+	informerFactory.Start(stopCh)
+
+	return newConfigMapWorkloadProfileProvider(configMapLister, namespace, name)
+}
+
+func newConfigMapWorkloadProfileProvider(configMapLister corelisters.ConfigMapLister, namespace string, name string) *ConfigMapWorkloadProfileProvider {
+	return &ConfigMapWorkloadProfileProvider{
+		namespace:       namespace,
+		name:            name,
+		configMapLister: configMapLister,
+	}
+}
+
+// GetWorkloadProfile returns the WorkloadProfile of the specified workload type.
+//
+// If no profile is known for the workload type, nil is returned without an error.
+func (me *ConfigMapWorkloadProfileProvider) GetWorkloadProfile(ctx context.Context, workloadType string) (WorkloadProfile, error) {
+	configMap, err := me.configMapLister.ConfigMaps(me.namespace).Get(me.name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, ok := configMap.Data[workloadType]
+	if !ok {
+		return nil, nil
+	}
+	profile := WorkloadProfile{}
+	if err := yaml.Unmarshal([]byte(data), &profile); err != nil {
+		return nil, fmt.Errorf("invalid profile of workload type %s in ConfigMap %s.%s: %w", workloadType, me.namespace, me.name, err)
+	}
+	return profile, nil
+}
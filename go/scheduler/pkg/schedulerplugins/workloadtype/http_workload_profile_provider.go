@@ -0,0 +1,125 @@
+package workloadtype
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	_httpWorkloadProfileProvider *HttpWorkloadProfileProvider
+
+	_ WorkloadProfileProvider = _httpWorkloadProfileProvider
+)
+
+// HttpWorkloadProfileProvider is a WorkloadProfileProvider that obtains the workload profiles from an external model server.
+//
+// For a workload type the model server is queried using "GET <baseURL>/profiles/<workload type>", which must return
+// a JSON object that maps node types to the expected performance of the workload type or 404, if the workload type is unknown.
+//
+// The responses of the model server, including unknown workload types, are cached for the cache TTL, such that the model server
+// is not queried in every scheduling cycle. Failed requests are not cached.
+type HttpWorkloadProfileProvider struct {
+	baseURL  string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	// The cached profiles, indexed by workload type.
+	cache map[string]*cachedWorkloadProfile
+
+	// Synchronizes access to the cache.
+	mutex sync.Mutex
+}
+
+// A WorkloadProfile obtained from the model server.
+type cachedWorkloadProfile struct {
+	// The profile or nil if the workload type is unknown to the model server.
+	profile WorkloadProfile
+
+	// The time after which the profile must be fetched again.
+	expiresAt time.Time
+}
+
+// NewHttpWorkloadProfileProvider creates a new HttpWorkloadProfileProvider for the model server at the specified base URL,
+// which caches the obtained profiles for cacheTTL.
+func NewHttpWorkloadProfileProvider(baseURL string, timeout time.Duration, cacheTTL time.Duration) *HttpWorkloadProfileProvider {
+	return &HttpWorkloadProfileProvider{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		client:   &http.Client{Timeout: timeout},
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]*cachedWorkloadProfile),
+	}
+}
+
+// GetWorkloadProfile returns the WorkloadProfile of the specified workload type from the cache or,
+// if it is not cached or has expired, from the model server.
+//
+// If no profile is known for the workload type, nil is returned without an error.
+func (me *HttpWorkloadProfileProvider) GetWorkloadProfile(ctx context.Context, workloadType string) (WorkloadProfile, error) {
+	if profile, ok := me.getCachedProfile(workloadType); ok {
+		return profile, nil
+	}
+
+	profile, err := me.fetchWorkloadProfile(ctx, workloadType)
+	if err != nil {
+		return nil, err
+	}
+
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	me.cache[workloadType] = &cachedWorkloadProfile{
+		profile:   profile,
+		expiresAt: time.Now().Add(me.cacheTTL),
+	}
+	return profile, nil
+}
+
+// Returns the cached profile of the workload type and true, if it is cached and has not expired yet.
+func (me *HttpWorkloadProfileProvider) getCachedProfile(workloadType string) (WorkloadProfile, bool) {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+
+	cached, ok := me.cache[workloadType]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(cached.expiresAt) {
+		delete(me.cache, workloadType)
+		return nil, false
+	}
+	return cached.profile, true
+}
+
+// Fetches the profile of the workload type from the model server.
+func (me *HttpWorkloadProfileProvider) fetchWorkloadProfile(ctx context.Context, workloadType string) (WorkloadProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, me.baseURL+"/profiles/"+url.PathEscape(workloadType), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := me.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("the model server returned status %s for workload type %s", resp.Status, workloadType)
+	}
+
+	profile := WorkloadProfile{}
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("invalid profile of workload type %s returned by the model server: %w", workloadType, err)
+	}
+	return profile, nil
+}
@@ -0,0 +1,31 @@
+package workloadtype
+
+import (
+	"context"
+)
+
+// WorkloadProfile maps node types to the expected performance of a workload type on nodes of that type.
+//
+// The performance values are relative to each other, i.e., a higher value means that the workload type is expected
+// to perform better on nodes of that type.
+type WorkloadProfile map[string]float64
+
+// WorkloadProfileProvider provides the expected performance of workload types on the node types of the cluster.
+type WorkloadProfileProvider interface {
+
+	// GetWorkloadProfile returns the WorkloadProfile of the specified workload type.
+	//
+	// If no profile is known for the workload type, nil is returned without an error.
+	GetWorkloadProfile(ctx context.Context, workloadType string) (WorkloadProfile, error)
+}
+
+// Returns the highest expected performance in the profile or 0 if the profile is empty.
+func (me WorkloadProfile) maxPerformance() float64 {
+	maxPerformance := 0.0
+	for _, performance := range me {
+		if performance > maxPerformance {
+			maxPerformance = performance
+		}
+	}
+	return maxPerformance
+}
@@ -0,0 +1,157 @@
+package workloadtype
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func checkProfile(t *testing.T, name string, expected WorkloadProfile, actual WorkloadProfile) {
+	if len(actual) != len(expected) || (expected == nil) != (actual == nil) {
+		t.Errorf("%s: expected %v, but got %v", name, expected, actual)
+		return
+	}
+	for nodeType, performance := range expected {
+		if actual[nodeType] != performance {
+			t.Errorf("%s: expected %v, but got %v", name, expected, actual)
+			return
+		}
+	}
+}
+
+func TestConfigMapWorkloadProfileProvider(t *testing.T) {
+	configMap := &core.ConfigMap{}
+	configMap.Namespace = "polaris"
+	configMap.Name = "workload-profiles"
+	configMap.Data = map[string]string{
+		"video-transcoding": "m5.large: 0.6\nc5.xlarge: 1.0\n",
+		"ml-inference":      `{"gpu-node": 2.5}`,
+		"broken":            "[1, 2",
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(configMap); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name         string
+		configMap    string
+		workloadType string
+		expected     WorkloadProfile
+		wantErr      bool
+	}{
+		{name: "YAML profile", configMap: "workload-profiles", workloadType: "video-transcoding", expected: WorkloadProfile{"m5.large": 0.6, "c5.xlarge": 1.0}},
+		{name: "JSON profile", configMap: "workload-profiles", workloadType: "ml-inference", expected: WorkloadProfile{"gpu-node": 2.5}},
+		{name: "unknown workload type", configMap: "workload-profiles", workloadType: "database"},
+		{name: "missing ConfigMap", configMap: "other-profiles", workloadType: "video-transcoding"},
+		{name: "invalid profile", configMap: "workload-profiles", workloadType: "broken", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		provider := newConfigMapWorkloadProfileProvider(corelisters.NewConfigMapLister(indexer), "polaris", tc.configMap)
+		profile, err := provider.GetWorkloadProfile(context.Background(), tc.workloadType)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		checkProfile(t, tc.name, tc.expected, profile)
+	}
+}
+
+func TestHttpWorkloadProfileProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/profiles/video-transcoding":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"m5.large": 0.6, "c5.xlarge": 1.0}`))
+		case "/profiles/failing":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name         string
+		workloadType string
+		expected     WorkloadProfile
+		wantErr      bool
+	}{
+		{name: "known workload type", workloadType: "video-transcoding", expected: WorkloadProfile{"m5.large": 0.6, "c5.xlarge": 1.0}},
+		{name: "unknown workload type", workloadType: "database"},
+		{name: "server error", workloadType: "failing", wantErr: true},
+	}
+
+	provider := NewHttpWorkloadProfileProvider(server.URL+"/", time.Second, time.Minute)
+	for _, tc := range testCases {
+		profile, err := provider.GetWorkloadProfile(context.Background(), tc.workloadType)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		checkProfile(t, tc.name, tc.expected, profile)
+	}
+}
+
+func TestHttpWorkloadProfileProviderCache(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/profiles/video-transcoding":
+			_, _ = w.Write([]byte(`{"m5.large": 0.6, "c5.xlarge": 1.0}`))
+		case "/profiles/failing":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cacheTTL := 100 * time.Millisecond
+	provider := NewHttpWorkloadProfileProvider(server.URL, time.Second, cacheTTL)
+	getProfile := func(workloadType string) {
+		_, _ = provider.GetWorkloadProfile(context.Background(), workloadType)
+	}
+	checkRequests := func(step string, expected int32) {
+		if actual := atomic.LoadInt32(&requests); actual != expected {
+			t.Errorf("%s: expected %d requests to the model server, but got %d", step, expected, actual)
+		}
+	}
+
+	getProfile("video-transcoding")
+	getProfile("video-transcoding")
+	checkRequests("known workload type", 1)
+
+	getProfile("database")
+	getProfile("database")
+	checkRequests("unknown workload type", 2)
+
+	getProfile("failing")
+	getProfile("failing")
+	checkRequests("failed requests", 4)
+
+	time.Sleep(cacheTTL + 10*time.Millisecond)
+	getProfile("video-transcoding")
+	checkRequests("expired profile", 5)
+}
@@ -0,0 +1,184 @@
+package workloadtype
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+const (
+	defaultNodeTypeLabel              = "node.kubernetes.io/instance-type"
+	defaultProfilesConfigMapNamespace = "polaris"
+	defaultProfilesConfigMapName      = "workload-profiles"
+	defaultModelServerTimeout         = 1 * time.Second
+	defaultModelServerCacheTTL        = 5 * time.Minute
+)
+
+// WorkloadTypeArgs configures the WorkloadTypePlugin.
+//
+// The performance profiles of the workload types are obtained from the ModelServer, if it is configured,
+// and from the ProfilesConfigMap otherwise.
+type WorkloadTypeArgs struct {
+	meta.TypeMeta `json:",inline"`
+
+	// The label of a node that contains the node's type, for which the expected performance is looked up
+	// in the performance profiles.
+	//
+	// Default: "node.kubernetes.io/instance-type"
+	NodeTypeLabel *string `json:"nodeTypeLabel,omitempty"`
+
+	// The ConfigMap that contains the benchmark results of the workload types.
+	// Every key of the ConfigMap's data is a workload type and its value is a YAML or JSON object
+	// that maps node types to the expected performance of the workload type on them (higher is better), e.g.,
+	//
+	//   video-transcoding: |
+	//     m5.large: 0.6
+	//     c5.xlarge: 1.0
+	//
+	// Default: namespace "polaris", name "workload-profiles"
+	ProfilesConfigMap *ProfilesConfigMapRef `json:"profilesConfigMap,omitempty"`
+
+	// An optional external model server, which is used instead of the ProfilesConfigMap.
+	//
+	// +optional
+	ModelServer *ModelServerConfig `json:"modelServer,omitempty"`
+}
+
+// ProfilesConfigMapRef references the ConfigMap that contains the performance profiles of the workload types.
+type ProfilesConfigMapRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ModelServerConfig configures the HTTP client for an external model server that provides the performance profiles.
+//
+// For a workload type the model server is queried using "GET <url>/profiles/<workload type>", which must return
+// a JSON object that maps node types to the expected performance of the workload type on them or 404, if the
+// workload type is unknown.
+type ModelServerConfig struct {
+	// The base URL of the model server. Must be an http or https URL.
+	URL string `json:"url"`
+
+	// The maximum time to wait for a response from the model server. Must be greater than zero.
+	//
+	// Default: "1s"
+	Timeout *meta.Duration `json:"timeout,omitempty"`
+
+	// The time for which a profile obtained from the model server is cached, before it is fetched again.
+	// Unknown workload types are cached as well. Must be greater than zero.
+	//
+	// Default: "5m"
+	CacheTTL *meta.Duration `json:"cacheTTL,omitempty"`
+}
+
+// Decodes the WorkloadTypeArgs from the runtime.Object passed to New(), sets the defaults, and validates them.
+func decodeWorkloadTypeArgs(obj runtime.Object) (*WorkloadTypeArgs, error) {
+	args := &WorkloadTypeArgs{}
+	if err := util.DecodePluginArgs(PluginName, obj, args); err != nil {
+		return nil, err
+	}
+	setDefaultsWorkloadTypeArgs(args)
+	if err := validateWorkloadTypeArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// AddArgsToScheme registers the WorkloadTypeArgs and their defaults with the specified scheme.
+func AddArgsToScheme(scheme *runtime.Scheme) {
+	util.AddPluginArgsToScheme(scheme, PluginName, &WorkloadTypeArgs{}, func(obj interface{}) {
+		setDefaultsWorkloadTypeArgs(obj.(*WorkloadTypeArgs))
+	})
+}
+
+func setDefaultsWorkloadTypeArgs(args *WorkloadTypeArgs) {
+	if args.NodeTypeLabel == nil {
+		nodeTypeLabel := defaultNodeTypeLabel
+		args.NodeTypeLabel = &nodeTypeLabel
+	}
+	if args.ProfilesConfigMap == nil {
+		args.ProfilesConfigMap = &ProfilesConfigMapRef{
+			Namespace: defaultProfilesConfigMapNamespace,
+			Name:      defaultProfilesConfigMapName,
+		}
+	}
+	if args.ModelServer != nil {
+		if args.ModelServer.Timeout == nil {
+			args.ModelServer.Timeout = &meta.Duration{Duration: defaultModelServerTimeout}
+		}
+		if args.ModelServer.CacheTTL == nil {
+			args.ModelServer.CacheTTL = &meta.Duration{Duration: defaultModelServerCacheTTL}
+		}
+	}
+}
+
+func validateWorkloadTypeArgs(args *WorkloadTypeArgs) error {
+	if *args.NodeTypeLabel == "" {
+		return fmt.Errorf("invalid %s args: nodeTypeLabel must not be empty", PluginName)
+	}
+	if args.ProfilesConfigMap.Namespace == "" || args.ProfilesConfigMap.Name == "" {
+		return fmt.Errorf("invalid %s args: profilesConfigMap must specify a namespace and a name", PluginName)
+	}
+	if args.ModelServer != nil {
+		serverURL, err := url.Parse(args.ModelServer.URL)
+		if err != nil || (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+			return fmt.Errorf("invalid %s args: modelServer.url must be an http or https URL, but is %q", PluginName, args.ModelServer.URL)
+		}
+		if args.ModelServer.Timeout.Duration <= 0 {
+			return fmt.Errorf("invalid %s args: modelServer.timeout must be greater than zero, but is %v", PluginName, args.ModelServer.Timeout.Duration)
+		}
+		if args.ModelServer.CacheTTL.Duration <= 0 {
+			return fmt.Errorf("invalid %s args: modelServer.cacheTTL must be greater than zero, but is %v", PluginName, args.ModelServer.CacheTTL.Duration)
+		}
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out.
+func (me *WorkloadTypeArgs) DeepCopyInto(out *WorkloadTypeArgs) {
+	*out = *me
+	out.TypeMeta = me.TypeMeta
+	if me.NodeTypeLabel != nil {
+		nodeTypeLabel := *me.NodeTypeLabel
+		out.NodeTypeLabel = &nodeTypeLabel
+	}
+	if me.ProfilesConfigMap != nil {
+		profilesConfigMap := *me.ProfilesConfigMap
+		out.ProfilesConfigMap = &profilesConfigMap
+	}
+	if me.ModelServer != nil {
+		modelServer := *me.ModelServer
+		if me.ModelServer.Timeout != nil {
+			timeout := *me.ModelServer.Timeout
+			modelServer.Timeout = &timeout
+		}
+		if me.ModelServer.CacheTTL != nil {
+			cacheTTL := *me.ModelServer.CacheTTL
+			modelServer.CacheTTL = &cacheTTL
+		}
+		out.ModelServer = &modelServer
+	}
+}
+
+// DeepCopy creates a deep copy of the WorkloadTypeArgs.
+func (me *WorkloadTypeArgs) DeepCopy() *WorkloadTypeArgs {
+	if me == nil {
+		return nil
+	}
+	out := &WorkloadTypeArgs{}
+	me.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject creates a deep copy of the WorkloadTypeArgs as a runtime.Object.
+func (me *WorkloadTypeArgs) DeepCopyObject() runtime.Object {
+	if c := me.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package workloadtype
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDecodeWorkloadTypeArgs(t *testing.T) {
+	testCases := []struct {
+		name              string
+		obj               runtime.Object
+		expectedConfigMap ProfilesConfigMapRef
+		expectedTimeout   time.Duration
+		expectedCacheTTL  time.Duration
+		wantErr           bool
+	}{
+		{
+			name:              "nil args",
+			obj:               nil,
+			expectedConfigMap: ProfilesConfigMapRef{Namespace: "polaris", Name: "workload-profiles"},
+		},
+		{
+			name:              "custom ConfigMap",
+			obj:               &runtime.Unknown{Raw: []byte(`{"profilesConfigMap": {"namespace": "benchmarks", "name": "results"}}`)},
+			expectedConfigMap: ProfilesConfigMapRef{Namespace: "benchmarks", Name: "results"},
+		},
+		{
+			name:              "model server",
+			obj:               &runtime.Unknown{Raw: []byte(`{"modelServer": {"url": "http://model-server.polaris:8080"}}`)},
+			expectedConfigMap: ProfilesConfigMapRef{Namespace: "polaris", Name: "workload-profiles"},
+			expectedTimeout:   time.Second,
+			expectedCacheTTL:  5 * time.Minute,
+		},
+		{
+			name:              "model server with cache TTL",
+			obj:               &runtime.Unknown{Raw: []byte(`{"modelServer": {"url": "https://model-server", "cacheTTL": "30s"}}`)},
+			expectedConfigMap: ProfilesConfigMapRef{Namespace: "polaris", Name: "workload-profiles"},
+			expectedTimeout:   time.Second,
+			expectedCacheTTL:  30 * time.Second,
+		},
+		{name: "empty nodeTypeLabel", obj: &runtime.Unknown{Raw: []byte(`{"nodeTypeLabel": ""}`)}, wantErr: true},
+		{name: "incomplete ConfigMap", obj: &runtime.Unknown{Raw: []byte(`{"profilesConfigMap": {"name": "results"}}`)}, wantErr: true},
+		{name: "invalid model server URL", obj: &runtime.Unknown{Raw: []byte(`{"modelServer": {"url": "model-server:8080"}}`)}, wantErr: true},
+		{name: "zero timeout", obj: &runtime.Unknown{Raw: []byte(`{"modelServer": {"url": "http://model-server", "timeout": "0s"}}`)}, wantErr: true},
+		{name: "zero cache TTL", obj: &runtime.Unknown{Raw: []byte(`{"modelServer": {"url": "http://model-server", "cacheTTL": "0s"}}`)}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		args, err := decodeWorkloadTypeArgs(tc.obj)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if *args.NodeTypeLabel != defaultNodeTypeLabel {
+			t.Errorf("%s: expected nodeTypeLabel %s, but got %s", tc.name, defaultNodeTypeLabel, *args.NodeTypeLabel)
+		}
+		if *args.ProfilesConfigMap != tc.expectedConfigMap {
+			t.Errorf("%s: expected profilesConfigMap %+v, but got %+v", tc.name, tc.expectedConfigMap, *args.ProfilesConfigMap)
+		}
+		if tc.expectedTimeout != 0 && args.ModelServer.Timeout.Duration != tc.expectedTimeout {
+			t.Errorf("%s: expected modelServer.timeout %v, but got %v", tc.name, tc.expectedTimeout, args.ModelServer.Timeout.Duration)
+		}
+		if tc.expectedCacheTTL != 0 && args.ModelServer.CacheTTL.Duration != tc.expectedCacheTTL {
+			t.Errorf("%s: expected modelServer.cacheTTL %v, but got %v", tc.name, tc.expectedCacheTTL, args.ModelServer.CacheTTL.Duration)
+		}
+	}
+}
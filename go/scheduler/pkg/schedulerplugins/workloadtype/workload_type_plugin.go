@@ -2,11 +2,15 @@ package workloadtype
 
 import (
 	"context"
+	"math"
 
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
 	framework "k8s.io/kubernetes/pkg/scheduler/framework"
 
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
 	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
 )
 
@@ -25,15 +29,47 @@ var (
 )
 
 // WorkloadTypePlugin is a Score plugin that assigns higher scores to nodes that are known to perform well for the pod's workload type.
+//
+// The workload type of a pod is specified by its kubeutil.AnnotationWorkloadType annotation or, if the pod does not have
+// this annotation, by the annotations of its ServiceGraphNode.
+// The expected performance of the workload type on the node types is obtained from a WorkloadProfileProvider.
+// The type of a node is read from the node label configured in the WorkloadTypeArgs.
+// Nodes, whose type is not contained in the profile, receive the lowest score.
+// If a pod does not have a workload type or if there is no profile for it, all nodes receive the same score.
 type WorkloadTypePlugin struct {
 	handle framework.Handle
+
+	args *WorkloadTypeArgs
+
+	profileProvider WorkloadProfileProvider
 }
 
 // New creates a new WorkloadTypePlugin instance.
+//
+// If the WorkloadTypeArgs configure a model server, an HttpWorkloadProfileProvider is used, otherwise a ConfigMapWorkloadProfileProvider.
 func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, err := decodeWorkloadTypeArgs(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var profileProvider WorkloadProfileProvider
+	if args.ModelServer != nil {
+		profileProvider = NewHttpWorkloadProfileProvider(args.ModelServer.URL, args.ModelServer.Timeout.Duration, args.ModelServer.CacheTTL.Duration)
+	} else {
+		// The plugin lives as long as the scheduler, so the informer does not need to be stopped.
+		profileProvider = NewConfigMapWorkloadProfileProvider(handle.ClientSet(), args.ProfilesConfigMap.Namespace, args.ProfilesConfigMap.Name, wait.NeverStop)
+	}
+
+	return newWorkloadTypePlugin(handle, args, profileProvider), nil
+}
+
+func newWorkloadTypePlugin(handle framework.Handle, args *WorkloadTypeArgs, profileProvider WorkloadProfileProvider) *WorkloadTypePlugin {
 	return &WorkloadTypePlugin{
-		handle: handle,
-	}, nil
+		handle:          handle,
+		args:            args,
+		profileProvider: profileProvider,
+	}
 }
 
 // Name returns the name of this scheduler plugin.
@@ -41,16 +77,28 @@ func (me *WorkloadTypePlugin) Name() string {
 	return PluginName
 }
 
-// PreScore determines the workload type of the pod and stores that info in the state.
+// PreScore determines the workload type of the pod, obtains its profile, and stores that info in the state.
 func (me *WorkloadTypePlugin) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodes []*core.Node) *framework.Status {
-	svcGraphState, noSvcGraphStatus := util.GetServiceGraphFromCycleStateOrStatus(cycleState)
-	if noSvcGraphStatus != nil {
-		return noSvcGraphStatus
+	workloadType, ok := getWorkloadType(pod, cycleState)
+	if !ok {
+		return framework.NewStatus(framework.Success, "Skipping this pod, because it does not have a workload type.")
+	}
+
+	profile, err := me.profileProvider.GetWorkloadProfile(ctx, workloadType)
+	if err != nil {
+		// An unavailable profile must not prevent the pod from being scheduled, so we only skip the scoring.
+		klog.Errorf("WorkloadType: could not obtain the profile of workload type %s: %s", workloadType, err)
+		return framework.NewStatus(framework.Success)
+	}
+	maxPerformance := profile.maxPerformance()
+	if maxPerformance <= 0 {
+		return framework.NewStatus(framework.Success, "Skipping this pod, because its workload type does not have a known profile.")
 	}
 
 	workloadTypeState := workloadTypeStateData{
-		// ToDo: Determine workload type, based on ML data.
-		workloadType: svcGraphState.ServiceGraphCRD().Name,
+		workloadType:   workloadType,
+		profile:        profile,
+		maxPerformance: maxPerformance,
 	}
 	cycleState.Write(workloadTypeStateKey, &workloadTypeState)
 
@@ -66,24 +114,67 @@ func (me *WorkloadTypePlugin) ScoreExtensions() framework.ScoreExtensions {
 // indicating the rank of the node. All scoring plugins must return success or
 // the pod will be rejected.
 func (me *WorkloadTypePlugin) Score(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, nodeName string) (int64, *framework.Status) {
-	workloadTypeState, noSvcGraphStatus := getWorkloadTypeStateDataOrStatus(cycleState)
-	if noSvcGraphStatus != nil {
-		return 100, noSvcGraphStatus
+	workloadTypeState, noProfileStatus := getWorkloadTypeStateDataOrStatus(cycleState)
+	if noProfileStatus != nil {
+		return 100, noProfileStatus
 	}
 
-	// ToDo: Implementation with ML data.
-	var _ = workloadTypeState
-
-	return 100, framework.NewStatus(framework.Success)
+	nodeInfo, err := me.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	return calcScore(nodeInfo.Node(), *me.args.NodeTypeLabel, workloadTypeState), framework.NewStatus(framework.Success)
 }
 
 // NormalizeScore normalizes all scores to a range between 0 and 100.
 func (me *WorkloadTypePlugin) NormalizeScore(ctx context.Context, cycleState *framework.CycleState, pod *core.Pod, scores framework.NodeScoreList) *framework.Status {
-	_, noSvcGraphStatus := getWorkloadTypeStateDataOrStatus(cycleState)
-	if noSvcGraphStatus != nil {
-		return noSvcGraphStatus
+	_, noProfileStatus := getWorkloadTypeStateDataOrStatus(cycleState)
+	if noProfileStatus != nil {
+		return noProfileStatus
 	}
 
 	util.NormalizeNodeScores(scores)
 	return framework.NewStatus(framework.Success)
 }
+
+// Returns the workload type of the pod from its annotation or, if the pod does not have a workload type annotation,
+// from the annotations of its ServiceGraphNode.
+func getWorkloadType(pod *core.Pod, cycleState *framework.CycleState) (string, bool) {
+	if workloadType, ok := kubeutil.GetAnnotation(pod, kubeutil.AnnotationWorkloadType); ok && workloadType != "" {
+		return workloadType, true
+	}
+
+	svcGraphState, err := util.GetServiceGraphFromCycleState(cycleState)
+	if err != nil {
+		return "", false
+	}
+	svcGraphNodeName, ok := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode)
+	if !ok {
+		return "", false
+	}
+	svcGraph := svcGraphState.ServiceGraphCRD()
+	for i := range svcGraph.Spec.Nodes {
+		if svcNode := &svcGraph.Spec.Nodes[i]; svcNode.Name == svcGraphNodeName {
+			workloadType, ok := svcNode.PodAnnotations[kubeutil.AnnotationWorkloadType]
+			return workloadType, ok && workloadType != ""
+		}
+	}
+	return "", false
+}
+
+// Calculates the score of the node, which is its type's expected performance relative to the highest
+// expected performance in the profile.
+func calcScore(node *core.Node, nodeTypeLabel string, workloadTypeState *workloadTypeStateData) int64 {
+	if node == nil {
+		return 0
+	}
+	nodeType, ok := kubeutil.GetLabel(node, nodeTypeLabel)
+	if !ok {
+		return 0
+	}
+	performance, ok := workloadTypeState.profile[nodeType]
+	if !ok || performance <= 0 {
+		return 0
+	}
+	return int64(math.Round(performance / workloadTypeState.maxPerformance * float64(framework.MaxNodeScore)))
+}
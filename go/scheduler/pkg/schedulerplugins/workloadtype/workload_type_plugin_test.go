@@ -0,0 +1,92 @@
+package workloadtype
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	fogappsCRDs "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/servicegraphmanager"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/internal/util"
+)
+
+func TestCalcScore(t *testing.T) {
+	profile := WorkloadProfile{"m5.large": 0.6, "c5.xlarge": 1.2, "broken": -1}
+	state := &workloadTypeStateData{workloadType: "video-transcoding", profile: profile, maxPerformance: profile.maxPerformance()}
+
+	newNode := func(nodeType string) *core.Node {
+		node := &core.Node{}
+		if nodeType != "" {
+			node.Labels = map[string]string{defaultNodeTypeLabel: nodeType}
+		}
+		return node
+	}
+
+	testCases := []struct {
+		name     string
+		node     *core.Node
+		expected int64
+	}{
+		{name: "best node type", node: newNode("c5.xlarge"), expected: 100},
+		{name: "slower node type", node: newNode("m5.large"), expected: 50},
+		{name: "unknown node type", node: newNode("t3.micro"), expected: 0},
+		{name: "negative performance", node: newNode("broken"), expected: 0},
+		{name: "node without type", node: newNode(""), expected: 0},
+	}
+
+	for _, tc := range testCases {
+		if actual := calcScore(tc.node, defaultNodeTypeLabel, state); actual != tc.expected {
+			t.Errorf("%s: expected %d, but got %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestGetWorkloadType(t *testing.T) {
+	svcGraph := &fogappsCRDs.ServiceGraph{}
+	svcGraph.Namespace = "test"
+	svcGraph.Name = "app"
+	svcGraph.Spec.Nodes = []fogappsCRDs.ServiceGraphNode{
+		{Name: "transcoder", NodeType: fogappsCRDs.ServiceNode, PodAnnotations: map[string]string{kubeutil.AnnotationWorkloadType: "video-transcoding"}},
+		{Name: "frontend", NodeType: fogappsCRDs.ServiceNode},
+	}
+	svcGraphManager := servicegraphmanager.NewFakeServiceGraphManager()
+	svcGraphState := svcGraphManager.AddServiceGraph(svcGraph)
+
+	newPod := func(svcGraphNode string, workloadType string) *core.Pod {
+		pod := &core.Pod{}
+		pod.Namespace = "test"
+		if svcGraphNode != "" {
+			pod.Labels = map[string]string{kubeutil.LabelRefServiceGraph: "app", kubeutil.LabelRefServiceGraphNode: svcGraphNode}
+		}
+		if workloadType != "" {
+			pod.Annotations = map[string]string{kubeutil.AnnotationWorkloadType: workloadType}
+		}
+		return pod
+	}
+
+	testCases := []struct {
+		name       string
+		pod        *core.Pod
+		expected   string
+		expectedOk bool
+	}{
+		{name: "pod annotation", pod: newPod("", "ml-inference"), expected: "ml-inference", expectedOk: true},
+		{name: "pod annotation overrides ServiceGraphNode", pod: newPod("transcoder", "ml-inference"), expected: "ml-inference", expectedOk: true},
+		{name: "ServiceGraphNode annotation", pod: newPod("transcoder", ""), expected: "video-transcoding", expectedOk: true},
+		{name: "ServiceGraphNode without annotation", pod: newPod("frontend", ""), expectedOk: false},
+		{name: "no annotation and no ServiceGraph", pod: newPod("", ""), expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		cycleState := framework.NewCycleState()
+		if _, ok := kubeutil.GetLabel(tc.pod, kubeutil.LabelRefServiceGraph); ok {
+			util.WriteServiceGraphToCycleState(cycleState, svcGraphState)
+		}
+		workloadType, ok := getWorkloadType(tc.pod, cycleState)
+		if ok != tc.expectedOk || workloadType != tc.expected {
+			t.Errorf("%s: expected (%q, %v), but got (%q, %v)", tc.name, tc.expected, tc.expectedOk, workloadType, ok)
+		}
+	}
+}
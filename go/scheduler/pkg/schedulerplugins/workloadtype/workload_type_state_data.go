@@ -14,20 +14,29 @@ var (
 
 type workloadTypeStateData struct {
 	workloadType string
+
+	// The expected performance of the workload type on the node types.
+	profile WorkloadProfile
+
+	// The highest expected performance in the profile.
+	maxPerformance float64
 }
 
 func (me *workloadTypeStateData) Clone() framework.StateData {
+	// The profile is never modified, so it does not need to be copied.
 	return &workloadTypeStateData{
-		workloadType: me.workloadType,
+		workloadType:   me.workloadType,
+		profile:        me.profile,
+		maxPerformance: me.maxPerformance,
 	}
 }
 
-// Gets the workloadTypeStateData from the CycleState or returns a framework.Success state if the current pod is not associated with a ServiceGraph
-// and, thus, does not have any workloadTypeStateData.
+// Gets the workloadTypeStateData from the CycleState or returns a framework.Success state if the workload type of the current pod
+// or its profile are not known and, thus, there is no workloadTypeStateData.
 func getWorkloadTypeStateDataOrStatus(cycleState *framework.CycleState) (*workloadTypeStateData, *framework.Status) {
 	stateData, err := cycleState.Read(workloadTypeStateKey)
 	if err == nil {
 		return stateData.(*workloadTypeStateData), nil
 	}
-	return nil, framework.NewStatus(framework.Success, "Skipping this pod, because it is not associated with a ServiceGraph.")
+	return nil, framework.NewStatus(framework.Success, "Skipping this pod, because its workload type does not have a known profile.")
 }
@@ -0,0 +1,105 @@
+package simulator
+
+import (
+	"fmt"
+	"sync"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var (
+	_nodeInfoSnapshot *nodeInfoSnapshot
+
+	_ framework.SharedLister   = _nodeInfoSnapshot
+	_ framework.NodeInfoLister = _nodeInfoSnapshot
+)
+
+// nodeInfoSnapshot is the in-memory equivalent of the scheduler cache's snapshot.
+// It contains the NodeInfos of all nodes of the simulated cluster, including the pods that have been assumed on them.
+type nodeInfoSnapshot struct {
+	mutex     sync.RWMutex
+	nodeInfos []*framework.NodeInfo
+	nodeMap   map[string]*framework.NodeInfo
+}
+
+func newNodeInfoSnapshot(nodes []*core.Node, pods []*core.Pod) *nodeInfoSnapshot {
+	snapshot := &nodeInfoSnapshot{
+		nodeInfos: make([]*framework.NodeInfo, len(nodes)),
+		nodeMap:   make(map[string]*framework.NodeInfo, len(nodes)),
+	}
+	for i, node := range nodes {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		snapshot.nodeInfos[i] = nodeInfo
+		snapshot.nodeMap[node.Name] = nodeInfo
+	}
+	for _, pod := range pods {
+		if nodeInfo, ok := snapshot.nodeMap[pod.Spec.NodeName]; ok {
+			nodeInfo.AddPod(pod)
+		}
+	}
+	return snapshot
+}
+
+func (me *nodeInfoSnapshot) NodeInfos() framework.NodeInfoLister {
+	return me
+}
+
+func (me *nodeInfoSnapshot) List() ([]*framework.NodeInfo, error) {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	return me.nodeInfos, nil
+}
+
+func (me *nodeInfoSnapshot) HavePodsWithAffinityList() ([]*framework.NodeInfo, error) {
+	return me.filter(func(nodeInfo *framework.NodeInfo) bool { return len(nodeInfo.PodsWithAffinity) > 0 }), nil
+}
+
+func (me *nodeInfoSnapshot) HavePodsWithRequiredAntiAffinityList() ([]*framework.NodeInfo, error) {
+	return me.filter(func(nodeInfo *framework.NodeInfo) bool { return len(nodeInfo.PodsWithRequiredAntiAffinity) > 0 }), nil
+}
+
+func (me *nodeInfoSnapshot) Get(nodeName string) (*framework.NodeInfo, error) {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	if nodeInfo, ok := me.nodeMap[nodeName]; ok {
+		return nodeInfo, nil
+	}
+	return nil, fmt.Errorf("nodeinfo not found for node name %q", nodeName)
+}
+
+// Adds the pod to the NodeInfo of its Spec.NodeName.
+func (me *nodeInfoSnapshot) assumePod(pod *core.Pod) error {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	nodeInfo, ok := me.nodeMap[pod.Spec.NodeName]
+	if !ok {
+		return fmt.Errorf("nodeinfo not found for node name %q", pod.Spec.NodeName)
+	}
+	nodeInfo.AddPod(pod)
+	return nil
+}
+
+// Removes the pod from the NodeInfo of its Spec.NodeName.
+func (me *nodeInfoSnapshot) forgetPod(pod *core.Pod) error {
+	me.mutex.Lock()
+	defer me.mutex.Unlock()
+	nodeInfo, ok := me.nodeMap[pod.Spec.NodeName]
+	if !ok {
+		return fmt.Errorf("nodeinfo not found for node name %q", pod.Spec.NodeName)
+	}
+	return nodeInfo.RemovePod(pod)
+}
+
+func (me *nodeInfoSnapshot) filter(predicate func(*framework.NodeInfo) bool) []*framework.NodeInfo {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+	filtered := make([]*framework.NodeInfo, 0)
+	for _, nodeInfo := range me.nodeInfos {
+		if predicate(nodeInfo) {
+			filtered = append(filtered, nodeInfo)
+		}
+	}
+	return filtered
+}
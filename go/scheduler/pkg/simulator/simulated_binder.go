@@ -0,0 +1,41 @@
+package simulator
+
+import (
+	"context"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// SimulatedBinderPluginName is the name of the Bind plugin that binds pods in the simulated cluster.
+	SimulatedBinderPluginName = "SimulatedBinder"
+)
+
+var (
+	_simulatedBinder *simulatedBinder
+
+	_ framework.Plugin     = _simulatedBinder
+	_ framework.BindPlugin = _simulatedBinder
+)
+
+// simulatedBinder is a Bind plugin that sets the node name of a pod in the simulated cluster,
+// instead of creating a Binding object through the API server.
+//
+// The scheduling framework requires at least one Bind plugin, even though the simulator does not need
+// the API server to bind pods.
+type simulatedBinder struct {
+	cluster *simulatedCluster
+}
+
+func (me *simulatedBinder) Name() string {
+	return SimulatedBinderPluginName
+}
+
+// Bind sets the pod's node name in the simulated cluster.
+func (me *simulatedBinder) Bind(ctx context.Context, state *framework.CycleState, pod *core.Pod, nodeName string) *framework.Status {
+	if err := me.cluster.bindPod(ctx, pod, nodeName); err != nil {
+		return framework.AsStatus(err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// simulatedCluster stores the objects of the simulated cluster in memory.
+//
+// The scheduler framework and the Polaris plugins access the cluster through two different clients:
+// the plugins' informers use a client-go clientset, while the RegionManager and the ServiceGraphManager
+// use a controller-runtime client. Both clients are backed by in-memory object trackers, which are kept in sync.
+type simulatedCluster struct {
+	// The controller-runtime client used by the RegionManager and the ServiceGraphManager.
+	client client.WithWatch
+
+	// The client-go clientset used by the informers of the scheduler framework.
+	clientset kubernetes.Interface
+
+	informerFactory informers.SharedInformerFactory
+}
+
+func newSimulatedCluster(scheme *runtime.Scheme, input *SimulationInput) *simulatedCluster {
+	crObjects := make([]client.Object, 0, len(input.Nodes)+len(input.NetworkLinks)+len(input.ServiceGraphs)+len(input.Pods))
+	clientsetObjects := make([]runtime.Object, 0, len(input.Nodes)+len(input.Pods))
+
+	for _, node := range input.Nodes {
+		crObjects = append(crObjects, node.DeepCopy())
+		clientsetObjects = append(clientsetObjects, node.DeepCopy())
+	}
+	for _, link := range input.NetworkLinks {
+		crObjects = append(crObjects, link.DeepCopy())
+	}
+	for _, svcGraph := range input.ServiceGraphs {
+		crObjects = append(crObjects, svcGraph.DeepCopy())
+	}
+	for _, pod := range input.Pods {
+		crObjects = append(crObjects, pod.DeepCopy())
+		clientsetObjects = append(clientsetObjects, pod.DeepCopy())
+	}
+
+	clientset := clientsetfake.NewSimpleClientset(clientsetObjects...)
+	return &simulatedCluster{
+		client:          fake.NewClientBuilder().WithScheme(scheme).WithObjects(crObjects...).Build(),
+		clientset:       clientset,
+		informerFactory: informers.NewSharedInformerFactory(clientset, 0),
+	}
+}
+
+// Starts the informers and waits until their caches have been synced.
+func (me *simulatedCluster) startInformers(ctx context.Context) error {
+	me.informerFactory.Start(ctx.Done())
+	for informerType, synced := range me.informerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("the informer for %v could not be synced", informerType)
+		}
+	}
+	return nil
+}
+
+// Sets the node name of the pod in both clients.
+func (me *simulatedCluster) bindPod(ctx context.Context, pod *core.Pod, nodeName string) error {
+	var crPod core.Pod
+	if err := me.client.Get(ctx, client.ObjectKeyFromObject(pod), &crPod); err != nil {
+		return err
+	}
+	crPod.Spec.NodeName = nodeName
+	if err := me.client.Update(ctx, &crPod); err != nil {
+		return err
+	}
+
+	clientsetPod, err := me.clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, meta.GetOptions{})
+	if err != nil {
+		return err
+	}
+	clientsetPod.Spec.NodeName = nodeName
+	_, err = me.clientset.CoreV1().Pods(pod.Namespace).Update(ctx, clientsetPod, meta.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,253 @@
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	cluster "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	fogapps "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/controllerutil"
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/services/regionmanager"
+)
+
+const (
+	// The namespace used for ServiceGraphs and pods that do not specify a namespace.
+	DefaultNamespace = "default"
+
+	// The label that contains the hostname of a node.
+	hostnameLabel = "kubernetes.io/hostname"
+)
+
+var (
+	// The allocatable resources of the nodes that are synthesized for the names referenced by NetworkLinks.
+	syntheticNodeAllocatable = core.ResourceList{
+		core.ResourceCPU:    resource.MustParse("4"),
+		core.ResourceMemory: resource.MustParse("8Gi"),
+		core.ResourcePods:   resource.MustParse("110"),
+	}
+)
+
+// SimulationInput contains the objects that make up the simulated cluster and the applications that should be scheduled.
+type SimulationInput struct {
+	// The nodes of the simulated cluster.
+	Nodes []*core.Node
+
+	// The NetworkLinks that connect the nodes.
+	NetworkLinks []*cluster.NetworkLink
+
+	// The ServiceGraphs, whose pods should be scheduled.
+	ServiceGraphs []*fogapps.ServiceGraph
+
+	// Pods that are already running in the cluster (if Spec.NodeName is set) or
+	// that should be scheduled in addition to the pods of the ServiceGraphs.
+	Pods []*core.Pod
+}
+
+// LoadSimulationInput loads the objects from the specified multi-document YAML or JSON files.
+//
+// The files may contain Nodes, NetworkLinks, ServiceGraphs, and Pods.
+// Objects of other kinds are ignored.
+func LoadSimulationInput(files ...string) (*SimulationInput, error) {
+	input := &SimulationInput{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := input.AddObjects(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return input, nil
+}
+
+// AddObjects decodes all objects from the multi-document YAML or JSON reader and adds them to the input.
+func (me *SimulationInput) AddObjects(reader io.Reader) error {
+	decoder := serializer.NewCodecFactory(NewScheme()).UniversalDeserializer()
+	yamlReader := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+
+	for {
+		doc, err := yamlReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			if runtime.IsMissingKind(err) || runtime.IsNotRegisteredError(err) {
+				continue
+			}
+			return err
+		}
+		me.addObject(obj)
+	}
+}
+
+// AddNetworkTopology adds the NetworkLinks described by the topology.
+// The nodes referenced by the topology are added by SynthesizeMissingNodes().
+func (me *SimulationInput) AddNetworkTopology(topology *regionmanager.NetworkTopology) {
+	for _, link := range topology.ToNetworkLinks(DefaultNamespace) {
+		me.NetworkLinks = append(me.NetworkLinks, link.DeepCopy())
+	}
+	for _, nodeName := range topology.Nodes {
+		if !me.hasNode(nodeName) {
+			me.Nodes = append(me.Nodes, newSyntheticNode(nodeName))
+		}
+	}
+}
+
+// SynthesizeMissingNodes adds a node with default resources for every node name
+// that is referenced by a NetworkLink, but that is not part of the Nodes.
+func (me *SimulationInput) SynthesizeMissingNodes() {
+	for _, link := range me.NetworkLinks {
+		for _, nodeName := range []string{link.Spec.NodeA, link.Spec.NodeB} {
+			if !me.hasNode(nodeName) {
+				me.Nodes = append(me.Nodes, newSyntheticNode(nodeName))
+			}
+		}
+	}
+}
+
+// CreatePodsForServiceGraphs creates the pods that the ServiceGraph controller would initially create for the ServiceGraphs.
+//
+// For each ServiceNode, the initial number of replicas is created, using the same pod template as the node's Deployment or StatefulSet.
+// The pods are named "<node>-<index>" and get consecutive creation timestamps in the order of the ServiceGraphs and their nodes.
+func (me *SimulationInput) CreatePodsForServiceGraphs() ([]*core.Pod, error) {
+	pods := make([]*core.Pod, 0)
+	creationTime := meta.Now()
+
+	for _, svcGraph := range me.ServiceGraphs {
+		for i := range svcGraph.Spec.Nodes {
+			node := &svcGraph.Spec.Nodes[i]
+			if node.NodeType == fogapps.UserNode {
+				continue
+			}
+
+			podTemplate, err := controllerutil.CreatePodTemplate(node, svcGraph)
+			if err != nil {
+				return nil, err
+			}
+			replicas := controllerutil.GetInitialReplicas(node)
+			for replica := int32(0); replica < replicas; replica++ {
+				pod := &core.Pod{
+					ObjectMeta: *podTemplate.ObjectMeta.DeepCopy(),
+					Spec:       *podTemplate.Spec.DeepCopy(),
+				}
+				pod.Name = fmt.Sprintf("%s-%d", node.Name, replica)
+				pod.Namespace = svcGraph.Namespace
+				pod.UID = types.UID(fmt.Sprintf("%s.%s.%s", svcGraph.Namespace, svcGraph.Name, pod.Name))
+				pod.CreationTimestamp = creationTime
+				pod.Status.Phase = core.PodPending
+				pods = append(pods, pod)
+
+				creationTime = meta.NewTime(creationTime.Add(1))
+			}
+		}
+	}
+
+	return pods, nil
+}
+
+// Sets the default namespace on all namespaced objects that do not have a namespace set
+// and sorts the nodes by name to make the simulation deterministic.
+func (me *SimulationInput) setDefaults() {
+	for _, link := range me.NetworkLinks {
+		setDefaultNamespace(&link.ObjectMeta)
+	}
+	for _, svcGraph := range me.ServiceGraphs {
+		setDefaultNamespace(&svcGraph.ObjectMeta)
+	}
+	for _, pod := range me.Pods {
+		setDefaultNamespace(&pod.ObjectMeta)
+		if pod.UID == "" {
+			pod.UID = types.UID(fmt.Sprintf("%s.%s", pod.Namespace, pod.Name))
+		}
+	}
+	sort.Slice(me.Nodes, func(i, j int) bool { return me.Nodes[i].Name < me.Nodes[j].Name })
+}
+
+func (me *SimulationInput) deepCopy() *SimulationInput {
+	inputCopy := &SimulationInput{
+		Nodes:         make([]*core.Node, len(me.Nodes)),
+		NetworkLinks:  make([]*cluster.NetworkLink, len(me.NetworkLinks)),
+		ServiceGraphs: make([]*fogapps.ServiceGraph, len(me.ServiceGraphs)),
+		Pods:          make([]*core.Pod, len(me.Pods)),
+	}
+	for i, node := range me.Nodes {
+		inputCopy.Nodes[i] = node.DeepCopy()
+	}
+	for i, link := range me.NetworkLinks {
+		inputCopy.NetworkLinks[i] = link.DeepCopy()
+	}
+	for i, svcGraph := range me.ServiceGraphs {
+		inputCopy.ServiceGraphs[i] = svcGraph.DeepCopy()
+	}
+	for i, pod := range me.Pods {
+		inputCopy.Pods[i] = pod.DeepCopy()
+	}
+	return inputCopy
+}
+
+func (me *SimulationInput) addObject(obj runtime.Object) {
+	switch typedObj := obj.(type) {
+	case *core.Node:
+		me.Nodes = append(me.Nodes, typedObj)
+	case *cluster.NetworkLink:
+		me.NetworkLinks = append(me.NetworkLinks, typedObj)
+	case *fogapps.ServiceGraph:
+		me.ServiceGraphs = append(me.ServiceGraphs, typedObj)
+	case *core.Pod:
+		me.Pods = append(me.Pods, typedObj)
+	}
+}
+
+func (me *SimulationInput) hasNode(name string) bool {
+	for _, node := range me.Nodes {
+		if node.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func newSyntheticNode(name string) *core.Node {
+	return &core.Node{
+		ObjectMeta: meta.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				hostnameLabel: name,
+			},
+		},
+		Status: core.NodeStatus{
+			Capacity:    syntheticNodeAllocatable.DeepCopy(),
+			Allocatable: syntheticNodeAllocatable.DeepCopy(),
+			Conditions: []core.NodeCondition{
+				{Type: core.NodeReady, Status: core.ConditionTrue},
+			},
+		},
+	}
+}
+
+func setDefaultNamespace(objMeta *meta.ObjectMeta) {
+	if objMeta.Namespace == "" {
+		objMeta.Namespace = DefaultNamespace
+	}
+}
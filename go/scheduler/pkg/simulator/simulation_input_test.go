@@ -0,0 +1,146 @@
+package simulator
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+)
+
+const (
+	exampleInputFile     = "../../manifests/examples/simulation-input.yaml"
+	defaultConfigFile    = "../../manifests/polaris-scheduler/default-polaris-scheduler-config.yaml"
+	exampleSvcGraphName  = "sensor-app"
+	exampleSvcGraphNodes = 2
+)
+
+func TestLoadSimulationInput(t *testing.T) {
+	input, err := LoadSimulationInput(exampleInputFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(input.NetworkLinks) != 3 || len(input.ServiceGraphs) != 1 || len(input.Nodes) != 0 {
+		t.Fatalf("expected 3 NetworkLinks, 1 ServiceGraph, and 0 Nodes, but got %d, %d, and %d",
+			len(input.NetworkLinks), len(input.ServiceGraphs), len(input.Nodes))
+	}
+	if len(input.ServiceGraphs[0].Spec.Nodes) != exampleSvcGraphNodes {
+		t.Errorf("expected %d ServiceGraphNodes, but got %d", exampleSvcGraphNodes, len(input.ServiceGraphs[0].Spec.Nodes))
+	}
+
+	input.SynthesizeMissingNodes()
+	input.setDefaults()
+	expectedNodes := []string{"cloud-1", "edge-1", "edge-2"}
+	if len(input.Nodes) != len(expectedNodes) {
+		t.Fatalf("expected %d synthesized Nodes, but got %d", len(expectedNodes), len(input.Nodes))
+	}
+	for i, node := range input.Nodes {
+		if node.Name != expectedNodes[i] {
+			t.Errorf("expected node %d to be %s, but got %s", i, expectedNodes[i], node.Name)
+		}
+		if node.Status.Allocatable.Cpu().IsZero() {
+			t.Errorf("expected node %s to have allocatable CPU", node.Name)
+		}
+	}
+	for _, link := range input.NetworkLinks {
+		if link.Namespace != DefaultNamespace {
+			t.Errorf("expected NetworkLink %s to be in the namespace %s, but got %s", link.Name, DefaultNamespace, link.Namespace)
+		}
+	}
+}
+
+func TestCreatePodsForServiceGraphs(t *testing.T) {
+	input, err := LoadSimulationInput(exampleInputFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pods, err := input.CreatePodsForServiceGraphs()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedPods := []struct {
+		name    string
+		svcNode string
+	}{
+		{name: "collector-0", svcNode: "collector"},
+		{name: "collector-1", svcNode: "collector"},
+		{name: "analyzer-0", svcNode: "analyzer"},
+	}
+	if len(pods) != len(expectedPods) {
+		t.Fatalf("expected %d pods, but got %d", len(expectedPods), len(pods))
+	}
+	for i, expected := range expectedPods {
+		pod := pods[i]
+		if pod.Name != expected.name || pod.Namespace != "demo" {
+			t.Errorf("expected pod demo.%s, but got %s.%s", expected.name, pod.Namespace, pod.Name)
+		}
+		if svcGraph, _ := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph); svcGraph != exampleSvcGraphName {
+			t.Errorf("%s: expected the ServiceGraph label %s, but got %s", pod.Name, exampleSvcGraphName, svcGraph)
+		}
+		if svcNode, _ := kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode); svcNode != expected.svcNode {
+			t.Errorf("%s: expected the ServiceGraphNode label %s, but got %s", pod.Name, expected.svcNode, svcNode)
+		}
+		if len(pod.Spec.Containers) != 1 || pod.UID == "" {
+			t.Errorf("%s: expected one container and a UID", pod.Name)
+		}
+		if i > 0 && !pods[i-1].CreationTimestamp.Before(&pod.CreationTimestamp) {
+			t.Errorf("%s: expected the creation timestamp to be after the one of the previous pod", pod.Name)
+		}
+	}
+}
+
+func TestLoadPluginConfig(t *testing.T) {
+	pluginConfig, err := LoadPluginConfig(defaultConfigFile, DefaultSchedulerName)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pluginNames := make(map[string]bool)
+	for _, pc := range pluginConfig {
+		if pc.Args == nil {
+			t.Errorf("expected the args of the %s plugin to be set", pc.Name)
+		}
+		pluginNames[pc.Name] = true
+	}
+	for _, name := range []string{"ServiceGraph", "AtomicDeployment", "PodsPerNode", "NetworkQoS"} {
+		if !pluginNames[name] {
+			t.Errorf("expected the args of the %s plugin, but got %v", name, pluginConfig)
+		}
+	}
+
+	if _, err := LoadPluginConfig(defaultConfigFile, "unknown-scheduler"); err == nil {
+		t.Errorf("expected an error for an unknown schedulerName")
+	}
+}
+
+func TestNewSortedNodeScores(t *testing.T) {
+	input := &SimulationInput{}
+	for _, name := range []string{"node-a", "node-b", "node-c"} {
+		input.Nodes = append(input.Nodes, newSyntheticNode(name))
+	}
+	pluginScores := framework.PluginToNodeScores{
+		"PluginA": {{Name: "node-a", Score: 10}, {Name: "node-b", Score: 50}, {Name: "node-c", Score: 30}},
+		"PluginB": {{Name: "node-a", Score: 40}, {Name: "node-b", Score: 10}, {Name: "node-c", Score: 0}},
+	}
+
+	nodeScores := newSortedNodeScores(input.Nodes, pluginScores)
+
+	expected := []struct {
+		name  string
+		total int64
+	}{
+		{name: "node-b", total: 60},
+		{name: "node-a", total: 50},
+		{name: "node-c", total: 30},
+	}
+	for i := range expected {
+		if nodeScores[i].NodeName != expected[i].name || nodeScores[i].TotalScore != expected[i].total {
+			t.Errorf("expected %s with a total score of %d at position %d, but got %s with %d",
+				expected[i].name, expected[i].total, i, nodeScores[i].NodeName, nodeScores[i].TotalScore)
+		}
+	}
+	if nodeScores[0].PluginScores["PluginA"] != 50 || nodeScores[0].PluginScores["PluginB"] != 10 {
+		t.Errorf("expected the plugin scores of node-b to be 50 and 10, but got %v", nodeScores[0].PluginScores)
+	}
+}
@@ -0,0 +1,87 @@
+package simulator
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/yaml"
+)
+
+// PlacementResult describes the outcome of the scheduling of a pod.
+type PlacementResult string
+
+const (
+	// The pod has been bound to a node.
+	PodScheduled PlacementResult = "Scheduled"
+
+	// No node passed the Filter phase or a PreFilter plugin found the pod to be unschedulable.
+	PodUnschedulable PlacementResult = "Unschedulable"
+
+	// A node had been selected for the pod, but a Reserve or Permit plugin rejected the pod or
+	// the pod was still waiting for permission when the simulation ended.
+	PodRejected PlacementResult = "Rejected"
+
+	// A plugin returned an error.
+	PodSchedulingError PlacementResult = "Error"
+)
+
+// SimulationReport contains the results of a simulation.
+type SimulationReport struct {
+	// The name of the scheduler profile used for the simulation.
+	SchedulerName string `json:"schedulerName"`
+
+	// The placements of the pods in the order, in which they were scheduled.
+	Placements []*PodPlacement `json:"placements"`
+}
+
+// PodPlacement describes the outcome of the scheduling of a single pod.
+type PodPlacement struct {
+	// The namespace of the pod.
+	Namespace string `json:"namespace"`
+
+	// The name of the pod.
+	Name string `json:"name"`
+
+	// The name of the ServiceGraph that the pod belongs to.
+	ServiceGraph string `json:"serviceGraph,omitempty"`
+
+	// The name of the ServiceGraphNode that the pod belongs to.
+	ServiceGraphNode string `json:"serviceGraphNode,omitempty"`
+
+	// The outcome of the scheduling.
+	Result PlacementResult `json:"result"`
+
+	// The node that was selected for the pod.
+	// This is also set if the pod was rejected after a node had been selected.
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Describes why the pod could not be scheduled.
+	Message string `json:"message,omitempty"`
+
+	// Maps the names of the nodes that did not pass the Filter phase to the reason.
+	FilteredNodes map[string]string `json:"filteredNodes,omitempty"`
+
+	// The scores of the nodes that passed the Filter phase, sorted by their total score in descending order.
+	NodeScores []*NodeScores `json:"nodeScores,omitempty"`
+}
+
+// NodeScores contains the scores that a node received for a pod.
+type NodeScores struct {
+	// The name of the node.
+	NodeName string `json:"nodeName"`
+
+	// The sum of the weighted scores of all Score plugins.
+	TotalScore int64 `json:"totalScore"`
+
+	// Maps the name of each Score plugin to the normalized score multiplied by the plugin's weight.
+	PluginScores map[string]int64 `json:"pluginScores"`
+}
+
+// ToYAML returns the YAML representation of the report.
+func (me *SimulationReport) ToYAML() ([]byte, error) {
+	return yaml.Marshal(me)
+}
+
+// ToJSON returns the indented JSON representation of the report.
+func (me *SimulationReport) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(me, "", "  ")
+}
@@ -0,0 +1,56 @@
+package simulator
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+
+	clusterv1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/cluster/v1"
+	fogappsv1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/fogapps/v1"
+	slov1 "k8s.rainbow-h2020.eu/rainbow/orchestration/apis/slo/v1"
+)
+
+// Simulator runs the Polaris scheduler plugins against an in-memory cluster, without requiring a Kubernetes API server.
+//
+// The simulated cluster consists of the nodes, NetworkLinks, ServiceGraphs, and pods of a SimulationInput.
+// For each ServiceGraph, the Simulator creates the pods that the ServiceGraph controller would create and schedules them
+// one by one, using a scheduling framework instance configured with a scheduler profile.
+// Like the kube-scheduler, it sorts the pods using the QueueSort plugin and runs the PreFilter, Filter, PostFilter, PreScore, Score,
+// Reserve, and Permit plugins for each pod. Pods that are waiting for permission remain in the waiting state until another pod
+// allows them or until all pods have been processed, in which case they are rejected.
+//
+// Node selection is deterministic: if multiple nodes have the highest total score, the node with the lowest name is selected.
+//
+// Each Simulator creates its own RegionManager, ServiceGraphManager, and BandwidthLedger for the simulated cluster,
+// so multiple Simulators may be used in the same process.
+type Simulator interface {
+
+	// Run schedules all pods of the SimulationInput that have not been assigned to a node yet and returns the report.
+	// Run may only be called once.
+	Run(ctx context.Context) (*SimulationReport, error)
+}
+
+// SimulatorOptions configures a Simulator.
+type SimulatorOptions struct {
+	// The scheduler profile, which determines the plugins that are run.
+	// If this is nil, NewDefaultProfile() is used.
+	Profile *config.KubeSchedulerProfile
+}
+
+// NewSimulator creates a new Simulator for the specified input.
+func NewSimulator(input *SimulationInput, options *SimulatorOptions) (Simulator, error) {
+	return newSimulatorImpl(input, options)
+}
+
+// NewScheme creates a Scheme with the Kubernetes types and the Polaris CRDs.
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	utilruntime.Must(fogappsv1.AddToScheme(scheme))
+	utilruntime.Must(slov1.AddToScheme(scheme))
+	return scheme
+}
@@ -0,0 +1,321 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"k8s.rainbow-h2020.eu/rainbow/orchestration/pkg/kubeutil"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
+)
+
+const (
+	// The name used when rejecting waiting pods at the end of the simulation.
+	simulatorName = "Simulator"
+)
+
+var (
+	_simulatorImpl *simulatorImpl
+
+	_ Simulator = _simulatorImpl
+)
+
+type simulatorImpl struct {
+	cluster  *simulatedCluster
+	services *pluginservices.PluginServices
+	snapshot *nodeInfoSnapshot
+	fwk      framework.Framework
+
+	// The pods that need to be scheduled.
+	pendingPods []*core.Pod
+
+	// The pods that have been assigned a node, but which are waiting for permission by a Permit plugin.
+	waitingPods []*assumedPodInfo
+
+	report *SimulationReport
+}
+
+// Stores a pod that has been assumed on a node, along with its scheduling cycle.
+type assumedPodInfo struct {
+	pod       *core.Pod
+	state     *framework.CycleState
+	placement *PodPlacement
+}
+
+func newSimulatorImpl(input *SimulationInput, options *SimulatorOptions) (*simulatorImpl, error) {
+	input = input.deepCopy()
+	input.setDefaults()
+
+	svcGraphPods, err := input.CreatePodsForServiceGraphs()
+	if err != nil {
+		return nil, err
+	}
+	input.Pods = append(input.Pods, svcGraphPods...)
+
+	profile := NewDefaultProfile()
+	if options != nil && options.Profile != nil {
+		profile = options.Profile
+	}
+
+	cluster := newSimulatedCluster(NewScheme(), input)
+	services, err := pluginservices.NewPluginServicesWithClient(cluster.client)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := newNodeInfoSnapshot(input.Nodes, input.Pods)
+	fwk, err := frameworkruntime.NewFramework(
+		newRegistry(cluster, services),
+		profile,
+		frameworkruntime.WithClientSet(cluster.clientset),
+		frameworkruntime.WithInformerFactory(cluster.informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	if err != nil {
+		services.Stop()
+		return nil, err
+	}
+
+	pendingPods := make([]*core.Pod, 0, len(input.Pods))
+	for _, pod := range input.Pods {
+		if pod.Spec.NodeName == "" {
+			pendingPods = append(pendingPods, pod)
+		}
+	}
+
+	return &simulatorImpl{
+		cluster:     cluster,
+		services:    services,
+		snapshot:    snapshot,
+		fwk:         fwk,
+		pendingPods: pendingPods,
+		waitingPods: make([]*assumedPodInfo, 0),
+		report: &SimulationReport{
+			SchedulerName: profile.SchedulerName,
+			Placements:    make([]*PodPlacement, 0, len(pendingPods)),
+		},
+	}, nil
+}
+
+func (me *simulatorImpl) Run(ctx context.Context) (*SimulationReport, error) {
+	defer me.services.Stop()
+	if err := me.cluster.startInformers(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, pod := range me.sortPendingPods() {
+		me.schedulePod(ctx, pod)
+	}
+	me.finishWaitingPods(ctx)
+
+	return me.report, nil
+}
+
+// Sorts the pending pods using the QueueSort plugin.
+func (me *simulatorImpl) sortPendingPods() []*core.Pod {
+	queuedPods := make([]*framework.QueuedPodInfo, len(me.pendingPods))
+	for i, pod := range me.pendingPods {
+		queuedPods[i] = &framework.QueuedPodInfo{
+			PodInfo:                 framework.NewPodInfo(pod),
+			Timestamp:               pod.CreationTimestamp.Time,
+			Attempts:                1,
+			InitialAttemptTimestamp: pod.CreationTimestamp.Time,
+		}
+	}
+
+	less := me.fwk.QueueSortFunc()
+	sort.SliceStable(queuedPods, func(i, j int) bool { return less(queuedPods[i], queuedPods[j]) })
+
+	sortedPods := make([]*core.Pod, len(queuedPods))
+	for i, queuedPod := range queuedPods {
+		sortedPods[i] = queuedPod.Pod
+	}
+	return sortedPods
+}
+
+// Runs the scheduling cycle for the pod and, if the pod does not need to wait for permission, its binding cycle.
+func (me *simulatorImpl) schedulePod(ctx context.Context, pod *core.Pod) {
+	placement := newPodPlacement(pod)
+	me.report.Placements = append(me.report.Placements, placement)
+	state := framework.NewCycleState()
+
+	if status := me.fwk.RunPreFilterPlugins(ctx, state, pod); !status.IsSuccess() {
+		setPlacementFailed(placement, PodUnschedulable, status)
+		return
+	}
+
+	feasibleNodes, filteredNodes, status := me.findFeasibleNodes(ctx, state, pod)
+	if !status.IsSuccess() {
+		setPlacementFailed(placement, PodSchedulingError, status)
+		return
+	}
+	placement.FilteredNodes = make(map[string]string, len(filteredNodes))
+	for nodeName, status := range filteredNodes {
+		placement.FilteredNodes[nodeName] = status.Message()
+	}
+	if len(feasibleNodes) == 0 {
+		me.fwk.RunPostFilterPlugins(ctx, state, pod, filteredNodes)
+		placement.Result = PodUnschedulable
+		placement.Message = fmt.Sprintf("0/%d nodes are available", len(filteredNodes))
+		return
+	}
+
+	if status := me.fwk.RunPreScorePlugins(ctx, state, pod, feasibleNodes); !status.IsSuccess() {
+		setPlacementFailed(placement, PodSchedulingError, status)
+		return
+	}
+	scores, status := me.fwk.RunScorePlugins(ctx, state, pod, feasibleNodes)
+	if !status.IsSuccess() {
+		setPlacementFailed(placement, PodSchedulingError, status)
+		return
+	}
+	placement.NodeScores = newSortedNodeScores(feasibleNodes, scores)
+	placement.NodeName = placement.NodeScores[0].NodeName
+
+	assumedPod := &assumedPodInfo{
+		pod:       pod.DeepCopy(),
+		state:     state,
+		placement: placement,
+	}
+	assumedPod.pod.Spec.NodeName = placement.NodeName
+	if err := me.snapshot.assumePod(assumedPod.pod); err != nil {
+		setPlacementFailed(placement, PodSchedulingError, framework.AsStatus(err))
+		return
+	}
+
+	if status := me.fwk.RunReservePluginsReserve(ctx, state, assumedPod.pod, placement.NodeName); !status.IsSuccess() {
+		me.rejectAssumedPod(ctx, assumedPod, status)
+		return
+	}
+
+	status = me.fwk.RunPermitPlugins(ctx, state, assumedPod.pod, placement.NodeName)
+	if status.Code() == framework.Wait {
+		me.waitingPods = append(me.waitingPods, assumedPod)
+		return
+	}
+	if !status.IsSuccess() {
+		me.rejectAssumedPod(ctx, assumedPod, status)
+		return
+	}
+	me.bindAssumedPod(ctx, assumedPod)
+}
+
+// Runs the Filter plugins on all nodes and returns the nodes that passed and the statuses of the nodes that did not pass.
+// The returned status is only unsuccessful if a Filter plugin returned an error.
+func (me *simulatorImpl) findFeasibleNodes(ctx context.Context, state *framework.CycleState, pod *core.Pod) ([]*core.Node, framework.NodeToStatusMap, *framework.Status) {
+	nodeInfos, err := me.snapshot.NodeInfos().List()
+	if err != nil {
+		return nil, nil, framework.AsStatus(err)
+	}
+
+	feasibleNodes := make([]*core.Node, 0, len(nodeInfos))
+	filteredNodes := make(framework.NodeToStatusMap)
+	for _, nodeInfo := range nodeInfos {
+		status := me.fwk.RunFilterPlugins(ctx, state, pod, nodeInfo).Merge()
+		switch {
+		case status.IsSuccess():
+			feasibleNodes = append(feasibleNodes, nodeInfo.Node())
+		case status.IsUnschedulable():
+			filteredNodes[nodeInfo.Node().Name] = status
+		default:
+			return nil, nil, status
+		}
+	}
+	return feasibleNodes, filteredNodes, nil
+}
+
+// Rejects all pods that are still waiting for permission and runs the binding cycle for all waiting pods.
+func (me *simulatorImpl) finishWaitingPods(ctx context.Context) {
+	me.fwk.IterateOverWaitingPods(func(wp framework.WaitingPod) {
+		if len(wp.GetPendingPlugins()) > 0 {
+			wp.Reject(simulatorName, fmt.Sprintf("the simulation ended while waiting for permission from %v", wp.GetPendingPlugins()))
+		}
+	})
+
+	for _, assumedPod := range me.waitingPods {
+		if status := me.fwk.WaitOnPermit(ctx, assumedPod.pod); !status.IsSuccess() {
+			me.rejectAssumedPod(ctx, assumedPod, status)
+			continue
+		}
+		me.bindAssumedPod(ctx, assumedPod)
+	}
+	me.waitingPods = nil
+}
+
+// Runs the PreBind, Bind, and PostBind plugins for the pod.
+func (me *simulatorImpl) bindAssumedPod(ctx context.Context, assumedPod *assumedPodInfo) {
+	nodeName := assumedPod.placement.NodeName
+	if status := me.fwk.RunPreBindPlugins(ctx, assumedPod.state, assumedPod.pod, nodeName); !status.IsSuccess() {
+		me.rejectAssumedPod(ctx, assumedPod, status)
+		return
+	}
+	if status := me.fwk.RunBindPlugins(ctx, assumedPod.state, assumedPod.pod, nodeName); !status.IsSuccess() {
+		me.rejectAssumedPod(ctx, assumedPod, status)
+		return
+	}
+	me.fwk.RunPostBindPlugins(ctx, assumedPod.state, assumedPod.pod, nodeName)
+	assumedPod.placement.Result = PodScheduled
+}
+
+// Runs the Unreserve plugins for the pod and removes it from the snapshot.
+func (me *simulatorImpl) rejectAssumedPod(ctx context.Context, assumedPod *assumedPodInfo, status *framework.Status) {
+	me.fwk.RunReservePluginsUnreserve(ctx, assumedPod.state, assumedPod.pod, assumedPod.placement.NodeName)
+	if err := me.snapshot.forgetPod(assumedPod.pod); err != nil {
+		status = framework.AsStatus(fmt.Errorf("%s, %w", status.Message(), err))
+	}
+	setPlacementFailed(assumedPod.placement, PodRejected, status)
+}
+
+func newPodPlacement(pod *core.Pod) *PodPlacement {
+	placement := &PodPlacement{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+	}
+	placement.ServiceGraph, _ = kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraph)
+	placement.ServiceGraphNode, _ = kubeutil.GetLabel(pod, kubeutil.LabelRefServiceGraphNode)
+	return placement
+}
+
+// Sets the result and the message of the placement.
+// If the status is an error, the result is always PodSchedulingError.
+func setPlacementFailed(placement *PodPlacement, result PlacementResult, status *framework.Status) {
+	placement.Result = result
+	if status.Code() == framework.Error {
+		placement.Result = PodSchedulingError
+	}
+	placement.Message = status.Message()
+	if failedPlugin := status.FailedPlugin(); failedPlugin != "" {
+		placement.Message = fmt.Sprintf("%s: %s", failedPlugin, placement.Message)
+	}
+}
+
+// Sums up the weighted scores of each node and sorts the nodes by their total score in descending order.
+// Nodes with the same total score are sorted by their names.
+func newSortedNodeScores(nodes []*core.Node, pluginScores framework.PluginToNodeScores) []*NodeScores {
+	nodeScores := make([]*NodeScores, len(nodes))
+	for i, node := range nodes {
+		nodeScores[i] = &NodeScores{
+			NodeName:     node.Name,
+			PluginScores: make(map[string]int64, len(pluginScores)),
+		}
+	}
+	for pluginName, scores := range pluginScores {
+		for i := range scores {
+			nodeScores[i].PluginScores[pluginName] = scores[i].Score
+			nodeScores[i].TotalScore += scores[i].Score
+		}
+	}
+
+	sort.Slice(nodeScores, func(i, j int) bool {
+		if nodeScores[i].TotalScore != nodeScores[j].TotalScore {
+			return nodeScores[i].TotalScore > nodeScores[j].TotalScore
+		}
+		return nodeScores[i].NodeName < nodeScores[j].NodeName
+	})
+	return nodeScores
+}
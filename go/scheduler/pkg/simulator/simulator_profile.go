@@ -0,0 +1,117 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"sigs.k8s.io/yaml"
+
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/pluginservices"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/atomicdeployment"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/networkqos"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/nodecost"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/podspernode"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/registry"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/servicegraph"
+	"polaris-slo-cloud.github.io/polaris-scheduler/v1/scheduler/pkg/schedulerplugins/workloadtype"
+)
+
+const (
+	// DefaultSchedulerName is the name of the default scheduler profile.
+	DefaultSchedulerName = "polaris-scheduler"
+)
+
+// NewDefaultProfile creates a scheduler profile that enables the Polaris plugins in the same way as
+// manifests/polaris-scheduler/default-polaris-scheduler-config.yaml.
+//
+// The default kube-scheduler plugins are not part of the profile, because the simulation focuses on the Polaris plugins.
+// The SimulatedBinder is used as the only Bind plugin.
+// Preemption is not simulated, so the ServiceGraphPreemption PostFilter plugin is not part of the profile.
+func NewDefaultProfile() *config.KubeSchedulerProfile {
+	return &config.KubeSchedulerProfile{
+		SchedulerName: DefaultSchedulerName,
+		Plugins: &config.Plugins{
+			QueueSort:  newPluginSet(servicegraph.PluginName),
+			PreFilter:  newPluginSet(servicegraph.PluginName, networkqos.PluginName, atomicdeployment.PluginName),
+			Filter:     newPluginSet(networkqos.PluginName),
+			PostFilter: newPluginSet(servicegraph.PluginName),
+			PreScore:   newPluginSet(networkqos.PluginName, podspernode.PluginName, nodecost.PluginName, workloadtype.PluginName),
+			Score: config.PluginSet{
+				Enabled: []config.Plugin{
+					{Name: networkqos.PluginName, Weight: 10},
+					{Name: podspernode.PluginName, Weight: 1},
+					{Name: nodecost.PluginName, Weight: 1},
+					{Name: workloadtype.PluginName, Weight: 1},
+				},
+			},
+			Reserve: newPluginSet(servicegraph.PluginName, networkqos.PluginName, atomicdeployment.PluginName),
+			Permit:  newPluginSet(atomicdeployment.PluginName, servicegraph.PluginName),
+			PreBind: newPluginSet(networkqos.PluginName),
+			Bind:    newPluginSet(SimulatedBinderPluginName),
+		},
+	}
+}
+
+// LoadPluginConfig loads the pluginConfig of the profile with the specified schedulerName
+// from a KubeSchedulerConfiguration YAML file, such as manifests/polaris-scheduler/default-polaris-scheduler-config.yaml.
+//
+// Only the plugin args are loaded; the enabled plugins are determined by the profile used for the simulation.
+func LoadPluginConfig(file string, schedulerName string) ([]config.PluginConfig, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedulerConfig struct {
+		Profiles []struct {
+			SchedulerName string `json:"schedulerName"`
+			PluginConfig  []struct {
+				Name string          `json:"name"`
+				Args json.RawMessage `json:"args"`
+			} `json:"pluginConfig"`
+		} `json:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &schedulerConfig); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	for _, profile := range schedulerConfig.Profiles {
+		if profile.SchedulerName != schedulerName {
+			continue
+		}
+		pluginConfig := make([]config.PluginConfig, len(profile.PluginConfig))
+		for i, pc := range profile.PluginConfig {
+			pluginConfig[i] = config.PluginConfig{
+				Name: pc.Name,
+				Args: &runtime.Unknown{Raw: pc.Args, ContentType: runtime.ContentTypeJSON},
+			}
+		}
+		return pluginConfig, nil
+	}
+	return nil, fmt.Errorf("%s: there is no profile with the schedulerName %s", file, schedulerName)
+}
+
+// Creates the registry with the Polaris plugins, which use the specified services, and the SimulatedBinder,
+// which binds pods in the specified cluster.
+func newRegistry(cluster *simulatedCluster, services *pluginservices.PluginServices) frameworkruntime.Registry {
+	reg := registry.NewPolarisRegistry(services)
+	reg[SimulatedBinderPluginName] = func(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		return &simulatedBinder{cluster: cluster}, nil
+	}
+	return reg
+}
+
+func newPluginSet(pluginNames ...string) config.PluginSet {
+	pluginSet := config.PluginSet{
+		Enabled: make([]config.Plugin, len(pluginNames)),
+	}
+	for i, name := range pluginNames {
+		pluginSet.Enabled[i] = config.Plugin{Name: name}
+	}
+	return pluginSet
+}
@@ -0,0 +1,64 @@
+package simulator
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// Runs the example input through the simulator with the default profile, like the polaris-simulator CLI does,
+// and compares the report with the golden file.
+func TestRunExampleInput(t *testing.T) {
+	input, err := LoadSimulationInput(exampleInputFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	input.SynthesizeMissingNodes()
+	sim, err := NewSimulator(input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	report, err := sim.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(report.Placements) != 3 {
+		t.Fatalf("expected 3 placements, but got %d", len(report.Placements))
+	}
+	for _, placement := range report.Placements {
+		if placement.Result != PodScheduled || placement.NodeName == "" {
+			t.Errorf("expected pod %s to be scheduled, but got %s (%s)", placement.Name, placement.Result, placement.Message)
+		}
+		for _, nodeScores := range placement.NodeScores {
+			for _, plugin := range []string{"NetworkQoS", "NodeCost", "PodsPerNode", "WorkloadType"} {
+				if _, ok := nodeScores.PluginScores[plugin]; !ok {
+					t.Errorf("expected a %s score for node %s of pod %s", plugin, nodeScores.NodeName, placement.Name)
+				}
+			}
+		}
+	}
+
+	output, err := report.ToYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	goldenFile := filepath.Join("testdata", "example-report.golden")
+	if *updateGolden {
+		if err := os.WriteFile(goldenFile, output, 0644); err != nil {
+			t.Fatalf("could not update %s: %s", goldenFile, err)
+		}
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("could not read %s: %s", goldenFile, err)
+	}
+	if string(output) != string(expected) {
+		t.Errorf("the report does not match %s (run the test with -update to update it):\n%s", goldenFile, output)
+	}
+}